@@ -0,0 +1,110 @@
+// Package actor provides a mailbox, the inbox of an actor: many producers
+// send messages that a single goroutine handles one at a time.
+package actor
+
+import (
+	"context"
+	"github.com/dk-open/ring"
+	"sync"
+	"sync/atomic"
+)
+
+// Option configures a mailbox created by Mailbox.
+type Option[T any] func(*options[T])
+
+type options[T any] struct {
+	onPanic func(msg T, recovered any)
+}
+
+// WithOnPanic receives the message and the recovered value of every panic of
+// the handler. The message is skipped either way and the mailbox keeps going.
+func WithOnPanic[T any](f func(msg T, recovered any)) Option[T] {
+	return func(o *options[T]) {
+		o.onPanic = f
+	}
+}
+
+// Actor is a running mailbox.
+type Actor[T any] struct {
+	d       ring.IDisruptor[T]
+	handler ring.ReaderCallback[T]
+	mu      sync.Mutex
+	closed  bool
+	// paused is closed by Resume, nil unless the mailbox is suspended
+	paused atomic.Pointer[chan struct{}]
+}
+
+// Mailbox starts a single goroutine handling the messages sent to the mailbox
+// in order. Capacity must be a power of two, senders block on a full mailbox.
+// The goroutine parks while the mailbox is empty, idle mailboxes cost no CPU.
+func Mailbox[T any](capacity uint64, handler func(msg T), opts ...Option[T]) (*Actor[T], error) {
+	var o options[T]
+	for _, opt := range opts {
+		opt(&o)
+	}
+	a := &Actor[T]{handler: ring.WithPanicHandler(handler, o.onPanic)}
+	d, err := ring.NewDisruptor[T](context.Background(),
+		ring.WithCapacity(capacity),
+		ring.WithBlockingWait(),
+		ring.WithReaders(a.receive),
+	)
+	if err != nil {
+		return nil, err
+	}
+	a.d = d
+	return a, nil
+}
+
+func (a *Actor[T]) receive(msg T) {
+	if ch := a.paused.Load(); ch != nil {
+		<-*ch
+	}
+	a.handler(msg)
+}
+
+// Send delivers msg, waiting while the mailbox is full. It fails with
+// ring.ErrClosed once the mailbox is closed.
+func (a *Actor[T]) Send(msg T) error {
+	return a.d.MustEnqueue(msg)
+}
+
+// TrySend delivers msg unless the mailbox is full or closed.
+func (a *Actor[T]) TrySend(msg T) bool {
+	return a.d.Enqueue(msg)
+}
+
+// Suspend stops handling messages after the current one. Messages keep
+// queueing up, senders block once the mailbox is full.
+func (a *Actor[T]) Suspend() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if !a.closed && a.paused.Load() == nil {
+		ch := make(chan struct{})
+		a.paused.Store(&ch)
+	}
+}
+
+// Resume continues handling messages of a suspended mailbox.
+func (a *Actor[T]) Resume() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if ch := a.paused.Swap(nil); ch != nil {
+		close(*ch)
+	}
+}
+
+// Stats reports the mailbox's queue as disruptor stats.
+func (a *Actor[T]) Stats() ring.Stats {
+	return a.d.Stats()
+}
+
+// Close stops accepting messages, resumes a suspended mailbox and waits until
+// the queued messages are handled. If ctx is done first the remaining
+// messages are abandoned and ctx.Err() is returned.
+func (a *Actor[T]) Close(ctx context.Context) error {
+	a.mu.Lock()
+	a.closed = true
+	a.mu.Unlock()
+	a.Resume()
+	return a.d.Close(ctx)
+}
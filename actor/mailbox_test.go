@@ -0,0 +1,101 @@
+package actor
+
+import (
+	"context"
+	"errors"
+	"github.com/dk-open/ring"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMailbox_HandlesInOrder(t *testing.T) {
+	const senders, n = 4, 500
+	last := make([]int, senders)
+	var recovered atomic.Int64
+	var handled int
+	a, err := Mailbox(16, func(msg [2]int) {
+		if msg[1] == 7 {
+			panic("boom")
+		}
+		if msg[1] <= last[msg[0]] && msg[1] != 0 {
+			t.Errorf("Sender %d: %d handled after %d", msg[0], msg[1], last[msg[0]])
+		}
+		last[msg[0]] = msg[1]
+		handled++
+	}, WithOnPanic(func(msg [2]int, v any) { recovered.Add(1) }))
+	if err != nil {
+		t.Fatalf("Failed to create mailbox: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for s := 0; s < senders; s++ {
+		wg.Add(1)
+		go func(s int) {
+			defer wg.Done()
+			for i := 0; i < n; i++ {
+				if err := a.Send([2]int{s, i}); err != nil {
+					t.Errorf("Send failed: %v", err)
+					return
+				}
+			}
+		}(s)
+	}
+	wg.Wait()
+	if err := a.Close(context.Background()); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if handled != senders*(n-1) || recovered.Load() != senders {
+		t.Errorf("Expected %d handled and %d recovered messages, got %d and %d", senders*(n-1), senders, handled, recovered.Load())
+	}
+	if err := a.Send([2]int{}); !errors.Is(err, ring.ErrClosed) {
+		t.Errorf("Expected ErrClosed, got %v", err)
+	}
+}
+
+func TestMailbox_SuspendResume(t *testing.T) {
+	var handled atomic.Int64
+	a, err := Mailbox(4, func(int) { handled.Add(1) })
+	if err != nil {
+		t.Fatalf("Failed to create mailbox: %v", err)
+	}
+	a.Suspend()
+	for i := 0; i < 4; i++ {
+		if !a.TrySend(i) {
+			t.Fatalf("TrySend %d failed", i)
+		}
+	}
+	time.Sleep(5 * time.Millisecond)
+	if n := handled.Load(); n != 0 {
+		t.Fatalf("Expected a suspended mailbox to hold its messages, handled %d", n)
+	}
+	if a.TrySend(4) {
+		t.Fatal("Expected a full mailbox to reject the message")
+	}
+
+	a.Resume()
+	if err := a.Close(context.Background()); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if n := handled.Load(); n != 4 {
+		t.Errorf("Expected 4 handled messages, got %d", n)
+	}
+}
+
+func TestMailbox_CloseDeadline(t *testing.T) {
+	block := make(chan struct{})
+	defer close(block)
+	a, err := Mailbox(4, func(int) { <-block })
+	if err != nil {
+		t.Fatalf("Failed to create mailbox: %v", err)
+	}
+	a.Send(1)
+	a.Send(2)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := a.Close(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got %v", err)
+	}
+}
@@ -0,0 +1,97 @@
+package audit
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/dk-open/ring"
+	"hash/fnv"
+	"io"
+	"time"
+)
+
+// RecordSize is the encoded size of a Record: sequence, unix nanoseconds and
+// payload hash, each stored as big-endian 64-bit value.
+const RecordSize = 24
+
+var (
+	ErrGap       = errors.New("audit: sequence gap")
+	ErrReordered = errors.New("audit: sequence reordered")
+)
+
+// Codec encodes a payload into the bytes that are hashed for the trail.
+type Codec[T any] func(value T) ([]byte, error)
+
+type Record struct {
+	Seq       uint64
+	Timestamp time.Time
+	Hash      uint64
+}
+
+func (r Record) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, RecordSize)
+	r.put(buf)
+	return buf, nil
+}
+
+func (r *Record) UnmarshalBinary(data []byte) error {
+	if len(data) < RecordSize {
+		return io.ErrUnexpectedEOF
+	}
+	r.Seq = binary.BigEndian.Uint64(data[0:])
+	r.Timestamp = time.Unix(0, int64(binary.BigEndian.Uint64(data[8:])))
+	r.Hash = binary.BigEndian.Uint64(data[16:])
+	return nil
+}
+
+func (r Record) put(buf []byte) {
+	binary.BigEndian.PutUint64(buf[0:], r.Seq)
+	binary.BigEndian.PutUint64(buf[8:], uint64(r.Timestamp.UnixNano()))
+	binary.BigEndian.PutUint64(buf[16:], r.Hash)
+}
+
+// Reader returns a sequenced disruptor reader that writes a Record stamped
+// with the event's ring sequence for every event it sees, so a skipped or
+// reordered event shows up when the trail is verified, and trails written by
+// readers of different stages or processes can be compared sequence by
+// sequence. A payload that fails to encode or a failed write leaves a gap.
+func Reader[T any](w io.Writer, codec Codec[T]) ring.SequencedReaderCallback[T] {
+	var buf [RecordSize]byte
+	return func(seq uint64, value T, endOfBatch bool) {
+		payload, err := codec(value)
+		if err != nil {
+			return
+		}
+		h := fnv.New64a()
+		_, _ = h.Write(payload)
+		Record{Seq: seq, Timestamp: time.Now(), Hash: h.Sum64()}.put(buf[:])
+		_, _ = w.Write(buf[:])
+	}
+}
+
+// Verify reads the trail and checks that sequences are contiguous and ordered
+// from the first record on, so a trail of a reader attached mid-stream
+// verifies. It returns the number of records read.
+func Verify(r io.Reader) (uint64, error) {
+	var n, next uint64
+	var rec Record
+	var buf [RecordSize]byte
+	for {
+		if _, err := io.ReadFull(r, buf[:]); err != nil {
+			if errors.Is(err, io.EOF) {
+				return n, nil
+			}
+			return n, err
+		}
+		_ = rec.UnmarshalBinary(buf[:])
+		switch {
+		case n == 0:
+		case rec.Seq < next:
+			return n, fmt.Errorf("%w: got %d after %d", ErrReordered, rec.Seq, next-1)
+		case rec.Seq > next:
+			return n, fmt.Errorf("%w: expected %d, got %d", ErrGap, next, rec.Seq)
+		}
+		next = rec.Seq + 1
+		n++
+	}
+}
@@ -0,0 +1,115 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/dk-open/ring"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func encodeInt(value int) ([]byte, error) {
+	return []byte(strconv.Itoa(value)), nil
+}
+
+func TestReader_Trail(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	const n = 100
+	var trail bytes.Buffer
+	var wg sync.WaitGroup
+	wg.Add(n)
+
+	audit := Reader[int](&trail, encodeInt)
+	d, err := ring.Disruptor[int](ctx, 16)
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	d.HandleWithSequenced(func(seq uint64, value int, endOfBatch bool) {
+		audit(seq, value, endOfBatch)
+		wg.Done()
+	})
+	for i := 0; i < n; i++ {
+		if err := d.MustEnqueue(i); err != nil {
+			t.Fatalf("MustEnqueue failed: %v", err)
+		}
+	}
+	wg.Wait()
+
+	if trail.Len() != n*RecordSize {
+		t.Fatalf("Expected %d bytes, got %d", n*RecordSize, trail.Len())
+	}
+	var first Record
+	if err := first.UnmarshalBinary(trail.Bytes()); err != nil {
+		t.Fatalf("Failed to decode record: %v", err)
+	}
+	if time.Since(first.Timestamp) > time.Minute {
+		t.Errorf("Unexpected timestamp %v", first.Timestamp)
+	}
+	if got, err := Verify(&trail); err != nil || got != n {
+		t.Errorf("Expected %d verified records, got %d: %v", n, got, err)
+	}
+}
+
+func TestVerify_Gap(t *testing.T) {
+	var trail bytes.Buffer
+	failing := func(value int) ([]byte, error) {
+		if value == 2 {
+			return nil, errors.New("encode failed")
+		}
+		return encodeInt(value)
+	}
+	audit := Reader[int](&trail, failing)
+	for i := 0; i < 5; i++ {
+		audit(uint64(i), i, false)
+	}
+	if got, err := Verify(&trail); !errors.Is(err, ErrGap) || got != 2 {
+		t.Errorf("Expected gap after 2 records, got %d: %v", got, err)
+	}
+}
+
+func TestVerify_Reordered(t *testing.T) {
+	var trail bytes.Buffer
+	for _, seq := range []uint64{0, 1, 0} {
+		buf, _ := Record{Seq: seq, Timestamp: time.Now()}.MarshalBinary()
+		trail.Write(buf)
+	}
+	if _, err := Verify(&trail); !errors.Is(err, ErrReordered) {
+		t.Errorf("Expected ErrReordered, got %v", err)
+	}
+}
+
+func TestReader_MidStream(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var trail bytes.Buffer
+	d, err := ring.Disruptor[int](ctx, 8)
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	// Without readers the first events are not audited, the trail starts at 5
+	for i := 0; i < 5; i++ {
+		d.Enqueue(i)
+	}
+	d.HandleWithSequenced(Reader[int](&trail, encodeInt))
+	for i := 5; i < 20; i++ {
+		if err := d.MustEnqueue(i); err != nil {
+			t.Fatalf("MustEnqueue failed: %v", err)
+		}
+	}
+	if err := d.Close(context.Background()); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	var first Record
+	if err := first.UnmarshalBinary(trail.Bytes()); err != nil || first.Seq != 5 {
+		t.Fatalf("Expected the trail to start at 5, got %d: %v", first.Seq, err)
+	}
+	if got, err := Verify(&trail); err != nil || got != 15 {
+		t.Errorf("Expected 15 verified records, got %d: %v", got, err)
+	}
+}
@@ -0,0 +1,99 @@
+package ring
+
+import (
+	"fmt"
+	"github.com/dk-open/ring/pad"
+	"math/bits"
+	"unsafe"
+)
+
+var ErrBuffer = fmt.Errorf("buffer does not match the capacity or element type")
+
+// WithBufferProvider allocates the ring's slots with provide instead of make,
+// e.g. to place them in hugepage-backed, arena or mmap memory or to align
+// them. provide receives the number of slots, the capacity or a multiple of
+// it with WithSlotPadding, and must return at least that many. The ring uses
+// the first n of them.
+func WithBufferProvider[T any](provide func(n uint64) []T) Option {
+	return func(o *options) {
+		o.buffer = provide
+	}
+}
+
+// WithBuffer uses the pre-allocated buf as the ring's slots.
+func WithBuffer[T any](buf []T) Option {
+	return WithBufferProvider(func(uint64) []T { return buf })
+}
+
+// WithQueueBufferProvider is WithBufferProvider for queues. A sharded queue
+// calls provide once per shard.
+func WithQueueBufferProvider[T any](provide func(n uint64) []T) QueueOption {
+	return func(o *queueOptions) {
+		o.buffer = provide
+	}
+}
+
+// newBuffer allocates capacity slots with the type-erased provider, or with
+// make if there is none.
+func newBuffer[T any](provider any, capacity uint64) ([]T, error) {
+	if provider == nil {
+		return make([]T, capacity), nil
+	}
+	provide, ok := provider.(func(n uint64) []T)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", ErrBuffer, provider)
+	}
+	buf := provide(capacity)
+	if uint64(len(buf)) < capacity {
+		return nil, fmt.Errorf("%w: %d slots for capacity %d", ErrBuffer, len(buf), capacity)
+	}
+	return buf[:capacity:capacity], nil
+}
+
+// WithSlotPadding spreads the slots of an element type smaller than a cache
+// line so that every event sits on a line of its own. Producers writing
+// neighbouring slots and readers copying them out then no longer invalidate
+// each other's lines, at the price of a buffer up to CacheLineSize/size times
+// larger and fewer events per line fetched by a reader draining a batch.
+func WithSlotPadding() Option {
+	return func(o *options) {
+		o.slotPadding = true
+	}
+}
+
+// WithQueueSlotPadding is WithSlotPadding for queues.
+func WithQueueSlotPadding() QueueOption {
+	return func(o *queueOptions) {
+		o.slotPadding = true
+	}
+}
+
+// slotShift returns the log2 of the stride between the slots of a padded
+// buffer of T, zero if T fills a cache line on its own.
+func slotShift[T any](padded bool) uint {
+	size := unsafe.Sizeof(*new(T))
+	if !padded || size == 0 || size >= pad.CacheLineSize {
+		return 0
+	}
+	per := (pad.CacheLineSize + size - 1) / size
+	return uint(bits.Len64(uint64(per - 1)))
+}
+
+// newSlots allocates the buffer of a ring of capacity slots spaced 1<<shift
+// elements apart.
+func newSlots[T any](provider any, capacity uint64, shift uint) ([]T, error) {
+	if capacity > MaxCapacity>>shift {
+		return nil, fmt.Errorf("%w: %d padded slots", ErrCapacity, capacity)
+	}
+	return newBuffer[T](provider, capacity<<shift)
+}
+
+// slot returns the slot of the doubled sequence seq.
+func (d *disruptor[T]) slot(seq uint64) *T {
+	return &d.buffer[(seq>>1&d.capMask)<<d.slotShift]
+}
+
+// slot returns the slot of the doubled sequence seq.
+func (q *queue[T]) slot(seq uint64) *T {
+	return &q.buffer[(seq>>1&q.capMask)<<q.slotShift]
+}
@@ -0,0 +1,152 @@
+package ring
+
+import (
+	"context"
+	"errors"
+	"github.com/dk-open/ring/pad"
+	"testing"
+)
+
+func TestNewDisruptor_BufferProvider(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	backing := make([]int, 32)
+	var requested uint64
+	d, err := NewDisruptor[int](ctx, WithCapacity(16), WithBufferProvider(func(n uint64) []int {
+		requested = n
+		return backing
+	}))
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	if requested != 16 {
+		t.Errorf("Expected the provider to be asked for 16 slots, got %d", requested)
+	}
+	for i := 1; i <= 3; i++ {
+		if err := d.MustEnqueue(i * 10); err != nil {
+			t.Fatalf("MustEnqueue failed: %v", err)
+		}
+	}
+	if backing[0] != 10 || backing[2] != 30 {
+		t.Errorf("Expected events in the provided slots, got %v", backing[:3])
+	}
+}
+
+func TestNewDisruptor_Buffer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	for name, opt := range map[string]Option{
+		"short":     WithBuffer(make([]int, 8)),
+		"mismatch":  WithBuffer(make([]string, 16)),
+		"undersize": WithBufferProvider(func(n uint64) []int { return nil }),
+	} {
+		if _, err := NewDisruptor[int](ctx, WithCapacity(16), opt); !errors.Is(err, ErrBuffer) {
+			t.Errorf("%s: expected ErrBuffer, got %v", name, err)
+		}
+	}
+	if _, err := NewDisruptor[int](ctx, WithCapacity(16), WithBuffer(make([]int, 16))); err != nil {
+		t.Errorf("Expected an exactly sized buffer to work, got %v", err)
+	}
+}
+
+func TestQueue_BufferProvider(t *testing.T) {
+	var calls int
+	q, err := ShardedQueue[int](4, 8, RoundRobin[int](), WithQueueBufferProvider(func(n uint64) []int {
+		calls++
+		return make([]int, n)
+	}))
+	if err != nil {
+		t.Fatalf("Failed to create queue: %v", err)
+	}
+	if calls != 4 {
+		t.Errorf("Expected a buffer per shard, got %d", calls)
+	}
+	if !q.Enqueue(1) {
+		t.Error("Enqueue failed")
+	}
+	if _, err := Queue[int](8, WithQueueBufferProvider(func(n uint64) []string { return make([]string, n) })); !errors.Is(err, ErrBuffer) {
+		t.Errorf("Expected ErrBuffer, got %v", err)
+	}
+}
+
+func TestNewDisruptor_SlotPadding(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var requested uint64
+	var backing []int64
+	d, err := NewDisruptor[int64](ctx, WithCapacity(4), WithSlotPadding(), WithBufferProvider(func(n uint64) []int64 {
+		requested = n
+		backing = make([]int64, n)
+		return backing
+	}))
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	stride := uint64(pad.CacheLineSize / 8)
+	if requested != 4*stride {
+		t.Fatalf("Expected %d padded slots, got %d", 4*stride, requested)
+	}
+	got := make(chan int64, 8)
+	d.HandleWith(func(v int64) { got <- v })
+	for i := int64(1); i <= 6; i++ {
+		if err := d.MustEnqueue(i); err != nil {
+			t.Fatalf("MustEnqueue failed: %v", err)
+		}
+		if v := <-got; v != i {
+			t.Fatalf("Expected %d, got %d", i, v)
+		}
+	}
+	if backing[0] != 5 || backing[stride] != 6 || backing[1] != 0 {
+		t.Errorf("Expected events a cache line apart, got %v", backing[:stride+1])
+	}
+}
+
+func TestSlotShift(t *testing.T) {
+	type line [pad.CacheLineSize]byte
+	if shift := slotShift[int64](false); shift != 0 {
+		t.Errorf("Expected no padding without the option, got shift %d", shift)
+	}
+	if shift := slotShift[struct{}](true); shift != 0 {
+		t.Errorf("Expected no padding for empty events, got shift %d", shift)
+	}
+	if shift := slotShift[line](true); shift != 0 {
+		t.Errorf("Expected no padding for line-sized events, got shift %d", shift)
+	}
+	check := func(name string, size, shift uint) {
+		// The smallest power of two stride that spans a cache line
+		if stride := uint(1) << shift; size*stride < pad.CacheLineSize || size*stride/2 >= pad.CacheLineSize {
+			t.Errorf("%s: stride %d of %d-byte slots does not fit a %d-byte line", name, stride, size, pad.CacheLineSize)
+		}
+	}
+	check("byte", 1, slotShift[byte](true))
+	check("int64", 8, slotShift[int64](true))
+	check("odd", 24, slotShift[[24]byte](true))
+}
+
+func TestQueue_SlotPadding(t *testing.T) {
+	q, err := Queue[int32](8, WithQueueSlotPadding())
+	if err != nil {
+		t.Fatalf("Failed to create queue: %v", err)
+	}
+	for round := 0; round < 3; round++ {
+		for i := int32(0); i < 8; i++ {
+			if !q.Enqueue(i) {
+				t.Fatalf("Enqueue %d failed", i)
+			}
+		}
+		if q.Enqueue(8) {
+			t.Fatal("Expected a full queue to reject")
+		}
+		for i := int32(0); i < 8; i++ {
+			if v, ok := q.Dequeue(); !ok || v != i {
+				t.Fatalf("Expected %d, got %d %v", i, v, ok)
+			}
+		}
+	}
+	if _, err := Queue[int32](MaxCapacity, WithQueueSlotPadding()); !errors.Is(err, ErrCapacity) {
+		t.Errorf("Expected ErrCapacity for an oversized padded buffer, got %v", err)
+	}
+}
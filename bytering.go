@@ -0,0 +1,200 @@
+package ring
+
+import (
+	"errors"
+	"github.com/dk-open/ring/pad"
+	"io"
+	"sync/atomic"
+)
+
+var ErrPeekTooLarge = errors.New("peek exceeds the ring capacity")
+
+// IByteRing is a single-producer single-consumer byte buffer, e.g. between a
+// network reader goroutine and a parser. Write blocks while the ring is full,
+// Read while it is empty.
+type IByteRing interface {
+	io.ReadWriteCloser
+	// Peek returns the next n bytes without consuming them, waiting until
+	// they are written. The slice aliases the ring when the bytes are
+	// contiguous and is valid until the next Read, Peek or Discard. It
+	// returns fewer bytes with io.EOF if the writer closed first.
+	Peek(n int) ([]byte, error)
+	// Discard consumes the next n bytes, waiting until they are written.
+	Discard(n int) (int, error)
+	// Buffered returns the number of bytes written and not yet consumed.
+	Buffered() int
+	// ReserveWrite returns a contiguous slab of the ring of at most n bytes
+	// for the writer to fill, waiting until the reader freed space. It is
+	// shorter than n where the ring wraps. The filled bytes are published by
+	// CommitWrite.
+	ReserveWrite(n int) ([]byte, error)
+	// CommitWrite publishes the first n bytes of the reserved slab.
+	CommitWrite(n int)
+	// PeekRead returns the contiguous readable region of the ring, waiting
+	// until a byte is written. The bytes are consumed by CommitRead.
+	PeekRead() ([]byte, error)
+	// CommitRead consumes n bytes of the region returned by PeekRead.
+	CommitRead(n int)
+}
+
+type byteRing struct {
+	buf     []byte
+	cap     uint64
+	capMask uint64
+	head    pad.AtomicUint64 // bytes written
+	tail    pad.AtomicUint64 // bytes consumed
+	closed  atomic.Bool
+	// owned by the reader
+	scratch []byte
+}
+
+// ByteRing creates a byte ring of the given capacity, a power of two.
+func ByteRing(capacity uint64) (IByteRing, error) {
+	if err := validCapacity(capacity); err != nil {
+		return nil, err
+	}
+	return &byteRing{buf: make([]byte, capacity), cap: capacity, capMask: capacity - 1}, nil
+}
+
+// Write copies p into the ring, waiting for the reader while it is full. It
+// fails with ErrClosed after Close.
+func (b *byteRing) Write(p []byte) (int, error) {
+	written := 0
+	var attempt uint64
+	for written < len(p) {
+		if b.closed.Load() {
+			return written, ErrClosed
+		}
+		head := b.head.Load()
+		free := b.cap - (head - b.tail.Load())
+		if free == 0 {
+			readerYield(attempt)
+			attempt++
+			continue
+		}
+		attempt = 0
+		n := b.copyIn(head, p[written:min(len(p), written+int(free))])
+		b.head.Store(head + uint64(n))
+		written += n
+	}
+	return written, nil
+}
+
+// copyIn copies p to the ring at head in at most two contiguous parts.
+func (b *byteRing) copyIn(head uint64, p []byte) int {
+	offset := head & b.capMask
+	n := copy(b.buf[offset:], p)
+	return n + copy(b.buf, p[n:])
+}
+
+// Read copies the buffered bytes into p, waiting until at least one byte is
+// written. It returns io.EOF once the writer closed and the ring is drained.
+func (b *byteRing) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	avail, err := b.wait(1)
+	if avail == 0 {
+		return 0, err
+	}
+	tail := b.tail.Load()
+	p = p[:min(len(p), avail)]
+	offset := tail & b.capMask
+	n := copy(p, b.buf[offset:])
+	n += copy(p[n:], b.buf)
+	b.tail.Store(tail + uint64(n))
+	return n, nil
+}
+
+func (b *byteRing) Peek(n int) ([]byte, error) {
+	if uint64(n) > b.cap {
+		return nil, ErrPeekTooLarge
+	}
+	avail, err := b.wait(n)
+	n = min(n, avail)
+	offset := b.tail.Load() & b.capMask
+	if offset+uint64(n) <= b.cap {
+		return b.buf[offset : offset+uint64(n)], err
+	}
+	// Wrapped bytes are copied to keep the result contiguous
+	if cap(b.scratch) < n {
+		b.scratch = make([]byte, n)
+	}
+	res := b.scratch[:n]
+	copy(res[copy(res, b.buf[offset:]):], b.buf)
+	return res, err
+}
+
+func (b *byteRing) Discard(n int) (int, error) {
+	discarded := 0
+	for discarded < n {
+		avail, err := b.wait(1)
+		if avail == 0 {
+			return discarded, err
+		}
+		step := min(n-discarded, avail)
+		b.tail.Store(b.tail.Load() + uint64(step))
+		discarded += step
+	}
+	return discarded, nil
+}
+
+func (b *byteRing) Buffered() int {
+	return int(b.head.Load() - b.tail.Load())
+}
+
+// Close ends the stream: pending writes fail and Read returns io.EOF after the
+// buffered bytes.
+func (b *byteRing) Close() error {
+	b.closed.Store(true)
+	return nil
+}
+
+func (b *byteRing) ReserveWrite(n int) ([]byte, error) {
+	var attempt uint64
+	for {
+		if b.closed.Load() {
+			return nil, ErrClosed
+		}
+		head := b.head.Load()
+		if free := b.cap - (head - b.tail.Load()); free > 0 || n == 0 {
+			offset := head & b.capMask
+			return b.buf[offset : offset+min(uint64(n), free, b.cap-offset)], nil
+		}
+		readerYield(attempt)
+		attempt++
+	}
+}
+
+func (b *byteRing) CommitWrite(n int) {
+	b.head.Store(b.head.Load() + uint64(n))
+}
+
+func (b *byteRing) PeekRead() ([]byte, error) {
+	avail, err := b.wait(1)
+	offset := b.tail.Load() & b.capMask
+	return b.buf[offset : offset+min(uint64(avail), b.cap-offset)], err
+}
+
+func (b *byteRing) CommitRead(n int) {
+	b.tail.Store(b.tail.Load() + uint64(n))
+}
+
+// wait blocks until n bytes are buffered or the writer closed, and returns the
+// buffered bytes with io.EOF if fewer than n are left.
+func (b *byteRing) wait(n int) (int, error) {
+	var attempt uint64
+	for {
+		// Closed is read first, so bytes written before Close are seen
+		closed := b.closed.Load()
+		avail := b.Buffered()
+		if avail >= n {
+			return avail, nil
+		}
+		if closed {
+			return avail, io.EOF
+		}
+		readerYield(attempt)
+		attempt++
+	}
+}
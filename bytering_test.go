@@ -0,0 +1,124 @@
+package ring
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"testing"
+)
+
+func TestByteRing_Stream(t *testing.T) {
+	r, err := ByteRing(64)
+	if err != nil {
+		t.Fatalf("Failed to create byte ring: %v", err)
+	}
+	data := make([]byte, 10000)
+	for i := range data {
+		data[i] = byte(rand.IntN(256))
+	}
+	go func() {
+		for rest := data; len(rest) > 0; {
+			n := min(len(rest), 1+rand.IntN(100))
+			if _, err := r.Write(rest[:n]); err != nil {
+				t.Errorf("Write failed: %v", err)
+				return
+			}
+			rest = rest[n:]
+		}
+		r.Close()
+	}()
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Fatalf("Expected %d bytes read back, got %d differing bytes", len(data), len(got))
+	}
+	if _, err := r.Write([]byte{1}); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected ErrClosed, got %v", err)
+	}
+}
+
+func TestByteRing_PeekDiscard(t *testing.T) {
+	r, err := ByteRing(8)
+	if err != nil {
+		t.Fatalf("Failed to create byte ring: %v", err)
+	}
+	if _, err := r.Write([]byte("abcdef")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if n, err := r.Discard(4); n != 4 || err != nil {
+		t.Fatalf("Expected 4 discarded bytes, got %d: %v", n, err)
+	}
+	// The next frame wraps around the end of the ring
+	if _, err := r.Write([]byte("ghij")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if p, err := r.Peek(5); err != nil || string(p) != "efghi" {
+		t.Fatalf("Expected efghi, got %q: %v", p, err)
+	}
+	if p, err := r.Peek(2); err != nil || string(p) != "ef" {
+		t.Fatalf("Expected ef, got %q: %v", p, err)
+	}
+	if r.Buffered() != 6 {
+		t.Errorf("Expected 6 buffered bytes, got %d", r.Buffered())
+	}
+	if _, err := r.Peek(9); !errors.Is(err, ErrPeekTooLarge) {
+		t.Errorf("Expected ErrPeekTooLarge, got %v", err)
+	}
+
+	r.Close()
+	if p, err := r.Peek(7); !errors.Is(err, io.EOF) || string(p) != "efghij" {
+		t.Errorf("Expected the rest with io.EOF, got %q: %v", p, err)
+	}
+	if n, err := r.Discard(10); n != 6 || !errors.Is(err, io.EOF) {
+		t.Errorf("Expected 6 discarded bytes and io.EOF, got %d: %v", n, err)
+	}
+}
+
+func TestByteRing_ZeroCopy(t *testing.T) {
+	r, err := ByteRing(16)
+	if err != nil {
+		t.Fatalf("Failed to create byte ring: %v", err)
+	}
+	data := make([]byte, 5000)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	go func() {
+		for rest := data; len(rest) > 0; {
+			slab, err := r.ReserveWrite(len(rest))
+			if err != nil {
+				t.Errorf("ReserveWrite failed: %v", err)
+				return
+			}
+			n := copy(slab, rest)
+			r.CommitWrite(n)
+			rest = rest[n:]
+		}
+		r.Close()
+	}()
+
+	var got []byte
+	for {
+		region, err := r.PeekRead()
+		if len(region) > 16 {
+			t.Fatalf("Region of %d bytes exceeds the ring", len(region))
+		}
+		got = append(got, region...)
+		r.CommitRead(len(region))
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("PeekRead failed: %v", err)
+		}
+	}
+	if !bytes.Equal(got, data) {
+		t.Fatalf("Expected %d bytes read back, got %d", len(data), len(got))
+	}
+	if _, err := r.ReserveWrite(1); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected ErrClosed, got %v", err)
+	}
+}
@@ -0,0 +1,44 @@
+package ring
+
+import (
+	"math"
+	"math/bits"
+	"time"
+)
+
+// MaxCapacity is the largest capacity a ring can be created with. It keeps the
+// doubled sequences clear of overflow and the buffer addressable on 32-bit
+// platforms.
+const MaxCapacity = 1 << (bits.UintSize - 2)
+
+// validCapacity reports ErrCapacity unless capacity is a power of two no
+// larger than MaxCapacity.
+func validCapacity(capacity uint64) error {
+	if capacity <= 0 || capacity&(capacity-1) != 0 || capacity > MaxCapacity {
+		return ErrCapacity
+	}
+	return nil
+}
+
+// sequenceLimit is the writer cursor at which a disruptor of the given
+// capacity stops publishing. Sequences are compared by magnitude, so the
+// doubled cursor must not wrap while a reader is a ring behind.
+func sequenceLimit(capacity uint64) uint64 {
+	return (math.MaxUint64 - 4*capacity) &^ 1
+}
+
+// MaxSupportedThroughputDuration returns how long a disruptor of the given
+// capacity can publish eventsPerSecond before its sequences are exhausted and
+// publishing fails with ErrSequenceExhausted. Sequences are 64-bit on every
+// platform, the result is capped at the largest time.Duration.
+func MaxSupportedThroughputDuration(capacity, eventsPerSecond uint64) time.Duration {
+	if eventsPerSecond == 0 {
+		return time.Duration(math.MaxInt64)
+	}
+	events := sequenceLimit(capacity) >> 1
+	seconds := events / eventsPerSecond
+	if seconds > uint64(math.MaxInt64/int64(time.Second)) {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Duration(seconds) * time.Second
+}
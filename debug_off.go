@@ -0,0 +1,5 @@
+//go:build !ringdebug
+
+package ring
+
+const debugChecks = false
@@ -0,0 +1,7 @@
+//go:build ringdebug
+
+package ring
+
+// debugChecks enables contract checks that cost an atomic operation on the
+// hot path, e.g. detecting a second publisher in single-producer mode.
+const debugChecks = true
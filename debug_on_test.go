@@ -0,0 +1,23 @@
+//go:build ringdebug
+
+package ring
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSingleProducer_DetectsSecondPublisher(t *testing.T) {
+	d, err := NewDisruptor[int](context.Background(), WithCapacity(8), WithSingleProducer())
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatal("Expected panic for concurrent publish")
+		}
+	}()
+	// Simulate a second publisher caught in the middle of a publish
+	d.(*disruptor[int]).publishing.Store(true)
+	d.Enqueue(1)
+}
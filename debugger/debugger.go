@@ -0,0 +1,143 @@
+// Package debugger records the recent events of a disruptor so a reader
+// callback can be re-run against the exact payload that triggered a bug,
+// without restarting the pipeline.
+package debugger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/dk-open/ring"
+	"runtime/debug"
+	"sync"
+)
+
+// DefaultSize is the number of events recorded when WithSize is not given.
+const DefaultSize = 1024
+
+var ErrNotRecorded = errors.New("debugger: event not recorded")
+
+// Event is a recorded event with its ring sequence.
+type Event[T any] struct {
+	Seq   uint64
+	Value T
+}
+
+// PanicError is returned by Replay when the callback panicked.
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("debugger: callback panicked: %v", e.Value)
+}
+
+// Option configures a debugger created by Attach.
+type Option func(*options)
+
+type options struct {
+	size int
+}
+
+// WithSize bounds the number of recorded events, older events are evicted.
+func WithSize(n int) Option {
+	return func(o *options) {
+		o.size = n
+	}
+}
+
+// Debugger keeps the most recent events seen by its recording reader. Values
+// are copied as they are, events referencing shared memory show its current
+// state rather than the state at publish time.
+type Debugger[T any] struct {
+	mu     sync.Mutex
+	events []Event[T]
+	next   int
+	full   bool
+	handle ring.ReaderHandle[T]
+}
+
+// Attach starts a reader on d that records every event published from now on.
+// The reader gates producers like any other, Detach removes it.
+func Attach[T any](d ring.IDisruptor[T], opts ...Option) *Debugger[T] {
+	o := options{size: DefaultSize}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	g := &Debugger[T]{events: make([]Event[T], max(o.size, 1))}
+	g.handle = d.HandleWithSequenced(g.record).Handles()[0]
+	return g
+}
+
+func (g *Debugger[T]) record(seq uint64, value T, endOfBatch bool) {
+	if seq == ring.UnknownSequence {
+		return
+	}
+	g.mu.Lock()
+	g.events[g.next] = Event[T]{Seq: seq, Value: value}
+	g.next++
+	if g.next == len(g.events) {
+		g.next, g.full = 0, true
+	}
+	g.mu.Unlock()
+}
+
+// Detach stops recording. Recorded events stay available.
+func (g *Debugger[T]) Detach() {
+	g.handle.Close()
+}
+
+// Events returns the recorded events, oldest first.
+func (g *Debugger[T]) Events() []Event[T] {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if !g.full {
+		return append([]Event[T](nil), g.events[:g.next]...)
+	}
+	res := make([]Event[T], 0, len(g.events))
+	res = append(res, g.events[g.next:]...)
+	return append(res, g.events[:g.next]...)
+}
+
+// Event returns the recorded event with the given sequence.
+func (g *Debugger[T]) Event(seq uint64) (Event[T], bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	n := g.next
+	if g.full {
+		n = len(g.events)
+	}
+	for i := 0; i < n; i++ {
+		if e := g.events[i]; e.Seq == seq {
+			return e, true
+		}
+	}
+	return Event[T]{}, false
+}
+
+// Replay invokes f with the recorded event seq on a separate goroutine and
+// waits until it returns. A panic is recovered and returned as *PanicError. If
+// ctx is done first Replay returns ctx.Err() and leaves f running.
+func (g *Debugger[T]) Replay(ctx context.Context, seq uint64, f ring.ReaderCallback[T]) error {
+	e, ok := g.Event(seq)
+	if !ok {
+		return fmt.Errorf("%w: sequence %d", ErrNotRecorded, seq)
+	}
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if v := recover(); v != nil {
+				done <- &PanicError{Value: v, Stack: debug.Stack()}
+			}
+		}()
+		f(e.Value)
+		done <- nil
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,53 @@
+package debugger
+
+import (
+	"context"
+	"errors"
+	"github.com/dk-open/ring"
+	"testing"
+)
+
+func TestDebugger_Replay(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	d, err := ring.Disruptor[int](ctx, 16)
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	g := Attach(d, WithSize(4))
+	for i := 0; i < 10; i++ {
+		if err := d.MustEnqueue(i * 10); err != nil {
+			t.Fatalf("MustEnqueue failed: %v", err)
+		}
+	}
+	if err := d.Close(context.Background()); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	events := g.Events()
+	if len(events) != 4 || events[0].Seq != 6 || events[3].Value != 90 {
+		t.Fatalf("Expected the last 4 events, got %+v", events)
+	}
+	var got int
+	if err := g.Replay(ctx, 8, func(value int) { got = value }); err != nil || got != 80 {
+		t.Errorf("Expected replay of 80, got %d: %v", got, err)
+	}
+	if err := g.Replay(ctx, 2, func(int) {}); !errors.Is(err, ErrNotRecorded) {
+		t.Errorf("Expected ErrNotRecorded for an evicted event, got %v", err)
+	}
+
+	var perr *PanicError
+	err = g.Replay(ctx, 9, func(value int) { panic(value) })
+	if !errors.As(err, &perr) || perr.Value != 90 || len(perr.Stack) == 0 {
+		t.Errorf("Expected a recovered panic, got %v", err)
+	}
+
+	block := make(chan struct{})
+	defer close(block)
+	cancelled, stop := context.WithCancel(ctx)
+	stop()
+	if err := g.Replay(cancelled, 9, func(int) { <-block }); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
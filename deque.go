@@ -0,0 +1,164 @@
+package ring
+
+import (
+	"fmt"
+	"github.com/dk-open/ring/pad"
+	"sync/atomic"
+)
+
+// maxPackedCapacity keeps positions in the 32 bits they share with a version
+// in a single atomic word.
+const maxPackedCapacity = 1 << 30
+
+// Deque is a bounded double-ended queue for work stealing. A single owner
+// goroutine pushes and pops at the back and may push at the front, any number
+// of stealers take from the front. The owner only contends with stealers for
+// the last item and for PushFront.
+type Deque[T any] struct {
+	// top holds the front position in the low 32 bits and a version in the
+	// high 32 bits. PushFront moves the front back and bumps the version, so a
+	// stealer's CAS fails if the front moved back and forth since it looked.
+	top pad.AtomicUint64
+	// bottom is the position after the back, only stored by the owner.
+	bottom pad.AtomicUint32
+	slots  []dequeSlot[T]
+	mask   uint32
+}
+
+// dequeSlot is busy from the push of an item until it is copied out, so the
+// owner does not overwrite an item a slow stealer has claimed but not read.
+type dequeSlot[T any] struct {
+	busy  atomic.Bool
+	value T
+}
+
+// NewDeque creates a deque of the given capacity, a power of two no larger
+// than 1<<30.
+func NewDeque[T any](capacity uint64) (*Deque[T], error) {
+	if err := validCapacity(capacity); err != nil {
+		return nil, err
+	}
+	if capacity > maxPackedCapacity {
+		return nil, fmt.Errorf("%w: deque of %d items", ErrCapacity, capacity)
+	}
+	return &Deque[T]{
+		slots: make([]dequeSlot[T], capacity),
+		mask:  uint32(capacity - 1),
+	}, nil
+}
+
+// PushBack adds item at the back. It reports false if the deque is full or a
+// stealer is still copying out the item that used the slot. Owner only.
+func (d *Deque[T]) PushBack(item T) bool {
+	b := d.bottom.Load()
+	if b-uint32(d.top.Load()) >= uint32(len(d.slots)) {
+		return false
+	}
+	slot := &d.slots[b&d.mask]
+	if slot.busy.Load() {
+		return false
+	}
+	slot.value = item
+	slot.busy.Store(true)
+	d.bottom.Store(b + 1)
+	return true
+}
+
+// PushFront adds item at the front, where it is the next to be stolen. It
+// reports false like PushBack. Owner only.
+func (d *Deque[T]) PushFront(item T) bool {
+	for {
+		top := d.top.Load()
+		t := uint32(top) - 1
+		if d.bottom.Load()-t > uint32(len(d.slots)) {
+			return false
+		}
+		slot := &d.slots[t&d.mask]
+		if slot.busy.Load() {
+			return false
+		}
+		slot.value = item
+		slot.busy.Store(true)
+		if d.top.CompareAndSwap(top, (top>>32+1)<<32|uint64(t)) {
+			return true
+		}
+		// A stealer moved the front, no one claims the slot before it
+		d.release(slot)
+	}
+}
+
+// PopBack takes the item at the back, the one pushed last. Owner only.
+func (d *Deque[T]) PopBack() (res T, ok bool) {
+	b := d.bottom.Load() - 1
+	// Stealers that see the lowered bottom stop short of b
+	d.bottom.Store(b)
+	top := d.top.Load()
+	t := uint32(top)
+	if int32(b-t) < 0 {
+		d.bottom.Store(b + 1)
+		return res, false
+	}
+	if b != t {
+		return d.take(b), true
+	}
+	// The last item, whoever moves the front owns it
+	ok = d.top.CompareAndSwap(top, top+1)
+	d.bottom.Store(b + 1)
+	if !ok {
+		return res, false
+	}
+	return d.take(b), true
+}
+
+// PopFront steals the item at the front, the oldest one. Any goroutine may
+// call it, it only fails if the deque is empty.
+func (d *Deque[T]) PopFront() (res T, ok bool) {
+	for {
+		switch res, r := d.TryPopFront(); r {
+		case Ok:
+			return res, true
+		case Empty:
+			return res, false
+		}
+	}
+}
+
+// TryPopFront makes one attempt to steal the item at the front. It reports
+// Contended if another goroutine took the front first, so a scheduler can
+// move on to another victim.
+func (d *Deque[T]) TryPopFront() (res T, result Result) {
+	top := d.top.Load()
+	t := uint32(top)
+	if int32(d.bottom.Load()-t) <= 0 {
+		return res, Empty
+	}
+	if !d.top.CompareAndSwap(top, top+1) {
+		return res, Contended
+	}
+	return d.take(t), Ok
+}
+
+// Len returns the number of items, which stealers may change at any time.
+func (d *Deque[T]) Len() int {
+	n := int32(d.bottom.Load() - uint32(d.top.Load()))
+	return int(max(n, 0))
+}
+
+// Cap returns the capacity of the deque.
+func (d *Deque[T]) Cap() int {
+	return len(d.slots)
+}
+
+// take copies out the item at position pos, which the caller has claimed.
+func (d *Deque[T]) take(pos uint32) T {
+	slot := &d.slots[pos&d.mask]
+	res := slot.value
+	d.release(slot)
+	return res
+}
+
+func (d *Deque[T]) release(slot *dequeSlot[T]) {
+	var zero T
+	slot.value = zero
+	slot.busy.Store(false)
+}
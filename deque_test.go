@@ -0,0 +1,198 @@
+package ring
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestDeque_Ends(t *testing.T) {
+	d, err := NewDeque[int](4)
+	if err != nil {
+		t.Fatalf("Failed to create deque: %v", err)
+	}
+	d.PushBack(1)
+	d.PushBack(2)
+	d.PushFront(0)
+	if got := d.Len(); got != 3 {
+		t.Errorf("Expected 3 items, got %d", got)
+	}
+	if v, ok := d.PopBack(); !ok || v != 2 {
+		t.Errorf("Expected PopBack to return 2, got %d (ok=%v)", v, ok)
+	}
+	if v, ok := d.PopFront(); !ok || v != 0 {
+		t.Errorf("Expected PopFront to return 0, got %d (ok=%v)", v, ok)
+	}
+	if v, ok := d.PopBack(); !ok || v != 1 {
+		t.Errorf("Expected PopBack to return 1, got %d (ok=%v)", v, ok)
+	}
+	if _, ok := d.PopBack(); ok {
+		t.Error("Expected PopBack on an empty deque to fail")
+	}
+	if _, r := d.TryPopFront(); r != Empty {
+		t.Errorf("Expected Empty, got %v", r)
+	}
+}
+
+func TestDeque_Full(t *testing.T) {
+	d, err := NewDeque[int](4)
+	if err != nil {
+		t.Fatalf("Failed to create deque: %v", err)
+	}
+	for i := 0; i < 4; i++ {
+		if !d.PushBack(i) {
+			t.Fatalf("Failed to push %d", i)
+		}
+	}
+	if d.PushBack(4) || d.PushFront(-1) {
+		t.Error("Expected pushes to a full deque to fail")
+	}
+	// The ring wraps around at both ends
+	for round := 0; round < 10; round++ {
+		v, _ := d.PopFront()
+		if !d.PushFront(v) {
+			t.Fatalf("Failed to push %d back to the front", v)
+		}
+		d.PopBack()
+		if !d.PushBack(round) {
+			t.Fatalf("Failed to push %d to the back", round)
+		}
+	}
+	if got := d.Len(); got != d.Cap() {
+		t.Errorf("Expected a full deque, got %d items", got)
+	}
+}
+
+func TestNewDeque_Capacity(t *testing.T) {
+	for _, capacity := range []uint64{0, 3, maxPackedCapacity << 1} {
+		if _, err := NewDeque[int](capacity); !errors.Is(err, ErrCapacity) {
+			t.Errorf("Expected ErrCapacity for %d, got %v", capacity, err)
+		}
+	}
+}
+
+func TestDeque_Steal(t *testing.T) {
+	const items = 100000
+	d, err := NewDeque[int](256)
+	if err != nil {
+		t.Fatalf("Failed to create deque: %v", err)
+	}
+	seen := make([]atomic.Int32, items)
+	var done atomic.Bool
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for !done.Load() || d.Len() > 0 {
+				if v, ok := d.PopFront(); ok {
+					seen[v].Add(1)
+				}
+			}
+		}()
+	}
+	for i := 0; i < items; i++ {
+		push := d.PushBack
+		if i%7 == 0 {
+			push = d.PushFront
+		}
+		for !push(i) {
+			if v, ok := d.PopBack(); ok {
+				seen[v].Add(1)
+			}
+		}
+		if i%3 == 0 {
+			if v, ok := d.PopBack(); ok {
+				seen[v].Add(1)
+			}
+		}
+	}
+	done.Store(true)
+	wg.Wait()
+	for v := range seen {
+		if got := seen[v].Load(); got != 1 {
+			t.Fatalf("Expected item %d to be taken once, got %d", v, got)
+		}
+	}
+}
+
+// mutexDeque is the baseline for the deque benchmarks.
+type mutexDeque struct {
+	mu    sync.Mutex
+	items []int
+}
+
+func (m *mutexDeque) PushBack(v int) bool {
+	m.mu.Lock()
+	m.items = append(m.items, v)
+	m.mu.Unlock()
+	return true
+}
+
+func (m *mutexDeque) PopBack() (int, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.items) == 0 {
+		return 0, false
+	}
+	v := m.items[len(m.items)-1]
+	m.items = m.items[:len(m.items)-1]
+	return v, true
+}
+
+func (m *mutexDeque) PopFront() (int, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.items) == 0 {
+		return 0, false
+	}
+	v := m.items[0]
+	m.items = m.items[1:]
+	return v, true
+}
+
+type benchDeque interface {
+	PushBack(v int) bool
+	PopBack() (int, bool)
+	PopFront() (int, bool)
+}
+
+// BenchmarkDeque has the owner push two tasks and pop one per operation while
+// stealers take from the front.
+func BenchmarkDeque(b *testing.B) {
+	for _, stealers := range []int{0, 1, 4} {
+		b.Run(fmt.Sprintf("Deque Stealers: %d", stealers), func(b *testing.B) {
+			d, _ := NewDeque[int](1024)
+			benchmarkDeque(b, d, stealers)
+		})
+		b.Run(fmt.Sprintf("MutexSlice Stealers: %d", stealers), func(b *testing.B) {
+			benchmarkDeque(b, &mutexDeque{}, stealers)
+		})
+	}
+}
+
+func benchmarkDeque(b *testing.B, d benchDeque, stealers int) {
+	var done atomic.Bool
+	var wg sync.WaitGroup
+	for i := 0; i < stealers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for !done.Load() {
+				d.PopFront()
+			}
+		}()
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if !d.PushBack(i) || !d.PushBack(i) {
+			d.PopFront()
+		}
+		d.PopBack()
+	}
+	b.StopTimer()
+	done.Store(true)
+	wg.Wait()
+}
@@ -0,0 +1,32 @@
+package ring
+
+import "github.com/dk-open/ring/pad"
+
+// admission is a ticket queue for producers waiting on a full ring. A producer
+// takes the next ticket and publishes once serving reaches it.
+type admission struct {
+	next    pad.AtomicUint64
+	serving pad.AtomicUint64
+}
+
+// queued reports whether producers are waiting for admission.
+func (d *disruptor[T]) queued() bool {
+	return d.admission != nil && d.admission.next.Load() != d.admission.serving.Load()
+}
+
+// admit publishes item after the producers that queued up before it. A
+// producer leaving on a closed ring keeps its ticket, nothing is admitted
+// after Close anyway.
+func (d *disruptor[T]) admit(item T) error {
+	ticket := d.admission.next.Add(1) - 1
+	var attempt uint64
+	for d.admission.serving.Load() != ticket {
+		if d.closed.Load() {
+			return ErrClosed
+		}
+		readerYield(attempt)
+		attempt++
+	}
+	defer d.admission.serving.Add(1)
+	return d.mustEnqueue(item, false)
+}
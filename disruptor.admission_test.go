@@ -0,0 +1,61 @@
+package ring
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestDisruptor_FairAdmission(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	release := make(chan struct{})
+	var received []int
+	d, err := NewDisruptor[int](ctx, WithCapacity(4), WithFairAdmission(), WithReaders(func(value int) {
+		<-release
+		received = append(received, value)
+	}))
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	for i := 0; i < 4; i++ {
+		if err := d.MustEnqueue(i); err != nil {
+			t.Fatalf("MustEnqueue failed: %v", err)
+		}
+	}
+
+	// Producers queue up on the full ring in order of arrival
+	fd := d.(*disruptor[int])
+	errs := make(chan error, 3)
+	for i := 4; i < 7; i++ {
+		go func(v int) { errs <- d.MustEnqueue(v) }(i)
+		for fd.admission.next.Load() != uint64(i-3) {
+			time.Sleep(time.Millisecond)
+		}
+	}
+	if d.Enqueue(100) {
+		t.Fatal("Expected Enqueue not to pass queued producers")
+	}
+	if n := d.EnqueueBatch([]int{100}); n != 0 {
+		t.Fatal("Expected EnqueueBatch not to pass queued producers")
+	}
+
+	close(release)
+	for i := 0; i < 3; i++ {
+		if err := <-errs; err != nil {
+			t.Fatalf("MustEnqueue failed: %v", err)
+		}
+	}
+	if err := d.Close(context.Background()); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	for i, v := range received {
+		if v != i {
+			t.Fatalf("Expected producers admitted in arrival order, got %v", received)
+		}
+	}
+	if len(received) != 7 {
+		t.Fatalf("Expected 7 events, got %v", received)
+	}
+}
@@ -0,0 +1,151 @@
+package ring
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestDisruptor_MustEnqueueBatch(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		opts []Option
+	}{
+		{name: "multi producer"},
+		{name: "single producer", opts: []Option{WithSingleProducer()}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			const producers, batches, size = 4, 200, 8
+			producerCount := producers
+			if len(tc.opts) > 0 {
+				producerCount = 1
+			}
+			last := make([]int, producerCount)
+			for i := range last {
+				last[i] = -1
+			}
+			var received int
+			d, err := NewDisruptor[[2]int](ctx, append(tc.opts, WithCapacity(16), WithReaders(func(v [2]int) {
+				if v[1] != last[v[0]]+1 {
+					t.Errorf("Producer %d: expected %d, got %d", v[0], last[v[0]]+1, v[1])
+				}
+				last[v[0]] = v[1]
+				received++
+			}))...)
+			if err != nil {
+				t.Fatalf("Failed to create disruptor: %v", err)
+			}
+
+			var wg sync.WaitGroup
+			for p := 0; p < producerCount; p++ {
+				wg.Add(1)
+				go func(p int) {
+					defer wg.Done()
+					batch := make([][2]int, size)
+					for b := 0; b < batches; b++ {
+						for i := range batch {
+							batch[i] = [2]int{p, b*size + i}
+						}
+						if err := d.MustEnqueueBatch(batch); err != nil {
+							t.Errorf("MustEnqueueBatch failed: %v", err)
+							return
+						}
+					}
+				}(p)
+			}
+			wg.Wait()
+			if err := d.Close(context.Background()); err != nil {
+				t.Fatalf("Close failed: %v", err)
+			}
+			if want := producerCount * batches * size; received != want {
+				t.Fatalf("Expected %d events, got %d", want, received)
+			}
+		})
+	}
+}
+
+func TestDisruptor_EnqueueBatch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	release := make(chan struct{})
+	d, err := Disruptor[int](ctx, 8, func(int) { <-release })
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	items := []int{0, 1, 2, 3, 4, 5}
+	if n := d.EnqueueBatch(items); n != 6 {
+		t.Fatalf("Expected 6 published events, got %d", n)
+	}
+	// Only the room left in the ring is claimed
+	if n := d.EnqueueBatch(items); n != 2 {
+		t.Fatalf("Expected 2 published events, got %d", n)
+	}
+	if n := d.EnqueueBatch(items); n != 0 {
+		t.Fatalf("Expected a full ring to reject the batch, got %d", n)
+	}
+	if st := d.Stats(); st.Writer != 8 || st.FailedEnqueues != 1 {
+		t.Errorf("Expected 8 published events and 1 failure, got %+v", st)
+	}
+	close(release)
+}
+
+func BenchmarkDisruptorContendedBatch(b *testing.B) {
+	for _, size := range []int{1, 4, 16} {
+		b.Run(fmt.Sprintf("Batch_%d", size), func(b *testing.B) {
+			ctx, cancel := context.WithCancel(b.Context())
+			defer cancel()
+
+			d, err := Disruptor[int](ctx, 1024, func(int) {})
+			if err != nil {
+				b.Fatalf("Failed to create disruptor: %v", err)
+			}
+			b.ReportAllocs()
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				batch := make([]int, 0, size)
+				for pb.Next() {
+					if batch = append(batch, 1); len(batch) == size {
+						if err := d.MustEnqueueBatch(batch); err != nil {
+							b.Errorf("MustEnqueueBatch failed: %v", err)
+							return
+						}
+						batch = batch[:0]
+					}
+				}
+				_ = d.MustEnqueueBatch(batch)
+			})
+		})
+	}
+}
+
+func TestDisruptor_MustEnqueueBatchDropped(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	release := make(chan struct{})
+	d, err := NewDisruptor[int](ctx,
+		WithCapacity(4),
+		WithSlowConsumerPolicy(DropNewest),
+		WithReaders(func(int) { <-release }),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	err = d.MustEnqueueBatch([]int{0, 1, 2, 3, 4, 5})
+	if !errors.Is(err, ErrDropped) || err.Error() != ErrDropped.Error()+": 2 of 6 events" {
+		t.Errorf("Expected ErrDropped for 2 events, got %v", err)
+	}
+	close(release)
+	if err := d.Close(context.Background()); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if s := d.Stats(); s.Dropped != 2 {
+		t.Errorf("Expected 2 drops, got %+v", s)
+	}
+}
@@ -0,0 +1,34 @@
+package ring
+
+import "context"
+
+// Linked is an event published to a downstream ring together with the
+// sequence of the source event it was derived from.
+type Linked[T any] struct {
+	Source uint64
+	Value  T
+}
+
+// Bridge consumes src and publishes the events derived by f to dst. The source
+// reader does not move past an event before its derived event is published or
+// given up on, f reports false to filter an event out.
+//
+// Derived events carry their source sequence. A consumer of dst that persists
+// the last Source it processed can, after a restart, resume src behind it and
+// drop the duplicates, which gives effectively-once delivery between the rings.
+// Publishing is retried while dst is full. An event is dropped once dst is
+// closed or drops it, or ctx is done; onDrop, if not nil, is called with its
+// source sequence, the source event and the reason, so no event leaves src
+// unnoticed.
+func Bridge[A, B any](ctx context.Context, src IDisruptor[A], dst IDisruptor[Linked[B]], f func(A) (B, bool), onDrop func(seq uint64, value A, err error)) *ReaderGroup[A] {
+	return src.HandleWithSequenced(func(seq uint64, value A, endOfBatch bool) {
+		derived, ok := f(value)
+		if !ok {
+			return
+		}
+		err := forward(ctx, dst, Linked[B]{Source: seq, Value: derived})
+		if err != nil && onDrop != nil {
+			onDrop(seq, value, err)
+		}
+	})
+}
@@ -0,0 +1,92 @@
+package ring
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBridge(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	src, err := Disruptor[int](ctx, 8)
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	dst, err := Disruptor[Linked[int]](ctx, 4)
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+
+	var received, violations atomic.Int64
+	dst.HandleWith(func(l Linked[int]) {
+		// Odd events are filtered out, every derived event keeps its source sequence
+		if l.Source%2 != 0 || l.Value != int(l.Source)*10 {
+			violations.Add(1)
+		}
+		time.Sleep(10 * time.Microsecond)
+		received.Add(1)
+	})
+	Bridge(ctx, src, dst, func(v int) (int, bool) {
+		return v * 10, v%2 == 0
+	}, func(uint64, int, error) { violations.Add(1) })
+
+	const n = 200
+	for i := 0; i < n; i++ {
+		if err := src.MustEnqueue(i); err != nil {
+			t.Fatalf("MustEnqueue failed: %v", err)
+		}
+	}
+	if err := src.Close(context.Background()); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if err := dst.Close(context.Background()); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	if got := received.Load(); got != n/2 {
+		t.Errorf("Expected %d derived events, got %d", n/2, got)
+	}
+	if v := violations.Load(); v != 0 {
+		t.Errorf("Expected derived events to match their source, got %d violations", v)
+	}
+}
+
+func TestBridge_ClosedDestination(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	src, err := Disruptor[int](ctx, 8)
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	dst, err := Disruptor[Linked[int]](ctx, 4)
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	if err := dst.Close(context.Background()); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	var dropped []uint64
+	Bridge(ctx, src, dst, func(v int) (int, bool) { return v, true }, func(seq uint64, v int, err error) {
+		if !errors.Is(err, ErrClosed) {
+			t.Errorf("Expected ErrClosed, got %v", err)
+		}
+		dropped = append(dropped, seq)
+	})
+	for i := 0; i < 3; i++ {
+		src.MustEnqueue(i)
+	}
+	// The reader is not stuck on the closed destination
+	closeCtx, stop := context.WithTimeout(context.Background(), time.Second)
+	defer stop()
+	if err := src.Close(closeCtx); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if len(dropped) != 3 || dropped[0] != 0 || dropped[2] != 2 {
+		t.Errorf("Expected events 0 to 2 to be dropped, got %v", dropped)
+	}
+}
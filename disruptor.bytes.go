@@ -0,0 +1,157 @@
+package ring
+
+import (
+	"context"
+	"fmt"
+	"github.com/dk-open/ring/pad"
+	"unsafe"
+)
+
+// ErrPayloadTooLarge is returned for a payload that can never fit the arena.
+var ErrPayloadTooLarge = fmt.Errorf("payload exceeds the ring capacity")
+
+// Bytes is the payload constraint of the byte-bounded disruptor.
+type Bytes interface {
+	~[]byte | ~string
+}
+
+// IByteDisruptor is a disruptor gated on retained payload bytes. Payloads are
+// copied into an internal arena, so producers may reuse their buffers.
+type IByteDisruptor[T Bytes] interface {
+	Enqueue(item T) bool
+	MustEnqueue(item T) error
+	Close(ctx context.Context) error
+	// Retained returns the number of arena bytes not yet released by every reader.
+	Retained() uint64
+}
+
+type byteRecord struct {
+	start, end uint64
+}
+
+type byteDisruptor[T Bytes] struct {
+	arena   []byte
+	cap     uint64
+	capMask uint64
+	align   uint64
+	// written is the arena offset after the last payload, stored by the
+	// producer only and loaded by Retained from any goroutine.
+	written  pad.AtomicUint64
+	records  *disruptor[byteRecord]
+	consumed []*pad.AtomicUint64
+}
+
+// ByteDisruptor creates a single-producer disruptor whose capacity is expressed
+// in arena bytes rather than slots. Slots bounds the number of in-flight
+// payloads. Both must be powers of two. A payload handed to a reader aliases
+// the arena and is only valid until the callback returns.
+func ByteDisruptor[T Bytes](ctx context.Context, capacity, slots uint64, readers ...ReaderCallback[T]) (IByteDisruptor[T], error) {
+	return AlignedByteDisruptor[T](ctx, capacity, slots, 1, readers...)
+}
+
+// AlignedByteDisruptor creates a byte disruptor that starts every payload at
+// an arena offset aligned to align bytes, a power of two no larger than the
+// capacity. Aligned to pad.CacheLineSize, payloads are copied with aligned
+// stores and a reader never shares a cache line with the payload being
+// written. The alignment padding counts against the capacity.
+func AlignedByteDisruptor[T Bytes](ctx context.Context, capacity, slots, align uint64, readers ...ReaderCallback[T]) (IByteDisruptor[T], error) {
+	if err := validCapacity(capacity); err != nil {
+		return nil, err
+	}
+	if validCapacity(align) != nil || align > capacity {
+		return nil, fmt.Errorf("%w: alignment %d", ErrCapacity, align)
+	}
+	res := &byteDisruptor[T]{
+		arena:   alignedArena(capacity, align),
+		cap:     capacity,
+		capMask: capacity - 1,
+		align:   align,
+	}
+	callbacks := make([]ReaderCallback[byteRecord], 0, len(readers))
+	for _, f := range readers {
+		consumed := &pad.AtomicUint64{}
+		res.consumed = append(res.consumed, consumed)
+		callbacks = append(callbacks, func(rec byteRecord) {
+			start := rec.start & res.capMask
+			end := start + rec.end - rec.start
+			// The payload is capped so a reader appending to it does not
+			// overwrite the next payload
+			f(T(res.arena[start:end:end]))
+			consumed.Store(rec.end)
+		})
+	}
+	records, err := Disruptor[byteRecord](ctx, slots, callbacks...)
+	if err != nil {
+		return nil, err
+	}
+	res.records = records.(*disruptor[byteRecord])
+	return res, nil
+}
+
+func (b *byteDisruptor[T]) Enqueue(item T) bool {
+	n := uint64(len(item))
+	if n > b.cap {
+		return false
+	}
+	written := b.written.Load()
+	start := (written + b.align - 1) &^ (b.align - 1)
+	// A payload never wraps around the arena, the tail of the arena is skipped.
+	if offset := start & b.capMask; offset+n > b.cap {
+		start += b.cap - offset
+	}
+	end := start + n
+	if end-b.released(written) > b.cap {
+		return false
+	}
+	copy(b.arena[start&b.capMask:], item)
+	if !b.records.Enqueue(byteRecord{start: start, end: end}) {
+		return false
+	}
+	b.written.Store(end)
+	return true
+}
+
+func (b *byteDisruptor[T]) MustEnqueue(item T) error {
+	if uint64(len(item)) > b.cap {
+		return ErrPayloadTooLarge
+	}
+	r := b.records.retrier()
+	for !b.Enqueue(item) {
+		if b.records.closed.Load() {
+			return ErrClosed
+		}
+		if err := r.backoff(false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *byteDisruptor[T]) Close(ctx context.Context) error {
+	return b.records.Close(ctx)
+}
+
+func (b *byteDisruptor[T]) Retained() uint64 {
+	written := b.written.Load()
+	return written - b.released(written)
+}
+
+// released returns the arena offset every reader has passed, at most written.
+func (b *byteDisruptor[T]) released(written uint64) uint64 {
+	res := written
+	for _, c := range b.consumed {
+		if v := c.Load(); v < res {
+			res = v
+		}
+	}
+	return res
+}
+
+// alignedArena allocates an arena whose first byte is aligned to align bytes.
+// The allocator only guarantees word alignment, so up to align-1 extra bytes
+// are allocated and the aligned window is sliced out of them.
+func alignedArena(capacity, align uint64) []byte {
+	buf := make([]byte, capacity+align-1)
+	skip := -uintptr(unsafe.Pointer(unsafe.SliceData(buf))) & uintptr(align-1)
+	return buf[skip : uint64(skip)+capacity : uint64(skip)+capacity]
+}
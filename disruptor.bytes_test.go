@@ -0,0 +1,165 @@
+package ring
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"unsafe"
+)
+
+func TestByteDisruptor_GatesOnBytes(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	release := make(chan struct{})
+	var mu sync.Mutex
+	var received [][]byte
+	d, err := ByteDisruptor[[]byte](ctx, 64, 16, func(value []byte) {
+		<-release
+		mu.Lock()
+		received = append(received, bytes.Clone(value))
+		mu.Unlock()
+	})
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+
+	payload := make([]byte, 10)
+	for i := 0; i < 6; i++ {
+		payload[0] = byte(i)
+		if !d.Enqueue(payload) {
+			t.Fatalf("Failed to enqueue payload %d", i)
+		}
+	}
+	if got := d.Retained(); got != 60 {
+		t.Errorf("Expected 60 retained bytes, got %d", got)
+	}
+	// The next payload would wrap and overwrite unread bytes
+	if d.Enqueue(payload) {
+		t.Fatal("Expected enqueue to fail when arena is full")
+	}
+
+	close(release)
+	payload[0] = 6
+	if err := d.MustEnqueue(payload); err != nil {
+		t.Fatalf("MustEnqueue failed: %v", err)
+	}
+	for {
+		mu.Lock()
+		n := len(received)
+		mu.Unlock()
+		if n == 7 {
+			break
+		}
+	}
+	for i, v := range received {
+		if len(v) != 10 || v[0] != byte(i) {
+			t.Errorf("Unexpected payload %d: %v", i, v)
+		}
+	}
+}
+
+func TestByteDisruptor_String(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	received := make(chan string, 4)
+	d, err := ByteDisruptor[string](ctx, 16, 4, func(value string) { received <- value })
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	if err := d.MustEnqueue("hello"); err != nil {
+		t.Fatalf("MustEnqueue failed: %v", err)
+	}
+	if v := <-received; v != "hello" {
+		t.Errorf("Expected hello, got %q", v)
+	}
+	if err := d.MustEnqueue(string(make([]byte, 17))); err == nil {
+		t.Error("Expected oversized payload to fail")
+	}
+}
+
+func TestAlignedByteDisruptor(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	const align = 16
+	var mu sync.Mutex
+	var received [][]byte
+	d, err := AlignedByteDisruptor[[]byte](ctx, 64, 16, align, func(value []byte) {
+		if addr := uintptr(unsafe.Pointer(unsafe.SliceData(value))); addr%align != 0 {
+			t.Errorf("Expected a payload aligned to %d bytes, got address %#x", align, addr)
+		}
+		mu.Lock()
+		received = append(received, bytes.Clone(value))
+		mu.Unlock()
+	})
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	// The producer reuses its buffer, readers see the copied payloads
+	payload := make([]byte, 5)
+	for i := 0; i < 20; i++ {
+		payload[0] = byte(i)
+		if err := d.MustEnqueue(payload); err != nil {
+			t.Fatalf("MustEnqueue failed: %v", err)
+		}
+	}
+	if err := d.Close(context.Background()); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if len(received) != 20 {
+		t.Fatalf("Expected 20 payloads, got %d", len(received))
+	}
+	for i, v := range received {
+		if v[0] != byte(i) {
+			t.Errorf("Unexpected payload %d: %v", i, v)
+		}
+	}
+
+	if _, err := AlignedByteDisruptor[[]byte](ctx, 64, 16, 24); !errors.Is(err, ErrCapacity) {
+		t.Errorf("Expected ErrCapacity for a non power of two alignment, got %v", err)
+	}
+}
+
+func TestByteDisruptor_ReaderAppends(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	release := make(chan struct{})
+	var received []string
+	d, err := ByteDisruptor[[]byte](ctx, 64, 16, func(value []byte) {
+		<-release
+		// Appending reallocates instead of writing over the next payload
+		value = append(value, "!!"...)
+		received = append(received, string(value))
+	})
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 1000; i++ {
+			if d.Retained() > 64 {
+				t.Error("Expected at most the capacity retained")
+			}
+		}
+	}()
+	for _, v := range []string{"one", "two"} {
+		if err := d.MustEnqueue([]byte(v)); err != nil {
+			t.Fatalf("MustEnqueue failed: %v", err)
+		}
+	}
+	close(release)
+	<-done
+	if err := d.Close(context.Background()); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if len(received) != 2 || received[0] != "one!!" || received[1] != "two!!" {
+		t.Errorf("Expected [one!! two!!], got %q", received)
+	}
+}
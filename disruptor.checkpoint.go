@@ -0,0 +1,79 @@
+package ring
+
+import "fmt"
+
+var ErrSequenceUnavailable = fmt.Errorf("sequence no longer held by the ring")
+
+// Checkpointer persists a reader's position, e.g. to durable offset storage.
+// Checkpoint receives the number of events the reader has passed, the
+// sequence to resume from with FromSequence. A failed checkpoint is retried
+// after the next batch.
+type Checkpointer interface {
+	Checkpoint(seq uint64) error
+}
+
+// CheckpointFunc adapts a function to a Checkpointer.
+type CheckpointFunc func(seq uint64) error
+
+func (f CheckpointFunc) Checkpoint(seq uint64) error {
+	return f(seq)
+}
+
+// WithCheckpointer calls c on the reader goroutine once the reader passed at
+// least every events since its last checkpoint, at the end of a batch, and
+// once more when the reader stops.
+func WithCheckpointer(every uint64, c Checkpointer) ReaderOption {
+	return func(o *readerOptions) {
+		o.checkpointEvery = max(every, 1)
+		o.checkpointer = c
+	}
+}
+
+// FromSequence starts the reader at sequence seq, e.g. a checkpoint, instead
+// of the writer cursor. AddReader fails with ErrSequenceUnavailable once the
+// writer overwrote the event or if it was not published yet.
+func FromSequence(seq uint64) ReaderOption {
+	return func(o *readerOptions) {
+		o.fromSequence = true
+		o.sequence = seq
+	}
+}
+
+// Sequence returns the number of events the reader has passed, the sequence
+// it reads next.
+func (r *disruptorReader[T]) Sequence() uint64 {
+	return r.tail.Load() >> 1
+}
+
+// startAt gates producers on the reader at seq. A publish that passed the
+// barrier before the reader joined may still overwrite the event, so the
+// sequence is checked again once the reader gates the writer.
+func (d *disruptor[T]) startAt(r *disruptorReader[T], seq uint64) error {
+	tail := 2 * seq
+	if head := d.writerCursor.Load() &^ 1; tail > head || d.oldest(head) > tail {
+		return fmt.Errorf("%w: %d", ErrSequenceUnavailable, seq)
+	}
+	r.tail.Store(tail)
+	d.addGating(&r.tail)
+	if d.oldest(d.writerCursor.Load()&^1) > tail {
+		d.readerBarrier.Remove(&r.tail)
+		return fmt.Errorf("%w: %d", ErrSequenceUnavailable, seq)
+	}
+	return nil
+}
+
+// checkpoint hands the reader's position to its checkpointer once it moved
+// on by the checkpoint interval, or whenever it moved on if force is set.
+// Events still held in a coalesced batch are not checkpointed yet.
+func (r *disruptorReader[T]) checkpoint(force bool) {
+	seq := r.tail.Load() >> 1
+	if r.coalescer != nil {
+		seq -= uint64(len(r.coalescer.batch))
+	}
+	if seq == r.checkpointed || (!force && seq-r.checkpointed < r.checkpointEvery) {
+		return
+	}
+	if r.checkpointer.Checkpoint(seq) == nil {
+		r.checkpointed = seq
+	}
+}
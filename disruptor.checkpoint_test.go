@@ -0,0 +1,127 @@
+package ring
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type offsets struct {
+	mu   sync.Mutex
+	seqs []uint64
+}
+
+func (o *offsets) Checkpoint(seq uint64) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.seqs = append(o.seqs, seq)
+	return nil
+}
+
+func (o *offsets) last() uint64 {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if len(o.seqs) == 0 {
+		return 0
+	}
+	return o.seqs[len(o.seqs)-1]
+}
+
+func TestAddReader_Checkpointer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	d, err := NewDisruptor[int](ctx, WithCapacity(64), WithMaxBatch(1))
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	var o offsets
+	h, err := d.AddReader(func(int) {}, WithCheckpointer(10, &o))
+	if err != nil {
+		t.Fatalf("AddReader failed: %v", err)
+	}
+	for i := 0; i < 25; i++ {
+		if err := d.MustEnqueue(i); err != nil {
+			t.Fatalf("MustEnqueue failed: %v", err)
+		}
+	}
+	waitFor(t, func() bool { return h.Sequence() == 25 })
+	if got := o.last(); got != 20 {
+		t.Errorf("Expected the last checkpoint at 20, got %d", got)
+	}
+	if err := d.Close(context.Background()); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if got := o.last(); got != 25 {
+		t.Errorf("Expected a final checkpoint at 25, got %d", got)
+	}
+}
+
+func TestAddReader_FromSequence(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	d, err := Disruptor[int](ctx, 8)
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	for i := 0; i < 12; i++ {
+		if err := d.MustEnqueue(i); err != nil {
+			t.Fatalf("MustEnqueue failed: %v", err)
+		}
+	}
+	for _, seq := range []uint64{2, 13} {
+		if _, err := d.AddReader(func(int) {}, FromSequence(seq)); !errors.Is(err, ErrSequenceUnavailable) {
+			t.Errorf("Expected ErrSequenceUnavailable for %d, got %v", seq, err)
+		}
+	}
+
+	got := make(chan int, 16)
+	h, err := d.AddReader(func(v int) { got <- v }, FromSequence(9))
+	if err != nil {
+		t.Fatalf("AddReader failed: %v", err)
+	}
+	if err := d.MustEnqueue(12); err != nil {
+		t.Fatalf("MustEnqueue failed: %v", err)
+	}
+	for want := 9; want <= 12; want++ {
+		select {
+		case v := <-got:
+			if v != want {
+				t.Fatalf("Expected %d, got %d", want, v)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Timed out waiting for %d", want)
+		}
+	}
+	waitFor(t, func() bool { return h.Sequence() == 13 })
+}
+
+func TestAddReader_CheckpointCoalesced(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	d, err := Disruptor[int](ctx, 64)
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	var o offsets
+	var b batches
+	h, err := d.AddReader(nil, WithCoalescing(4, time.Hour, b.flush), WithCheckpointer(1, &o))
+	if err != nil {
+		t.Fatalf("AddReader failed: %v", err)
+	}
+	for i := 0; i < 6; i++ {
+		if err := d.MustEnqueue(i); err != nil {
+			t.Fatalf("MustEnqueue failed: %v", err)
+		}
+	}
+	waitFor(t, func() bool { return h.Sequence() == 6 })
+	time.Sleep(10 * time.Millisecond)
+	// Two events wait in the batch and are not checkpointed
+	if got := o.last(); got != 4 {
+		t.Errorf("Expected the checkpoint at the flushed batch, got %d", got)
+	}
+}
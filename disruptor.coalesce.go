@@ -0,0 +1,71 @@
+package ring
+
+import "time"
+
+// coalescing configures a coalescer, type-erased in readerOptions.
+type coalescing[T any] struct {
+	n     int
+	d     time.Duration
+	flush func(batch []T)
+}
+
+// WithCoalescing collects the reader's events into micro-batches and hands a
+// batch to flush once it holds n events or its oldest event waited d. The
+// timeout is checked by the reader loop between batches and while idle, so
+// flush runs on the reader goroutine like the callback. Events still pending
+// when the reader stops are flushed on exit. The batch is reused after flush
+// returns. The reader callback may be nil; if given it still sees every event.
+func WithCoalescing[T any](n int, d time.Duration, flush func(batch []T)) ReaderOption {
+	return func(o *readerOptions) {
+		o.coalescing = coalescing[T]{n: max(n, 1), d: d, flush: flush}
+	}
+}
+
+// coalescer is the batch of a coalescing reader, owned by the reader goroutine.
+type coalescer[T any] struct {
+	coalescing[T]
+	batch []T
+	since time.Time
+}
+
+func newCoalescer[T any](opt any) (*coalescer[T], error) {
+	c, ok := opt.(coalescing[T])
+	if !ok {
+		return nil, ErrReaderType
+	}
+	return &coalescer[T]{coalescing: c, batch: make([]T, 0, c.n)}, nil
+}
+
+func (c *coalescer[T]) add(v T) {
+	if len(c.batch) == 0 {
+		c.since = time.Now()
+	}
+	c.batch = append(c.batch, v)
+	if len(c.batch) >= c.n {
+		c.flushBatch()
+	}
+}
+
+// expire flushes a batch whose oldest event waited the timeout.
+func (c *coalescer[T]) expire() {
+	if len(c.batch) > 0 && time.Since(c.since) >= c.d {
+		c.flushBatch()
+	}
+}
+
+// remaining returns the time until a pending batch is due.
+func (c *coalescer[T]) remaining() (time.Duration, bool) {
+	if len(c.batch) == 0 {
+		return 0, false
+	}
+	return c.d - time.Since(c.since), true
+}
+
+func (c *coalescer[T]) flushBatch() {
+	if len(c.batch) == 0 {
+		return
+	}
+	c.flush(c.batch)
+	clear(c.batch)
+	c.batch = c.batch[:0]
+}
@@ -0,0 +1,115 @@
+package ring
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// batches records the flushed batches, copied since the coalescer reuses them.
+type batches struct {
+	mu  sync.Mutex
+	got [][]int
+}
+
+func (b *batches) flush(batch []int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.got = append(b.got, slices.Clone(batch))
+}
+
+func (b *batches) snapshot() [][]int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return slices.Clone(b.got)
+}
+
+func TestAddReader_CoalescingSize(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	d, err := Disruptor[int](ctx, 16)
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	var b batches
+	if _, err := d.AddReader(nil, WithCoalescing(4, time.Hour, b.flush)); err != nil {
+		t.Fatalf("AddReader failed: %v", err)
+	}
+	for i := 0; i < 10; i++ {
+		if err := d.MustEnqueue(i); err != nil {
+			t.Fatalf("MustEnqueue failed: %v", err)
+		}
+	}
+	time.Sleep(20 * time.Millisecond)
+	got := b.snapshot()
+	if len(got) != 2 || !slices.Equal(got[0], []int{0, 1, 2, 3}) || !slices.Equal(got[1], []int{4, 5, 6, 7}) {
+		t.Fatalf("Expected two full batches, got %v", got)
+	}
+
+	// The remainder is flushed when the disruptor closes
+	if err := d.Close(context.Background()); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if got = b.snapshot(); len(got) != 3 || !slices.Equal(got[2], []int{8, 9}) {
+		t.Errorf("Expected the partial batch on close, got %v", got)
+	}
+}
+
+func TestAddReader_CoalescingTimeout(t *testing.T) {
+	for name, opts := range map[string][]Option{
+		"backoff":  nil,
+		"blocking": {WithBlockingWait()},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			d, err := NewDisruptor[int](ctx, append(opts, WithCapacity(16))...)
+			if err != nil {
+				t.Fatalf("Failed to create disruptor: %v", err)
+			}
+			var b batches
+			var seen []int
+			if _, err := d.AddReader(func(v int) { seen = append(seen, v) }, WithCoalescing(100, 10*time.Millisecond, b.flush)); err != nil {
+				t.Fatalf("AddReader failed: %v", err)
+			}
+			start := time.Now()
+			for i := 0; i < 3; i++ {
+				if err := d.MustEnqueue(i); err != nil {
+					t.Fatalf("MustEnqueue failed: %v", err)
+				}
+			}
+			deadline := time.Now().Add(time.Second)
+			for len(b.snapshot()) == 0 {
+				if time.Now().After(deadline) {
+					t.Fatal("Timed out waiting for the batch to be flushed")
+				}
+				time.Sleep(time.Millisecond)
+			}
+			if elapsed := time.Since(start); elapsed < 10*time.Millisecond {
+				t.Errorf("Batch flushed before its timeout after %v", elapsed)
+			}
+			if got := b.snapshot(); !slices.Equal(got[0], []int{0, 1, 2}) || !slices.Equal(seen, []int{0, 1, 2}) {
+				t.Errorf("Expected events 0-2 in the callback and the batch, got %v and %v", seen, got)
+			}
+		})
+	}
+}
+
+func TestAddReader_CoalescingType(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	d, err := Disruptor[int](ctx, 16)
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	_, err = d.AddReader(nil, WithCoalescing(4, time.Millisecond, func(batch []string) {}))
+	if !errors.Is(err, ErrReaderType) {
+		t.Errorf("Expected ErrReaderType, got %v", err)
+	}
+}
@@ -0,0 +1,81 @@
+package ring
+
+import (
+	"fmt"
+	"github.com/dk-open/ring/pad"
+	"strings"
+)
+
+// DisruptorSnapshot is a copy of the events a disruptor holds for its gating
+// readers, taken for debugging.
+type DisruptorSnapshot[T any] struct {
+	// Name is the name given with WithName.
+	Name string
+	// Writer is the number of events published when the events were copied.
+	Writer uint64
+	// Readers holds the sequence of every reader in registration order.
+	Readers []uint64
+	// Start is the sequence of Items[0], the slowest gating reader's.
+	Start uint64
+	// Items are the events published but not yet passed by every gating
+	// reader, oldest first.
+	Items []T
+}
+
+// Snapshot copies the events between the slowest gating reader and the writer
+// into a fresh slice. It is advisory: producers and readers keep running, the
+// cursors are read one after another and are stale by the time it returns.
+// While the events are copied a pin holds producers back from their slots, so
+// the copies are never torn. Values referencing shared memory show its
+// current state.
+func (d *disruptor[T]) Snapshot() DisruptorSnapshot[T] {
+	pin := pad.ConstBarrier(d.readerBarrier.Load() &^ 1)
+	d.addGating(&pin)
+	defer d.readerBarrier.Remove(&pin)
+
+	// A producer that checked for room before the pin joined only overwrites
+	// events the readers had passed, the readers' minimum loaded now is above
+	head := d.writerCursor.Load() &^ 1
+	start := head
+	for _, b := range d.readerBarrier.Members() {
+		if b != pad.Barrier(&pin) {
+			start = min(start, b.Load()&^1)
+		}
+	}
+	res := DisruptorSnapshot[T]{
+		Name:    d.name,
+		Writer:  head >> 1,
+		Readers: d.readerSequences(head),
+		Start:   start >> 1,
+		Items:   make([]T, 0, (head-start)>>1),
+	}
+	for seq := start; seq < head; seq += 2 {
+		res.Items = append(res.Items, *d.slot(seq))
+	}
+	return res
+}
+
+// String describes the disruptor's cursors for logs, e.g.
+// `disruptor orders: writer=120 released=100 readers=[100 118] capacity=64`.
+func (d *disruptor[T]) String() string {
+	head := d.writerCursor.Load() &^ 1
+	var b strings.Builder
+	b.WriteString("disruptor")
+	if d.name != "" {
+		b.WriteString(" " + d.name)
+	}
+	fmt.Fprintf(&b, ": writer=%d released=%d readers=%v capacity=%d",
+		head>>1, d.Released(), d.readerSequences(head), d.cap)
+	return b.String()
+}
+
+// readerSequences returns the sequence of every registered reader.
+func (d *disruptor[T]) readerSequences(head uint64) []uint64 {
+	d.registry.mu.Lock()
+	defer d.registry.mu.Unlock()
+	res := make([]uint64, len(d.registry.readers))
+	for i, r := range d.registry.readers {
+		res[i] = r.stats(head).Sequence
+	}
+	return res
+}
@@ -0,0 +1,73 @@
+package ring
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDisruptor_Snapshot(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	d, err := NewDisruptor[int](ctx, WithCapacity(8), WithName("orders"))
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	r := d.NewReader()
+	for i := 0; i < 5; i++ {
+		d.Enqueue(i)
+	}
+	r.Dequeue()
+	r.Dequeue()
+
+	snap := d.Snapshot()
+	if snap.Writer != 5 || snap.Start != 2 || len(snap.Readers) != 1 || snap.Readers[0] != 2 {
+		t.Errorf("Unexpected cursors %+v", snap)
+	}
+	if len(snap.Items) != 3 || snap.Items[0] != 2 || snap.Items[2] != 4 {
+		t.Errorf("Expected the unconsumed events 2..4, got %v", snap.Items)
+	}
+	if got := d.Released(); got != 2 {
+		t.Errorf("Expected the snapshot to leave the barrier at 2, got %d", got)
+	}
+	if got := d.(interface{ String() string }).String(); got != "disruptor orders: writer=5 released=2 readers=[2] capacity=8" {
+		t.Errorf("Unexpected String %q", got)
+	}
+}
+
+func TestDisruptor_SnapshotConcurrent(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var passed atomic.Int64
+	d, err := NewDisruptor[int](ctx, WithCapacity(16), WithReaders(func(int) { passed.Add(1) }))
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 20000; i++ {
+			if err := d.MustEnqueue(i); err != nil {
+				t.Errorf("Failed to enqueue %d: %v", i, err)
+				return
+			}
+		}
+	}()
+	for done := false; !done; time.Sleep(10 * time.Microsecond) {
+		snap := d.Snapshot()
+		for k, v := range snap.Items {
+			if uint64(v) != snap.Start+uint64(k) {
+				t.Fatalf("Expected event %d at sequence %d, got %d", snap.Start+uint64(k), snap.Start+uint64(k), v)
+			}
+		}
+		done = snap.Writer == 20000
+	}
+	wg.Wait()
+	if !strings.HasPrefix(d.(interface{ String() string }).String(), "disruptor: writer=20000") {
+		t.Errorf("Unexpected String %q", d.(interface{ String() string }).String())
+	}
+}
@@ -0,0 +1,42 @@
+package ring
+
+// FilterReader wraps cb so that it only sees the events pred accepts. The
+// predicate runs on the reader's goroutine, skipped events still advance the
+// reader's sequence, so they never hold back producers.
+func FilterReader[T any](pred func(value T) bool, cb ReaderCallback[T]) ReaderCallback[T] {
+	return func(value T) {
+		if pred(value) {
+			cb(value)
+		}
+	}
+}
+
+// MapReader transforms every event with fn and forwards the result to
+// downstream on the reader's goroutine, without an intermediate ring.
+// Decorators compose, e.g. FilterReader(pred, MapReader(fn, cb)).
+func MapReader[A, B any](fn func(value A) B, downstream ReaderCallback[B]) ReaderCallback[A] {
+	return func(value A) {
+		downstream(fn(value))
+	}
+}
+
+// DedupReader wraps cb so that it skips an event equal to the one before it,
+// for state propagation where only changes matter. Every call creates its own
+// memory of the previous event, so each reader deduplicates on its own, but a
+// single decorated callback must not be shared by several readers.
+func DedupReader[T comparable](cb ReaderCallback[T]) ReaderCallback[T] {
+	return DedupReaderFunc(func(a, b T) bool { return a == b }, cb)
+}
+
+// DedupReaderFunc is DedupReader comparing events with equal.
+func DedupReaderFunc[T any](equal func(a, b T) bool, cb ReaderCallback[T]) ReaderCallback[T] {
+	var prev T
+	seen := false
+	return func(value T) {
+		if seen && equal(prev, value) {
+			return
+		}
+		prev, seen = value, true
+		cb(value)
+	}
+}
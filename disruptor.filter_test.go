@@ -0,0 +1,76 @@
+package ring
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestFilterMapReader(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var mu sync.Mutex
+	var got []string
+	reader := FilterReader(func(v int) bool { return v%2 == 0 },
+		MapReader(strconv.Itoa, func(s string) {
+			mu.Lock()
+			got = append(got, s)
+			mu.Unlock()
+		}))
+	d, err := NewDisruptor[int](ctx, WithCapacity(4), WithReaders(reader))
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	// More events than slots, skipped events must release theirs
+	for i := 0; i < 10; i++ {
+		if err := d.MustEnqueue(i); err != nil {
+			t.Fatalf("Failed to enqueue %d: %v", i, err)
+		}
+	}
+	if err := d.WaitDrained(ctx); err != nil {
+		t.Fatalf("WaitDrained failed: %v", err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	want := []string{"0", "2", "4", "6", "8"}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Expected %v, got %v", want, got)
+			break
+		}
+	}
+}
+
+func TestDedupReader(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var first, second []int
+	d, err := NewDisruptor[int](ctx, WithCapacity(16), WithReaders(
+		DedupReader(func(v int) { first = append(first, v) }),
+		DedupReaderFunc(func(a, b int) bool { return a/10 == b/10 }, func(v int) { second = append(second, v) }),
+	))
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	for _, v := range []int{0, 0, 1, 1, 1, 0, 12, 12, 15} {
+		if err := d.MustEnqueue(v); err != nil {
+			t.Fatalf("Failed to enqueue %d: %v", v, err)
+		}
+	}
+	if err := d.WaitDrained(ctx); err != nil {
+		t.Fatalf("WaitDrained failed: %v", err)
+	}
+	if want := []int{0, 1, 0, 12, 15}; !slices.Equal(first, want) {
+		t.Errorf("Expected %v, got %v", want, first)
+	}
+	if want := []int{0, 12}; !slices.Equal(second, want) {
+		t.Errorf("Expected %v, got %v", want, second)
+	}
+}
@@ -2,109 +2,447 @@ package ring
 
 import (
 	"context"
-	"fmt"
 	"github.com/dk-open/ring/pad"
-	"runtime"
-	"time"
+	"iter"
+	"sync"
+	"sync/atomic"
 )
 
 type IDisruptor[T any] interface {
 	Enqueue(item T) bool
+	// TryEnqueue makes a single attempt like Enqueue and tells why it failed.
+	TryEnqueue(item T) Result
 	MustEnqueue(item T) error
+	EnqueueBatch(items []T) int
+	MustEnqueueBatch(items []T) error
+	// Publisher returns a handle for a single producer goroutine publishing
+	// windows of up to size events.
+	Publisher(size int) *Publisher[T]
+	// Close stops accepting new events, waits until every gating reader has
+	// consumed the published events and stops the readers. If ctx is done
+	// first the readers are cancelled without waiting for them.
+	Close(ctx context.Context) error
+	// Start launches the readers of a disruptor created by Build.
+	Start(ctx context.Context) error
+	// Stop stops accepting events and cancels the readers without draining.
+	Stop()
+	// Wait blocks until the disruptor is stopped and its readers have exited.
+	Wait() error
+	// WaitDrained blocks until every gating reader has passed the events
+	// published before the call.
+	WaitDrained(ctx context.Context) error
+	HandleWith(readers ...ReaderCallback[T]) *ReaderGroup[T]
+	HandleWithSequenced(readers ...SequencedReaderCallback[T]) *ReaderGroup[T]
+	HandleWithWorkerPool(workers ...ReaderCallback[T]) *ReaderGroup[T]
+	HandleWithGroup(name string, members ...ReaderCallback[T]) *ReaderGroup[T]
+	WithStandbyReader(f ReaderCallback[T], activateOn func() bool) *ReaderGroup[T]
+	NewReader() IDisruptorRing[T]
+	AddReader(f ReaderCallback[T], opts ...ReaderOption) (ReaderHandle[T], error)
+	Stats() Stats
+	// Published returns the number of events published so far.
+	Published() uint64
+	// Released returns the number of events every gating reader has passed.
+	Released() uint64
+	// Snapshot copies the events not passed by every gating reader yet, for
+	// debugging.
+	Snapshot() DisruptorSnapshot[T]
+	// Retain attaches side metadata that is released as readers pass events.
+	Retain(r Retainer) (remove func())
 }
 
+// IDisruptorRing is a pull-style disruptor reader.
 type IDisruptorRing[T any] interface {
 	Dequeue() (res T, ok bool)
+	// Events yields the events with their sequence numbers until ctx is done
+	// or the disruptor is closed and the reader has caught up.
+	Events(ctx context.Context) iter.Seq2[uint64, T]
+	// Close detaches the reader from the gating barrier.
+	Close()
 }
 
 type ReaderCallback[T any] func(value T)
 
+// SequencedReaderCallback receives every event with its sequence number and
+// whether it is the last event of the batch currently available to the reader,
+// which is a natural point to checkpoint or flush.
+type SequencedReaderCallback[T any] func(seq uint64, value T, endOfBatch bool)
+
+// UnknownSequence is passed to sequenced readers for events whose position in
+// the ring is no longer known, e.g. events replayed from a side queue.
+const UnknownSequence = ^uint64(0)
+
 type disruptor[T any] struct {
-	buffer        []T
-	cap           uint64
-	capMask       uint64
-	capX2         uint64
-	writerCursor  pad.AtomicUint64
-	readerBarrier pad.Barrier
+	ctx     context.Context
+	cancel  context.CancelFunc
+	name    string
+	readers sync.WaitGroup
+	closed  atomic.Bool
+	// producers counts the publish attempts in progress, Close waits for them
+	producers    pad.AtomicInt64
+	buffer       []T
+	slotShift    uint
+	cap          uint64
+	capMask      uint64
+	capX2        uint64
+	limit        uint64
+	writerCursor pad.AtomicUint64
+	// readerBarrier is the minimum over the gating readers, the writer cursor
+	// while there are none
+	readerBarrier *pad.CompositeBarrier
+	// gating caches readerBarrier for the producers' full checks
+	gating *pad.CachingMinBarrier
+
+	singleProducer bool
+	publishing     atomic.Bool
+
+	// wake is closed and replaced to cut parked readers' sleeps short, nil
+	// unless priority inheritance or blocking wait is enabled
+	wake atomic.Pointer[chan struct{}]
+	// blocking readers park on wake until signalled, waiters counts them
+	blocking bool
+	waiters  atomic.Int64
+	// upper bound of the adaptive spin budget, zero without adaptive wait
+	maxSpins uint64
+
+	policy SlowConsumerPolicy
+	onDrop func(value T)
+	// dropHandler receives events Enqueue rejects on a full ring
+	dropHandler func(item T)
+	dropped     pad.AtomicUint64
+
+	watermarks *watermarks
+	idle       idleBackoff
+	maxBatch   uint64
+	retry      retryPolicy
+	// stamps holds publish times, nil unless WithLatencyTracking
+	stamps *stamps
+
+	// admission orders producers waiting on a full ring, nil unless fair
+	admission *admission
+	// limiter caps the publish rate, nil without WithMaxRate
+	limiter *rateLimiter
+
+	lifecycle      lifecycle
+	registry       readerRegistry
+	readerIDs      atomic.Uint64
+	retention      retention
+	reclaimed      atomic.Uint64
+	failedEnqueues atomic.Uint64
+	drops          atomic.Uint64
+	backoffSleeps  atomic.Uint64
 }
 
 func Disruptor[T any](ctx context.Context, capacity uint64, readers ...ReaderCallback[T]) (IDisruptor[T], error) {
-	if capacity <= 0 || capacity&(capacity-1) != 0 {
-		return nil, ErrCapacity
-	}
-	res := &disruptor[T]{
-		buffer:  make([]T, capacity),
-		capMask: capacity - 1,
-		cap:     capacity,
-		capX2:   capacity*2 - 1,
+	return NewDisruptor[T](ctx, WithCapacity(capacity), WithReaders(readers...))
+}
+
+// DisruptorGroups creates a disruptor with named consumer groups. Every group
+// sees every event, members inside a group load-balance the group's events.
+func DisruptorGroups[T any](ctx context.Context, capacity uint64, groups map[string][]ReaderCallback[T]) (IDisruptor[T], error) {
+	res, err := Disruptor[T](ctx, capacity)
+	if err != nil {
+		return nil, err
 	}
-	barriers := pad.MinBarrier{}
-	for _, o := range readers {
-		barriers = append(barriers, runReader(ctx, res, o))
+	for name, members := range groups {
+		res.HandleWithGroup(name, members...)
 	}
-
-	res.readerBarrier = barriers
 	return res, nil
 }
 
+// HandleWith starts readers gated only by the writer cursor. The returned group
+// can be used to chain dependent stages with Then.
+func (d *disruptor[T]) HandleWith(readers ...ReaderCallback[T]) *ReaderGroup[T] {
+	return d.startGroup(&d.writerCursor, readers)
+}
+
+// HandleWithSequenced starts sequenced readers gated only by the writer cursor.
+func (d *disruptor[T]) HandleWithSequenced(readers ...SequencedReaderCallback[T]) *ReaderGroup[T] {
+	return d.startSequencedGroup(&d.writerCursor, readers)
+}
+
 func (d *disruptor[T]) Enqueue(item T) bool {
+	return d.TryEnqueue(item) == Ok
+}
+
+func (d *disruptor[T]) TryEnqueue(item T) Result {
+	if d.limiter == nil {
+		return d.enqueue(item)
+	}
+	if d.limiter.take(1) == 0 {
+		d.failedEnqueues.Add(1)
+		return Full
+	}
+	res := d.enqueue(item)
+	if res != Ok {
+		d.limiter.refund(1)
+	}
+	return res
+}
+
+func (d *disruptor[T]) enqueue(item T) Result {
+	if d.closed.Load() {
+		return Closed
+	}
+	if d.queued() {
+		// Producers waiting for admission go first
+		d.failedEnqueues.Add(1)
+		d.rejected(item)
+		return Full
+	}
+	for {
+		ok, full := d.tryEnqueue(item)
+		switch {
+		case ok:
+			d.occupancy()
+			d.signal()
+			return Ok
+		case full && d.policy == DropOldest && d.dropOldest():
+			continue
+		case full && d.policy == DropNewest:
+			d.dropNewest(item)
+			return Full
+		}
+		d.failedEnqueues.Add(1)
+		switch {
+		case full:
+			d.rejected(item)
+			return Full
+		case d.closed.Load(), d.writerCursor.Load() >= d.limit:
+			return Closed
+		}
+		return Contended
+	}
+}
+
+func (d *disruptor[T]) MustEnqueue(item T) error {
+	if _, err := d.throttle(1); err != nil {
+		return err
+	}
+	return d.waitEnqueue(item)
+}
+
+// waitEnqueue publishes item past the rate limit, behind the producers
+// waiting for admission.
+func (d *disruptor[T]) waitEnqueue(item T) error {
+	if d.queued() {
+		return d.admit(item)
+	}
+	return d.mustEnqueue(item, d.admission != nil)
+}
+
+// mustEnqueue publishes item, a fair producer that finds the ring full queues
+// up for admission.
+func (d *disruptor[T]) mustEnqueue(item T, fair bool) error {
+	r := d.retrier()
+	for {
+		if d.closed.Load() {
+			return ErrClosed
+		}
+		ok, full := d.tryEnqueue(item)
+		if ok {
+			d.occupancy()
+			d.signal()
+			return nil
+		}
+		if full {
+			switch d.policy {
+			case DropNewest:
+				d.dropNewest(item)
+				return ErrDropped
+			case DropOldest:
+				if d.dropOldest() {
+					continue
+				}
+			}
+			if fair && d.policy == Block {
+				return d.admit(item)
+			}
+		}
+		if d.writerCursor.Load() >= d.limit {
+			return ErrSequenceExhausted
+		}
+		if err := d.backoff(&r, full); err != nil {
+			d.failedEnqueues.Add(1)
+			return err
+		}
+	}
+}
+
+// rejected reports an event Enqueue turned away on a full ring.
+func (d *disruptor[T]) rejected(item T) {
+	if d.dropHandler != nil {
+		d.dropHandler(item)
+	}
+}
+
+// tryEnqueue makes a single publish attempt. full reports that it failed on
+// the gating readers rather than on a competing producer.
+func (d *disruptor[T]) tryEnqueue(item T) (ok, full bool) {
+	if !d.enter() {
+		return false, false
+	}
+	defer d.producers.Add(-1)
+	if d.singleProducer {
+		return d.publish(item)
+	}
 	head := d.writerCursor.Load()
-	if head-d.readerBarrier.Load() >= d.capX2 {
-		return false
+	// An odd cursor means another producer is between its CAS and its commit
+	if head&1 == 1 || head >= d.limit {
+		return false, false
+	}
+	if d.full(head) {
+		return false, true
 	}
 
 	nextHead := head + 1
 	if d.writerCursor.CompareAndSwap(head, nextHead) {
-		d.buffer[head>>1&d.capMask] = item
+		d.store(head, item)
+		if d.stamps != nil {
+			d.stamps.publish(head>>1, 1)
+		}
 		d.writerCursor.Store(nextHead + 1)
-		return true
+		return true, false
 	}
-	return false
+	return false, false
 }
 
-func (d *disruptor[T]) MustEnqueue(item T) error {
-	attempt := 0
-	for {
-		head := d.writerCursor.Load()
-		if head-d.readerBarrier.Load() >= d.capX2 {
-			if err := backoff(attempt); err != nil {
-				return fmt.Errorf("enqueue failed after %d attempts: %w", attempt, err)
-			}
-			continue
+// enter counts a publish attempt unless the disruptor is closed. Close waits
+// until no attempt is counted, so an event committed by a producer that
+// entered before Close is drained and no producer enters afterwards.
+func (d *disruptor[T]) enter() bool {
+	d.producers.Add(1)
+	if d.closed.Load() {
+		d.producers.Add(-1)
+		return false
+	}
+	return true
+}
+
+// publish is the single-producer path. Readers only consume below the cursor,
+// so the slot is written first and committed with one store.
+func (d *disruptor[T]) publish(item T) (ok, full bool) {
+	if debugChecks && d.publishing.Swap(true) {
+		panic("ring: concurrent publish on a single-producer disruptor")
+	}
+	head := d.writerCursor.Load()
+	full = d.full(head)
+	if ok = !full && head < d.limit; ok {
+		d.store(head, item)
+		if d.stamps != nil {
+			d.stamps.publish(head>>1, 1)
 		}
+		d.writerCursor.Store(head + 2)
+	}
+	if debugChecks {
+		d.publishing.Store(false)
+	}
+	return ok, full
+}
 
-		nextHead := head + 1
-		if d.writerCursor.CompareAndSwap(head, nextHead) {
-			d.buffer[head>>1&d.capMask] = item
-			d.writerCursor.Store(nextHead + 1)
-			return nil
+func (d *disruptor[T]) Published() uint64 {
+	return d.writerCursor.Load() >> 1
+}
+
+func (d *disruptor[T]) Released() uint64 {
+	return d.readerBarrier.Load() >> 1
+}
+
+func (d *disruptor[T]) Close(ctx context.Context) error {
+	d.closed.Store(true)
+	defer d.cancel()
+	// Readers of a disruptor that was never started drain it now
+	d.release()
+
+	for attempt := uint64(0); ; {
+		head, err := d.drain(ctx)
+		if err != nil {
+			// Readers blocked inside a callback are not waited for
+			return err
 		}
-		attempt++
-		if err := backoff(attempt); err != nil {
-			return fmt.Errorf("enqueue failed after %d attempts: %w", attempt, err)
+		// A producer that entered before Close may still commit
+		if d.producers.Load() != 0 {
+			readerYield(attempt)
+			attempt++
+			continue
 		}
-		continue
-	}
-}
-
-func backoff(attempt int) error {
-	switch {
-	case attempt < 5:
-		// On modern CPUs, can hint with a PAUSE (Go does not expose directly)
-		// Just an empty loop does nothing, but you could do:
-		// runtime_procPin()... // not exposed
-		// For real, just do nothing
-	case attempt < 20:
-		runtime.Gosched() // Let Go scheduler run another goroutine
-	case attempt < 10000:
-		// Exponential backoff, up to a max
-		d := time.Microsecond << uint(attempt-20)
-		if d > 5*time.Millisecond {
-			d = 5 * time.Millisecond
+		if d.writerCursor.Load() == head {
+			d.cancel()
+			d.readers.Wait()
+			return nil
 		}
-		time.Sleep(d)
-	default:
-		return fmt.Errorf("enqueue failed after %d attempts", attempt)
 	}
-	return nil
+}
+
+// WaitDrained blocks until every gating reader has passed the events
+// published before the call, or ctx is done. Producers may keep publishing.
+func (d *disruptor[T]) WaitDrained(ctx context.Context) error {
+	_, err := d.drain(ctx)
+	return err
+}
+
+// drain waits for the gating readers to reach the writer cursor and returns
+// the cursor it waited for.
+func (d *disruptor[T]) drain(ctx context.Context) (uint64, error) {
+	// An odd cursor is a publish in flight, it commits at the next even value
+	head := (d.writerCursor.Load() + 1) &^ 1
+	_, err := d.waitable(d.readerBarrier).WaitFor(ctx, head)
+	return head, err
+}
+
+// full reports whether publishing head would overwrite a slot a gating reader
+// has not passed. The readers are only re-scanned once the cached barrier is
+// too far behind.
+func (d *disruptor[T]) full(head uint64) bool {
+	return head >= d.capX2 && !d.gating.Reaches(head-d.capX2+1)
+}
+
+// addGating makes producers wait for barriers. A barrier may join behind the
+// cached gating value, so the cache is dropped.
+func (d *disruptor[T]) addGating(barriers ...pad.Barrier) {
+	d.readerBarrier.Add(barriers...)
+	d.gating.Reset()
+}
+
+// spinAttempts is the number of attempts a waiting goroutine spins on the CPU
+// before it yields to the scheduler.
+const spinAttempts = 5
+
+// backoffSleepAttempt is the first attempt at which backoff sleeps.
+const backoffSleepAttempt = 20
+
+// backoff waits before the next enqueue attempt and counts the sleeps. A
+// producer that starts waiting or is about to sleep wakes the parked readers,
+// so the reader holding the ring does not sleep at the same time.
+func (d *disruptor[T]) backoff(r *retry, full bool) error {
+	attempt := r.attempt + 1
+	if attempt >= backoffSleepAttempt {
+		d.backoffSleeps.Add(1)
+	}
+	if attempt == 1 || attempt >= backoffSleepAttempt {
+		d.boost()
+	}
+	return r.backoff(full)
+}
+
+// retrier starts the retry state of a MustEnqueue call, it gives up once the
+// disruptor's context is done.
+func (d *disruptor[T]) retrier() retry {
+	return retry{policy: d.retry, ctx: d.ctx}
+}
+
+func (d *disruptor[T]) boost() {
+	if wake := d.wake.Load(); wake != nil {
+		next := make(chan struct{})
+		if d.wake.CompareAndSwap(wake, &next) {
+			close(*wake)
+		}
+	}
+}
+
+// signal wakes the readers parked by a blocking wait after a sequence they may
+// be waiting for was advanced.
+func (d *disruptor[T]) signal() {
+	if d.blocking && d.waiters.Load() > 0 {
+		d.boost()
+	}
 }
@@ -0,0 +1,166 @@
+package ring
+
+import (
+	"fmt"
+	"github.com/dk-open/ring/pad"
+)
+
+// ReaderGroup is a stage of the reader dependency graph. Every reader of a
+// stage sees an event only after all readers of the upstream stage have
+// processed it, e.g. journaler and replicator before business logic:
+//
+//	d.HandleWith(journal, replicate).Then(apply)
+type ReaderGroup[T any] struct {
+	d       *disruptor[T]
+	name    string
+	readers []*disruptorReader[T]
+}
+
+// Name returns the name of a consumer group, empty for unnamed stages.
+func (g *ReaderGroup[T]) Name() string {
+	return g.name
+}
+
+// Sequence returns the number of events every reader of the group has passed.
+func (g *ReaderGroup[T]) Sequence() uint64 {
+	return g.Barrier().Load() >> 1
+}
+
+// Then starts readers gated by the sequences of this group.
+func (g *ReaderGroup[T]) Then(readers ...ReaderCallback[T]) *ReaderGroup[T] {
+	return g.d.startGroup(g.Barrier(), readers)
+}
+
+// Handles returns the handles of the group's readers in registration order.
+func (g *ReaderGroup[T]) Handles() []ReaderHandle[T] {
+	res := make([]ReaderHandle[T], len(g.readers))
+	for i, r := range g.readers {
+		res[i] = r
+	}
+	return res
+}
+
+// Barrier returns the minimum sequence processed by every reader of the group.
+func (g *ReaderGroup[T]) Barrier() pad.Barrier {
+	if len(g.readers) == 0 {
+		return &g.d.writerCursor
+	}
+	barriers := make(pad.MinBarrier, 0, len(g.readers))
+	for _, r := range g.readers {
+		barriers = append(barriers, &r.tail)
+	}
+	return barriers
+}
+
+// ThenSequenced starts sequenced readers gated by the sequences of this group.
+func (g *ReaderGroup[T]) ThenSequenced(readers ...SequencedReaderCallback[T]) *ReaderGroup[T] {
+	return g.d.startSequencedGroup(g.Barrier(), readers)
+}
+
+func (d *disruptor[T]) startGroup(barrier pad.Barrier, readers []ReaderCallback[T]) *ReaderGroup[T] {
+	res := make([]*disruptorReader[T], len(readers))
+	for i, f := range readers {
+		res[i] = &disruptorReader[T]{d: d, barrier: barrier, f: f, gated: true}
+	}
+	return d.start(res)
+}
+
+func (d *disruptor[T]) startSequencedGroup(barrier pad.Barrier, readers []SequencedReaderCallback[T]) *ReaderGroup[T] {
+	res := make([]*disruptorReader[T], len(readers))
+	for i, f := range readers {
+		res[i] = &disruptorReader[T]{d: d, barrier: barrier, sf: f, gated: true}
+	}
+	return d.start(res)
+}
+
+func (d *disruptor[T]) start(readers []*disruptorReader[T]) *ReaderGroup[T] {
+	g := &ReaderGroup[T]{d: d, readers: readers}
+	tails := make([]*pad.AtomicUint64, 0, len(readers))
+	for _, r := range readers {
+		r.tail.Store(r.barrier.Load() &^ 1)
+		tails = append(tails, &r.tail)
+	}
+	d.gate(tails...)
+	for _, r := range readers {
+		runReader(d.ctx, r)
+	}
+	return g
+}
+
+// AddReader attaches a reader at runtime. It starts at the current writer
+// cursor and gates producers until its handle is closed.
+func (d *disruptor[T]) AddReader(f ReaderCallback[T], opts ...ReaderOption) (ReaderHandle[T], error) {
+	if d.closed.Load() {
+		return nil, ErrClosed
+	}
+	r := &disruptorReader[T]{d: d, barrier: &d.writerCursor, f: f, gated: true}
+	for _, opt := range opts {
+		opt(&r.readerOptions)
+	}
+	if r.coalescing != nil {
+		c, err := newCoalescer[T](r.coalescing)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %T", err, r.coalescing)
+		}
+		r.coalescer = c
+	}
+	if r.fromSequence {
+		if err := d.startAt(r, r.sequence); err != nil {
+			return nil, err
+		}
+		runReader(d.ctx, r)
+		return r, nil
+	}
+	if r.fromOldest {
+		d.replay(r)
+		runReader(d.ctx, r)
+		return r, nil
+	}
+	r.tail.Store(d.writerCursor.Load() &^ 1)
+	d.gate(&r.tail)
+	runReader(d.ctx, r)
+	return r, nil
+}
+
+// replay gates producers on the reader at the oldest sequence still held. A
+// publish that passed the barrier before the reader joined may still overwrite
+// the oldest slots, so the tail is moved past every sequence it could reach.
+func (d *disruptor[T]) replay(r *disruptorReader[T]) {
+	r.tail.Store(d.oldest(d.writerCursor.Load() &^ 1))
+	d.gate(&r.tail)
+}
+
+// gate adds the tails of readers that do not run yet to the gating barrier. A
+// producer that checked for room before they joined may have lapped them,
+// those tails move on to the oldest event that survives.
+func (d *disruptor[T]) gate(tails ...*pad.AtomicUint64) {
+	barriers := make([]pad.Barrier, len(tails))
+	for i, t := range tails {
+		barriers[i] = t
+	}
+	d.addGating(barriers...)
+	oldest := d.oldest(d.writerCursor.Load() &^ 1)
+	for _, t := range tails {
+		if oldest > t.Load() {
+			t.Store(oldest)
+		}
+	}
+}
+
+// oldest returns the oldest sequence that survives a publish of head.
+func (d *disruptor[T]) oldest(head uint64) uint64 {
+	if head+2 <= 2*d.cap {
+		return 0
+	}
+	return head + 2 - 2*d.cap
+}
+
+// WithStandbyReader starts a warm standby reader. Until activateOn reports true
+// its cursor follows the writer without gating producers, afterwards it
+// becomes a regular gating reader starting at the current writer cursor.
+func (d *disruptor[T]) WithStandbyReader(f ReaderCallback[T], activateOn func() bool) *ReaderGroup[T] {
+	r := &disruptorReader[T]{d: d, barrier: &d.writerCursor, f: f, standby: activateOn}
+	r.tail.Store(d.writerCursor.Load() &^ 1)
+	runReader(d.ctx, r)
+	return &ReaderGroup[T]{d: d, readers: []*disruptorReader[T]{r}}
+}
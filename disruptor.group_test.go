@@ -0,0 +1,141 @@
+package ring
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestReaderGroup_Then(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	const n = 1000
+	var journaled, replicated atomic.Int64
+	var violations atomic.Int64
+	var wg sync.WaitGroup
+	wg.Add(n)
+
+	d, err := Disruptor[int](ctx, 64)
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	d.HandleWith(
+		func(value int) { journaled.Store(int64(value)) },
+		func(value int) { replicated.Store(int64(value)) },
+	).Then(func(value int) {
+		if journaled.Load() < int64(value) || replicated.Load() < int64(value) {
+			violations.Add(1)
+		}
+		wg.Done()
+	})
+
+	for i := 1; i <= n; i++ {
+		if err := d.MustEnqueue(i); err != nil {
+			t.Fatalf("MustEnqueue failed: %v", err)
+		}
+	}
+	wg.Wait()
+
+	if v := violations.Load(); v != 0 {
+		t.Errorf("Expected downstream reader to run after upstream readers, got %d violations", v)
+	}
+}
+
+func TestReaderGroup_EmptyThen(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var wg sync.WaitGroup
+	wg.Add(3)
+	d, err := Disruptor[int](ctx, 8)
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	d.HandleWith().Then(func(value int) { wg.Done() })
+
+	for i := 0; i < 3; i++ {
+		if !d.Enqueue(i) {
+			t.Fatalf("Failed to enqueue item %d", i)
+		}
+	}
+	wg.Wait()
+}
+
+func TestWithStandbyReader(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var active atomic.Bool
+	received := make(chan int, 128)
+	d, err := Disruptor[int](ctx, 4)
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	d.WithStandbyReader(func(value int) { received <- value }, active.Load)
+
+	// Standby reader must not gate the writer
+	for i := 0; i < 10; i++ {
+		if !d.Enqueue(i) {
+			t.Fatalf("Failed to enqueue item %d while reader is on standby", i)
+		}
+	}
+
+	active.Store(true)
+	for len(d.(*disruptor[int]).readerBarrier.Members()) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	for i := 100; i < 200; i++ {
+		if err := d.MustEnqueue(i); err != nil {
+			t.Fatalf("MustEnqueue failed: %v", err)
+		}
+	}
+	for i := 100; i < 200; i++ {
+		if v := <-received; v != i {
+			t.Fatalf("Expected %d, got %d", i, v)
+		}
+	}
+}
+
+func TestHandleWithSequenced(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	const n = 500
+	var next uint64
+	var violations, batches atomic.Int64
+	d, err := Disruptor[int](ctx, 32)
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	d.HandleWithSequenced(func(seq uint64, value int, endOfBatch bool) {
+		if seq != next || int(seq) != value {
+			violations.Add(1)
+		}
+		next++
+		if endOfBatch {
+			batches.Add(1)
+		}
+	})
+
+	for i := 0; i < n; i++ {
+		if err := d.MustEnqueue(i); err != nil {
+			t.Fatalf("MustEnqueue failed: %v", err)
+		}
+	}
+	if err := d.Close(context.Background()); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	if v := violations.Load(); v != 0 {
+		t.Errorf("Expected contiguous sequences, got %d violations", v)
+	}
+	if next != n {
+		t.Errorf("Expected %d events, got %d", n, next)
+	}
+	if b := batches.Load(); b < 1 || b > n {
+		t.Errorf("Expected between 1 and %d batches, got %d", n, b)
+	}
+}
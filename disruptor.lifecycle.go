@@ -0,0 +1,105 @@
+package ring
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+var ErrStarted = fmt.Errorf("disruptor already started")
+
+// Build creates a disruptor configured by options like NewDisruptor, but does
+// not run its readers. Readers registered before Start, with WithReaders or
+// the Handle methods, are wired into the topology and gate producers from the
+// start, their goroutines are launched by Start. Events published before
+// Start wait in the ring.
+func Build[T any](opts ...Option) (IDisruptor[T], error) {
+	return newDisruptor[T](context.Background(), true, opts...)
+}
+
+// lifecycle holds back the reader goroutines of a built disruptor until it is
+// started and records why it stopped.
+type lifecycle struct {
+	mu      sync.Mutex
+	started bool
+	pending []func()
+	stopped error
+}
+
+// launch runs go, the launch of a reader goroutine, now or once the disruptor
+// is started.
+func (d *disruptor[T]) launch(start func()) {
+	d.lifecycle.mu.Lock()
+	if !d.lifecycle.started {
+		d.lifecycle.pending = append(d.lifecycle.pending, start)
+		d.lifecycle.mu.Unlock()
+		return
+	}
+	d.lifecycle.mu.Unlock()
+	start()
+}
+
+// release launches the pending readers, it reports whether the disruptor was
+// not started before.
+func (d *disruptor[T]) release() bool {
+	d.lifecycle.mu.Lock()
+	if d.lifecycle.started {
+		d.lifecycle.mu.Unlock()
+		return false
+	}
+	d.lifecycle.started = true
+	pending := d.lifecycle.pending
+	d.lifecycle.pending = nil
+	d.lifecycle.mu.Unlock()
+	for _, start := range pending {
+		start()
+	}
+	return true
+}
+
+// Start launches the readers of a disruptor created by Build. Once ctx is
+// done the disruptor stops as with Stop and Wait returns ctx's error. It
+// fails with ErrStarted if the disruptor is already running.
+func (d *disruptor[T]) Start(ctx context.Context) error {
+	if !d.release() {
+		return ErrStarted
+	}
+	context.AfterFunc(ctx, func() {
+		d.stop(ctx.Err())
+	})
+	return nil
+}
+
+// Stop stops accepting events and cancels the readers without waiting for
+// them to consume the published events, Close drains them first.
+func (d *disruptor[T]) Stop() {
+	d.stop(nil)
+}
+
+func (d *disruptor[T]) stop(err error) {
+	d.lifecycle.mu.Lock()
+	if d.closed.Load() {
+		d.lifecycle.mu.Unlock()
+		return
+	}
+	d.lifecycle.stopped = err
+	d.closed.Store(true)
+	d.lifecycle.mu.Unlock()
+	d.cancel()
+	// Readers that never ran see the cancelled context and exit at once
+	d.release()
+}
+
+// Wait blocks until the disruptor is stopped and its readers have exited. It
+// returns the error of the context that stopped it, given to Start or to the
+// constructor, and nil after Stop or Close.
+func (d *disruptor[T]) Wait() error {
+	<-d.ctx.Done()
+	d.readers.Wait()
+	if !d.closed.Load() {
+		return d.ctx.Err()
+	}
+	d.lifecycle.mu.Lock()
+	defer d.lifecycle.mu.Unlock()
+	return d.lifecycle.stopped
+}
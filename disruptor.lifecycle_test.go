@@ -0,0 +1,108 @@
+package ring
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBuild_StartStopWait(t *testing.T) {
+	var count atomic.Int64
+	d, err := Build[int](WithCapacity(8), WithReaders(func(int) { count.Add(1) }))
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	d.HandleWith(func(int) { count.Add(1) })
+	for i := 0; i < 3; i++ {
+		if err := d.MustEnqueue(i); err != nil {
+			t.Fatalf("Failed to enqueue %d: %v", i, err)
+		}
+	}
+	time.Sleep(10 * time.Millisecond)
+	if got := count.Load(); got != 0 {
+		t.Fatalf("Expected no reader to run before Start, got %d events", got)
+	}
+	if got := d.Released(); got != 0 {
+		t.Fatalf("Expected the unstarted readers to gate producers, released %d", got)
+	}
+
+	if err := d.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if err := d.Start(context.Background()); !errors.Is(err, ErrStarted) {
+		t.Errorf("Expected ErrStarted, got %v", err)
+	}
+	for deadline := time.Now().Add(time.Second); count.Load() < 6 && time.Now().Before(deadline); {
+		time.Sleep(time.Millisecond)
+	}
+	if got := count.Load(); got != 6 {
+		t.Fatalf("Expected both readers to consume the 3 events, got %d", got)
+	}
+
+	d.Stop()
+	if err := d.Wait(); err != nil {
+		t.Errorf("Expected Wait to return nil after Stop, got %v", err)
+	}
+	if err := d.MustEnqueue(4); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected ErrClosed after Stop, got %v", err)
+	}
+}
+
+func TestBuild_StartContext(t *testing.T) {
+	d, err := Build[int](WithReaders(func(int) {}))
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := d.Start(ctx); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	cancel()
+	if err := d.Wait(); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestBuild_CloseUnstarted(t *testing.T) {
+	var count atomic.Int64
+	d, err := Build[int](WithReaders(func(int) { count.Add(1) }))
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	d.Enqueue(1)
+	d.Enqueue(2)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := d.Close(ctx); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if got := count.Load(); got != 2 {
+		t.Errorf("Expected Close to drain the unstarted readers, got %d events", got)
+	}
+
+	stopped, err := Build[int](WithReaders(func(int) {}))
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	stopped.Stop()
+	if err := stopped.Wait(); err != nil {
+		t.Errorf("Expected readers that never ran to exit on Stop, got %v", err)
+	}
+}
+
+func TestNewDisruptor_Lifecycle(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	d, err := NewDisruptor[int](ctx, WithReaders(func(int) {}))
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	if err := d.Start(ctx); !errors.Is(err, ErrStarted) {
+		t.Errorf("Expected a running disruptor to report ErrStarted, got %v", err)
+	}
+	cancel()
+	if err := d.Wait(); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,73 @@
+package ring
+
+import "github.com/dk-open/ring/pad"
+
+// SlowConsumerPolicy selects what producers do when the slowest reader is a
+// full ring behind.
+type SlowConsumerPolicy int
+
+const (
+	// Block makes Enqueue fail and MustEnqueue back off until the readers
+	// catch up.
+	Block SlowConsumerPolicy = iota
+	// DropNewest drops the event being published and reports it to the
+	// OnDrop callback. MustEnqueue returns ErrDropped.
+	DropNewest
+	// DropOldest overwrites the oldest unread event and moves the lagging
+	// readers past it. Overwritten events are reported to the OnDrop callback.
+	// Readers then copy every event out of its slot under a per-event CAS
+	// instead of publishing their cursor once per batch.
+	DropOldest
+)
+
+func (d *disruptor[T]) dropNewest(item T) {
+	d.drops.Add(1)
+	if d.onDrop != nil {
+		d.onDrop(item)
+	}
+}
+
+// dropOldest moves the readers holding the oldest sequence past it and
+// reports whether the producer may retry. A reader copying the event out of
+// its slot holds an odd cursor and is not moved, the producer waits for it.
+func (d *disruptor[T]) dropOldest() bool {
+	seqs := d.readerBarrier.Members()
+	head := d.writerCursor.Load() &^ 1
+	moved := false
+	for _, b := range seqs {
+		tail, ok := b.(*pad.AtomicUint64)
+		if !ok {
+			continue
+		}
+		if t := tail.Load(); t&1 == 0 && head-t >= d.capX2 && tail.CompareAndSwap(t, t+2) {
+			// Remembered so the producer overwriting the slot reports the event once
+			d.dropped.Store(t + 2)
+			moved = true
+		}
+	}
+	return moved
+}
+
+// store writes the slot of head, the caller holds the sequence. An event the
+// readers were moved past is reported before it is overwritten.
+func (d *disruptor[T]) store(head uint64, item T) {
+	slot := d.slot(head)
+	if d.policy == DropOldest && head >= 2*d.cap && d.dropped.Load() == head+2-2*d.cap {
+		d.drops.Add(1)
+		if d.onDrop != nil {
+			d.onDrop(*slot)
+		}
+	}
+	*slot = item
+}
+
+// claim copies the event at tail out of its slot for a reader of a DropOldest
+// disruptor. It fails if the producer moved the reader past tail.
+func (d *disruptor[T]) claim(cursor *pad.AtomicUint64, tail uint64) (v T, ok bool) {
+	if !cursor.CompareAndSwap(tail, tail+1) {
+		return v, false
+	}
+	v = *d.slot(tail)
+	cursor.Store(tail + 2)
+	return v, true
+}
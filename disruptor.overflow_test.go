@@ -0,0 +1,186 @@
+package ring
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSlowConsumerPolicy_DropNewest(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	release := make(chan struct{})
+	var received []int
+	var dropped []int
+	d, err := NewDisruptor[int](ctx,
+		WithCapacity(4),
+		WithSlowConsumerPolicy(DropNewest),
+		WithOnDrop(func(value int) { dropped = append(dropped, value) }),
+		WithReaders(func(value int) {
+			<-release
+			received = append(received, value)
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+
+	for i := 0; i < 10; i++ {
+		err := d.MustEnqueue(i)
+		switch {
+		case i < 4 && err != nil:
+			t.Fatalf("MustEnqueue %d failed: %v", i, err)
+		case i >= 4 && !errors.Is(err, ErrDropped):
+			t.Fatalf("Expected ErrDropped for %d, got %v", i, err)
+		}
+	}
+	if d.Enqueue(10) {
+		t.Fatalf("Expected Enqueue into a full ring to fail")
+	}
+	close(release)
+	if err := d.Close(context.Background()); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	if len(received) != 4 || received[3] != 3 {
+		t.Errorf("Expected events 0..3, got %v", received)
+	}
+	if len(dropped) != 7 || dropped[0] != 4 || dropped[6] != 10 {
+		t.Errorf("Expected events 4..10 dropped, got %v", dropped)
+	}
+	if s := d.Stats(); s.Dropped != 7 || s.FailedEnqueues != 0 {
+		t.Errorf("Expected 7 drops and no failures, got %+v", s)
+	}
+}
+
+func TestSlowConsumerPolicy_DropOldestPullReader(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var dropped []int
+	d, err := NewDisruptor[int](ctx,
+		WithCapacity(4),
+		WithSlowConsumerPolicy(DropOldest),
+		WithOnDrop(func(value int) { dropped = append(dropped, value) }),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	r := d.NewReader()
+	for i := 0; i < 10; i++ {
+		if err := d.MustEnqueue(i); err != nil {
+			t.Fatalf("MustEnqueue %d failed: %v", i, err)
+		}
+	}
+
+	var received []int
+	for v, ok := r.Dequeue(); ok; v, ok = r.Dequeue() {
+		received = append(received, v)
+	}
+	if len(received) != 4 || received[0] != 6 || received[3] != 9 {
+		t.Errorf("Expected the newest events 6..9, got %v", received)
+	}
+	if len(dropped) != 6 || dropped[0] != 0 || dropped[5] != 5 {
+		t.Errorf("Expected events 0..5 dropped, got %v", dropped)
+	}
+	if s := d.Stats(); s.Dropped != 6 {
+		t.Errorf("Expected 6 drops, got %d", s.Dropped)
+	}
+}
+
+func TestSlowConsumerPolicy_DropOldestConcurrent(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	const n = 20000
+	var dropped atomic.Int64
+	var received []int
+	var mu sync.Mutex
+	seen := make(map[int]int)
+	d, err := NewDisruptor[int](ctx,
+		WithCapacity(16),
+		WithSingleProducer(),
+		WithSlowConsumerPolicy(DropOldest),
+		WithOnDrop(func(value int) { dropped.Add(1) }),
+		WithReaders(func(value int) {
+			if value%64 == 0 {
+				time.Sleep(10 * time.Microsecond)
+			}
+			received = append(received, value)
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	worker := func(value int) {
+		mu.Lock()
+		seen[value]++
+		mu.Unlock()
+	}
+	d.HandleWithWorkerPool(worker, worker)
+
+	for i := 0; i < n; i++ {
+		if err := d.MustEnqueue(i); err != nil {
+			t.Fatalf("MustEnqueue %d failed: %v", i, err)
+		}
+	}
+	if err := d.Close(context.Background()); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	for i := 1; i < len(received); i++ {
+		if received[i] <= received[i-1] {
+			t.Fatalf("Expected increasing events, got %d after %d", received[i], received[i-1])
+		}
+	}
+	if len(received) == 0 || received[len(received)-1] != n-1 {
+		t.Errorf("Expected the reader to end with the last event")
+	}
+	for v, c := range seen {
+		if c != 1 || v < 0 || v >= n {
+			t.Fatalf("Expected every event at most once in the pool, got %d x%d", v, c)
+		}
+	}
+	if d.Stats().Dropped != uint64(dropped.Load()) {
+		t.Errorf("Expected Stats and OnDrop to agree, got %d and %d", d.Stats().Dropped, dropped.Load())
+	}
+}
+
+func TestWithDropHandler(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	release := make(chan struct{})
+	defer close(release)
+	var rejected []int
+	d, err := NewDisruptor[int](ctx,
+		WithCapacity(2),
+		WithMaxAttempts(3),
+		WithDropHandler(func(item int) { rejected = append(rejected, item) }),
+		WithReaders(func(int) { <-release }),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	for i := 0; i < 4; i++ {
+		d.Enqueue(i)
+	}
+	if res := d.TryEnqueue(4); res != Full {
+		t.Fatalf("Expected Full, got %v", res)
+	}
+	// MustEnqueue reports its failures as errors instead
+	if err := d.MustEnqueue(5); !errors.Is(err, ErrFull) {
+		t.Fatalf("Expected MustEnqueue into a held ring to give up, got %v", err)
+	}
+	if len(rejected) != 3 || rejected[0] != 2 || rejected[2] != 4 {
+		t.Errorf("Expected events 2..4 rejected, got %v", rejected)
+	}
+
+	if _, err := NewDisruptor[int](ctx, WithDropHandler(func(string) {})); !errors.Is(err, ErrReaderType) {
+		t.Errorf("Expected ErrReaderType for a mismatched handler, got %v", err)
+	}
+}
@@ -0,0 +1,75 @@
+package ring
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/maphash"
+)
+
+// IPartitionedDisruptor routes every event by key to one of several
+// disruptors, each consumed by its own reader.
+type IPartitionedDisruptor[K comparable, T any] interface {
+	Enqueue(key K, item T) bool
+	MustEnqueue(key K, item T) error
+	// Partition returns the index of the partition handling key.
+	Partition(key K) int
+	// Close closes every partition, see IDisruptor.Close.
+	Close(ctx context.Context) error
+	// Stats returns the stats of every partition.
+	Stats() []Stats
+}
+
+type partitionedDisruptor[K comparable, T any] struct {
+	seed       maphash.Seed
+	partitions []IDisruptor[T]
+}
+
+// PartitionedDisruptor creates partitions disruptors configured by opts, each
+// with its own reader calling handler. Events of the same key go to the same
+// partition and are handled in publish order, events of different partitions
+// are handled concurrently.
+func PartitionedDisruptor[K comparable, T any](ctx context.Context, partitions int, handler ReaderCallback[T], opts ...Option) (IPartitionedDisruptor[K, T], error) {
+	if partitions <= 0 {
+		return nil, fmt.Errorf("partitions must be positive, got %d", partitions)
+	}
+	res := &partitionedDisruptor[K, T]{seed: maphash.MakeSeed()}
+	for i := 0; i < partitions; i++ {
+		d, err := NewDisruptor[T](ctx, append(opts[:len(opts):len(opts)], WithReaders(handler))...)
+		if err != nil {
+			return nil, err
+		}
+		res.partitions = append(res.partitions, d)
+	}
+	return res, nil
+}
+
+func (p *partitionedDisruptor[K, T]) Partition(key K) int {
+	return int(maphash.Comparable(p.seed, key) % uint64(len(p.partitions)))
+}
+
+func (p *partitionedDisruptor[K, T]) Enqueue(key K, item T) bool {
+	return p.partitions[p.Partition(key)].Enqueue(item)
+}
+
+func (p *partitionedDisruptor[K, T]) MustEnqueue(key K, item T) error {
+	return p.partitions[p.Partition(key)].MustEnqueue(item)
+}
+
+func (p *partitionedDisruptor[K, T]) Close(ctx context.Context) error {
+	var errs []error
+	for _, d := range p.partitions {
+		if err := d.Close(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (p *partitionedDisruptor[K, T]) Stats() []Stats {
+	res := make([]Stats, len(p.partitions))
+	for i, d := range p.partitions {
+		res[i] = d.Stats()
+	}
+	return res
+}
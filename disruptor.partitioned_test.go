@@ -0,0 +1,69 @@
+package ring
+
+import (
+	"context"
+	"sync"
+	"testing"
+)
+
+type keyed struct {
+	key string
+	seq int
+}
+
+func TestPartitionedDisruptor(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	const keys, n = 16, 200
+	var mu sync.Mutex
+	last := map[string]int{}
+	p, err := PartitionedDisruptor[string](ctx, 4, func(v keyed) {
+		mu.Lock()
+		defer mu.Unlock()
+		if prev, ok := last[v.key]; ok && v.seq != prev+1 {
+			t.Errorf("Key %s: expected %d, got %d", v.key, prev+1, v.seq)
+		}
+		last[v.key] = v.seq
+	}, WithCapacity(64))
+	if err != nil {
+		t.Fatalf("Failed to create partitioned disruptor: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for k := 0; k < keys; k++ {
+		wg.Add(1)
+		go func(key string) {
+			defer wg.Done()
+			for i := 0; i < n; i++ {
+				if err := p.MustEnqueue(key, keyed{key: key, seq: i}); err != nil {
+					t.Errorf("MustEnqueue failed: %v", err)
+					return
+				}
+			}
+		}(string(rune('a' + k)))
+	}
+	wg.Wait()
+	if err := p.Close(context.Background()); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	used := map[int]bool{}
+	var total uint64
+	for i, st := range p.Stats() {
+		total += st.Writer
+		if st.Writer > 0 {
+			used[i] = true
+		}
+	}
+	if total != keys*n || len(last) != keys {
+		t.Fatalf("Expected %d events of %d keys, got %d events of %d keys", keys*n, keys, total, len(last))
+	}
+	if len(used) < 2 {
+		t.Errorf("Expected keys spread over partitions, used %d", len(used))
+	}
+
+	if _, err := PartitionedDisruptor[string](ctx, 0, func(keyed) {}); err == nil {
+		t.Error("Expected an error for zero partitions")
+	}
+}
@@ -0,0 +1,145 @@
+package ring
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// defaultRetryBackoff and defaultMaxRetryBackoff space out the retries of
+	// an ErrorPolicy that leaves Backoff and MaxBackoff zero.
+	defaultRetryBackoff    = time.Millisecond
+	defaultMaxRetryBackoff = time.Second
+)
+
+// ReaderCallbackErr is a reader callback that reports failures as errors.
+type ReaderCallbackErr[T any] func(value T) error
+
+// ErrorAction selects what a reader does with an event whose callback failed.
+type ErrorAction int
+
+const (
+	// SkipEvent drops the failed event and continues with the next one.
+	SkipEvent ErrorAction = iota
+	// RetryEvent invokes the callback again up to ErrorPolicy.Retries times,
+	// sleeping ErrorPolicy.Backoff before the first retry and twice as long
+	// before every next one. Afterwards the event is routed to the dead-letter
+	// sink or skipped.
+	RetryEvent
+	// DeadLetterEvent routes the failed event to the dead-letter sink.
+	DeadLetterEvent
+	// StopReader stops invoking the callback. The reader keeps consuming so
+	// producers are not stalled: the failed event and every later one go to
+	// the dead-letter sink, without one they are discarded. Suspend the
+	// reader's handle to release its gating sequence.
+	StopReader
+)
+
+// ErrorPolicy describes how a reader recovers from a failing callback.
+type ErrorPolicy[T any] struct {
+	Action  ErrorAction
+	Retries int
+	// Backoff is the sleep before the first retry, 1ms if zero. It doubles
+	// with every retry up to MaxBackoff, 1s if zero.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	// OnPanic is called with the event and the recovered value of every panic.
+	OnPanic func(value T, recovered any)
+	// OnError is called with the event and every error of a ReaderCallbackErr.
+	OnError func(value T, err error)
+	// DeadLetter receives events that could not be processed.
+	DeadLetter func(value T)
+}
+
+// DeadLetterTo returns a dead-letter sink parking failed events in q for later
+// inspection. Events that do not fit into q are dropped.
+func DeadLetterTo[T any](q IQueue[T]) func(value T) {
+	return func(value T) {
+		q.Enqueue(value)
+	}
+}
+
+// WithPanicHandler wraps f so that a panic is recovered and reported to
+// onPanic, the event is skipped and the reader keeps running.
+func WithPanicHandler[T any](f ReaderCallback[T], onPanic func(value T, recovered any)) ReaderCallback[T] {
+	return WithErrorPolicy(f, ErrorPolicy[T]{OnPanic: onPanic})
+}
+
+// WithErrorPolicy wraps f so that a panic is recovered and handled according
+// to the policy instead of crashing the process and stalling the barrier.
+func WithErrorPolicy[T any](f ReaderCallback[T], policy ErrorPolicy[T]) ReaderCallback[T] {
+	return WithErrorHandler(func(value T) error {
+		f(value)
+		return nil
+	}, policy)
+}
+
+// WithErrorHandler adapts an error-returning callback to a reader. Errors and
+// panics are handled according to the policy.
+func WithErrorHandler[T any](f ReaderCallbackErr[T], policy ErrorPolicy[T]) ReaderCallback[T] {
+	var stopped atomic.Bool
+	return func(value T) {
+		if stopped.Load() {
+			policy.deadLetter(value)
+			return
+		}
+		for attempt := 0; ; attempt++ {
+			panicked, recovered, err := invoke(f, value)
+			if !panicked && err == nil {
+				return
+			}
+			if err != nil && policy.OnError != nil {
+				policy.OnError(value, err)
+			}
+			if panicked && policy.OnPanic != nil {
+				policy.OnPanic(value, recovered)
+			}
+			switch policy.Action {
+			case RetryEvent:
+				if attempt < policy.Retries {
+					time.Sleep(policy.retryDelay(attempt))
+					continue
+				}
+			case StopReader:
+				stopped.Store(true)
+			case SkipEvent:
+				return
+			}
+			policy.deadLetter(value)
+			return
+		}
+	}
+}
+
+// retryDelay returns the sleep before retry attempt+1.
+func (p ErrorPolicy[T]) retryDelay(attempt int) time.Duration {
+	res, limit := p.Backoff, p.MaxBackoff
+	if res <= 0 {
+		res = defaultRetryBackoff
+	}
+	if limit <= 0 {
+		limit = defaultMaxRetryBackoff
+	}
+	for ; attempt > 0 && res < limit; attempt-- {
+		res *= 2
+	}
+	return min(res, limit)
+}
+
+func (p ErrorPolicy[T]) deadLetter(value T) {
+	if p.DeadLetter != nil {
+		p.DeadLetter(value)
+	}
+}
+
+func invoke[T any](f ReaderCallbackErr[T], value T) (panicked bool, recovered any, err error) {
+	panicked = true
+	defer func() {
+		if panicked {
+			recovered = recover()
+		}
+	}()
+	err = f(value)
+	panicked = false
+	return
+}
@@ -0,0 +1,162 @@
+package ring
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWithPanicHandler_SkipsEvent(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var panics atomic.Int64
+	var sum atomic.Int64
+	var wg sync.WaitGroup
+	wg.Add(10)
+
+	reader := WithPanicHandler(func(value int) {
+		defer wg.Done()
+		if value%2 == 0 {
+			panic("even")
+		}
+		sum.Add(int64(value))
+	}, func(value int, recovered any) {
+		if recovered != "even" {
+			t.Errorf("Unexpected recovered value %v", recovered)
+		}
+		panics.Add(1)
+	})
+	d, err := Disruptor(ctx, 16, reader)
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	for i := 0; i < 10; i++ {
+		if err := d.MustEnqueue(i); err != nil {
+			t.Fatalf("MustEnqueue failed: %v", err)
+		}
+	}
+	wg.Wait()
+
+	if p, s := panics.Load(), sum.Load(); p != 5 || s != 25 {
+		t.Errorf("Expected 5 panics and sum 25, got %d and %d", p, s)
+	}
+}
+
+func TestWithErrorPolicy_RetryThenDeadLetter(t *testing.T) {
+	var calls int
+	var dead []int
+	reader := WithErrorPolicy(func(value int) {
+		calls++
+		if value == 1 || calls == 1 {
+			panic("failed")
+		}
+	}, ErrorPolicy[int]{
+		Action:     RetryEvent,
+		Retries:    2,
+		DeadLetter: func(value int) { dead = append(dead, value) },
+	})
+
+	reader(0) // fails once, succeeds on retry
+	reader(1) // fails on every attempt
+
+	if calls != 2+3 {
+		t.Errorf("Expected 5 calls, got %d", calls)
+	}
+	if len(dead) != 1 || dead[0] != 1 {
+		t.Errorf("Expected item 1 to be dead-lettered, got %v", dead)
+	}
+}
+
+func TestWithErrorHandler_RetryBackoff(t *testing.T) {
+	var calls []time.Time
+	reader := WithErrorHandler(func(value int) error {
+		calls = append(calls, time.Now())
+		return errors.New("db unavailable")
+	}, ErrorPolicy[int]{
+		Action:     RetryEvent,
+		Retries:    4,
+		Backoff:    2 * time.Millisecond,
+		MaxBackoff: 5 * time.Millisecond,
+	})
+	reader(1)
+
+	if len(calls) != 5 {
+		t.Fatalf("Expected 5 calls, got %d", len(calls))
+	}
+	// The waits double from Backoff and stop growing at MaxBackoff
+	for i, want := range []time.Duration{2, 4, 5, 5} {
+		if got := calls[i+1].Sub(calls[i]); got < want*time.Millisecond {
+			t.Errorf("Expected retry %d after at least %v, got %v", i+1, want*time.Millisecond, got)
+		}
+	}
+}
+
+func TestWithErrorPolicy_DeadLetter(t *testing.T) {
+	var dead []int
+	reader := WithErrorPolicy(func(value int) { panic("failed") }, ErrorPolicy[int]{
+		Action:     DeadLetterEvent,
+		DeadLetter: func(value int) { dead = append(dead, value) },
+	})
+	reader(7)
+	if len(dead) != 1 || dead[0] != 7 {
+		t.Errorf("Expected item 7 to be dead-lettered, got %v", dead)
+	}
+}
+
+func TestWithErrorHandler_DeadLetterQueue(t *testing.T) {
+	dlq, err := Queue[int](8)
+	if err != nil {
+		t.Fatalf("Failed to create queue: %v", err)
+	}
+	errTransient := errors.New("db unavailable")
+	var reported []error
+	reader := WithErrorHandler(func(value int) error {
+		if value < 0 {
+			return errTransient
+		}
+		return nil
+	}, ErrorPolicy[int]{
+		Action:     RetryEvent,
+		Retries:    1,
+		OnError:    func(value int, err error) { reported = append(reported, err) },
+		DeadLetter: DeadLetterTo(dlq),
+	})
+
+	reader(1)
+	reader(-1)
+	reader(2)
+
+	if len(reported) != 2 || !errors.Is(reported[0], errTransient) {
+		t.Errorf("Expected 2 reported errors, got %v", reported)
+	}
+	if v, ok := dlq.Dequeue(); !ok || v != -1 {
+		t.Errorf("Expected -1 in dead-letter queue, got %d %v", v, ok)
+	}
+	if _, ok := dlq.Dequeue(); ok {
+		t.Error("Expected a single dead-lettered item")
+	}
+}
+
+func TestWithErrorHandler_StopReader(t *testing.T) {
+	var processed, dead []int
+	reader := WithErrorHandler(func(value int) error {
+		if value == 2 {
+			return errors.New("fatal")
+		}
+		processed = append(processed, value)
+		return nil
+	}, ErrorPolicy[int]{
+		Action:     StopReader,
+		DeadLetter: func(value int) { dead = append(dead, value) },
+	})
+	for i := 0; i < 5; i++ {
+		reader(i)
+	}
+	if len(processed) != 2 || len(dead) != 3 || dead[0] != 2 {
+		t.Errorf("Expected [0 1] processed and [2 3 4] dead-lettered, got %v and %v", processed, dead)
+	}
+}
@@ -0,0 +1,112 @@
+package ring
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// PriorityDisruptor is a disruptor of priority lanes, lane 0 is the most
+// urgent. Every lane is a disruptor of its own, readers started with
+// HandleWith receive the events of all lanes in priority order.
+type PriorityDisruptor[T any] struct {
+	ctx         context.Context
+	cancel      context.CancelFunc
+	lanes       []IDisruptor[T]
+	picker      *lanePicker
+	dispatchers sync.WaitGroup
+}
+
+// NewPriorityDisruptor creates lanes disruptors, each configured by opts.
+// Readers given with WithReaders would consume a single lane, so readers are
+// attached with HandleWith. Lanes are served as by PriorityQueue: strictly by
+// priority with nil weights, otherwise lane i gets weights[i] of every
+// sum(weights) deliveries while it has events.
+func NewPriorityDisruptor[T any](ctx context.Context, lanes int, weights []int, opts ...Option) (*PriorityDisruptor[T], error) {
+	picker, err := newLanePicker(lanes, weights)
+	if err != nil {
+		return nil, err
+	}
+	res := &PriorityDisruptor[T]{picker: picker}
+	res.ctx, res.cancel = context.WithCancel(ctx)
+	for range lanes {
+		d, err := NewDisruptor[T](ctx, opts...)
+		if err != nil {
+			res.cancel()
+			return nil, err
+		}
+		res.lanes = append(res.lanes, d)
+	}
+	return res, nil
+}
+
+func (p *PriorityDisruptor[T]) Enqueue(item T, prio int) bool {
+	return p.lanes[lane(prio, len(p.lanes))].Enqueue(item)
+}
+
+func (p *PriorityDisruptor[T]) TryEnqueue(item T, prio int) Result {
+	return p.lanes[lane(prio, len(p.lanes))].TryEnqueue(item)
+}
+
+func (p *PriorityDisruptor[T]) MustEnqueue(item T, prio int) error {
+	return p.lanes[lane(prio, len(p.lanes))].MustEnqueue(item)
+}
+
+// Lane returns the disruptor of priority prio.
+func (p *PriorityDisruptor[T]) Lane(prio int) IDisruptor[T] {
+	return p.lanes[lane(prio, len(p.lanes))]
+}
+
+// HandleWith starts a dispatcher per reader. It pulls the events published
+// afterwards from the lanes in turn order and calls the reader on its own
+// goroutine, so an urgent event waits at most for the callback in progress.
+// Every reader sees every event and gates the producers of every lane.
+func (p *PriorityDisruptor[T]) HandleWith(readers ...ReaderCallback[T]) {
+	for _, f := range readers {
+		pulls := make([]IDisruptorRing[T], len(p.lanes))
+		for i, d := range p.lanes {
+			pulls[i] = d.NewReader()
+		}
+		p.dispatchers.Add(1)
+		go p.dispatch(pulls, f)
+	}
+}
+
+func (p *PriorityDisruptor[T]) dispatch(pulls []IDisruptorRing[T], f ReaderCallback[T]) {
+	defer p.dispatchers.Done()
+	defer func() {
+		for _, r := range pulls {
+			r.Close()
+		}
+	}()
+	var attempt uint64
+	for p.ctx.Err() == nil {
+		delivered := false
+		for _, i := range p.picker.turn() {
+			if v, ok := pulls[i].Dequeue(); ok {
+				f(v)
+				delivered = true
+				break
+			}
+		}
+		if delivered {
+			attempt = 0
+			continue
+		}
+		readerYield(attempt)
+		attempt++
+	}
+}
+
+// Close closes the lanes, most urgent first, each after the dispatchers have
+// drained it, then stops the dispatchers. If ctx is done first the remaining
+// lanes are not drained.
+func (p *PriorityDisruptor[T]) Close(ctx context.Context) error {
+	var errs []error
+	for _, d := range p.lanes {
+		errs = append(errs, d.Close(ctx))
+	}
+	p.cancel()
+	p.dispatchers.Wait()
+	return errors.Join(errs...)
+}
@@ -0,0 +1,105 @@
+package ring
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPriorityDisruptor_Strict(t *testing.T) {
+	d, err := NewPriorityDisruptor[int](context.Background(), 2, nil, WithCapacity(16))
+	if err != nil {
+		t.Fatalf("Failed to create priority disruptor: %v", err)
+	}
+	var mu sync.Mutex
+	var got []int
+	release := make(chan struct{})
+	d.HandleWith(func(v int) {
+		if v < 0 {
+			<-release
+			return
+		}
+		mu.Lock()
+		got = append(got, v)
+		mu.Unlock()
+	})
+	// The reader blocks on the first event while both lanes fill up
+	d.Enqueue(-1, 1)
+	time.Sleep(10 * time.Millisecond)
+	for i := 0; i < 3; i++ {
+		d.Enqueue(10+i, 1)
+	}
+	for i := 0; i < 3; i++ {
+		d.Enqueue(i, 0)
+	}
+	close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := d.Close(ctx); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	want := []int{0, 1, 2, 10, 11, 12}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Expected %v, got %v", want, got)
+		}
+	}
+	if err := d.MustEnqueue(1, 0); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected ErrClosed after Close, got %v", err)
+	}
+}
+
+func TestPriorityDisruptor_Weighted(t *testing.T) {
+	d, err := NewPriorityDisruptor[int](context.Background(), 2, []int{1, 1}, WithCapacity(64))
+	if err != nil {
+		t.Fatalf("Failed to create priority disruptor: %v", err)
+	}
+	var got []int
+	release := make(chan struct{})
+	d.HandleWith(func(v int) {
+		if v < 0 {
+			<-release
+			return
+		}
+		got = append(got, v)
+	})
+	d.Enqueue(-1, 1)
+	time.Sleep(10 * time.Millisecond)
+	for i := 0; i < 4; i++ {
+		d.Enqueue(0, 0)
+		d.Enqueue(1, 1)
+	}
+	close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := d.Close(ctx); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if len(got) != 8 {
+		t.Fatalf("Expected 8 events, got %v", got)
+	}
+	// With equal weights the first four deliveries alternate between the lanes
+	var bulk int
+	for _, v := range got[:4] {
+		bulk += v
+	}
+	if bulk != 2 {
+		t.Errorf("Expected the lanes to alternate, got %v", got)
+	}
+	if d.Lane(5) != d.Lane(1) {
+		t.Errorf("Expected priorities out of range to be clamped")
+	}
+}
+
+func TestNewPriorityDisruptor_InvalidWeights(t *testing.T) {
+	if _, err := NewPriorityDisruptor[int](context.Background(), 2, []int{1}); !errors.Is(err, ErrPriority) {
+		t.Errorf("Expected ErrPriority, got %v", err)
+	}
+}
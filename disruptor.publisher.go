@@ -0,0 +1,60 @@
+package ring
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Publisher is a producer handle owned by a single goroutine. It collects a
+// window of events and publishes them with one claim of the writer cursor, so
+// producers with their own handles contend once per window instead of once
+// per event. Events of a window stay contiguous in the ring while it has room
+// for the whole window.
+//
+// The window is buffered in the handle rather than claimed in the ring up
+// front: a claim keeps the writer cursor odd until it commits, so a
+// pre-claimed window would stall every other producer while its owner fills
+// it. The cost is latency, an event stays invisible to readers until its
+// window fills or the owner calls Flush; nothing flushes a window on a timer.
+// Producers with bursty traffic should Flush when they go idle.
+type Publisher[T any] struct {
+	d      *disruptor[T]
+	window []T
+}
+
+// Publisher returns a handle publishing windows of up to size events. Events
+// become visible to readers when the window fills or on Flush.
+func (d *disruptor[T]) Publisher(size int) *Publisher[T] {
+	return &Publisher[T]{d: d, window: make([]T, 0, max(size, 1))}
+}
+
+// Publish adds item to the window, publishing the window once it is full.
+func (p *Publisher[T]) Publish(item T) error {
+	p.window = append(p.window, item)
+	if len(p.window) < cap(p.window) {
+		return nil
+	}
+	return p.Flush()
+}
+
+// Flush publishes the events of the window, waiting while the ring is full.
+// The window is empty afterwards. Events dropped by the slow-consumer policy
+// are reported as by MustEnqueueBatch; on any other error the error names
+// how many events of the window were lost without being published.
+func (p *Publisher[T]) Flush() error {
+	if len(p.window) == 0 {
+		return nil
+	}
+	done, err := p.d.publishBatch(p.window)
+	if err != nil && !errors.Is(err, ErrDropped) {
+		err = fmt.Errorf("%w: %d of %d events not published", err, len(p.window)-done, len(p.window))
+	}
+	clear(p.window)
+	p.window = p.window[:0]
+	return err
+}
+
+// Pending returns the number of events waiting in the window.
+func (p *Publisher[T]) Pending() int {
+	return len(p.window)
+}
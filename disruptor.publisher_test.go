@@ -0,0 +1,84 @@
+package ring
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestDisruptor_Publisher(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	const producers, n, window = 4, 1003, 8
+	last := make([]int, producers)
+	for i := range last {
+		last[i] = -1
+	}
+	var received int
+	d, err := Disruptor[[2]int](ctx, 64, func(v [2]int) {
+		if v[1] != last[v[0]]+1 {
+			t.Errorf("Producer %d: expected %d, got %d", v[0], last[v[0]]+1, v[1])
+		}
+		last[v[0]] = v[1]
+		received++
+	})
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			pub := d.Publisher(window)
+			for i := 0; i < n; i++ {
+				if err := pub.Publish([2]int{p, i}); err != nil {
+					t.Errorf("Publish failed: %v", err)
+					return
+				}
+			}
+			if pub.Pending() != n%window {
+				t.Errorf("Expected %d pending events, got %d", n%window, pub.Pending())
+			}
+			if err := pub.Flush(); err != nil {
+				t.Errorf("Flush failed: %v", err)
+			}
+		}(p)
+	}
+	wg.Wait()
+	if err := d.Close(context.Background()); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if received != producers*n {
+		t.Fatalf("Expected %d events, got %d", producers*n, received)
+	}
+}
+
+func TestPublisher_FlushClosed(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	d, err := Disruptor[int](ctx, 8, func(int) {})
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	pub := d.Publisher(4)
+	for i := 0; i < 3; i++ {
+		if err := pub.Publish(i); err != nil {
+			t.Fatalf("Publish failed: %v", err)
+		}
+	}
+	if err := d.Close(context.Background()); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	err = pub.Flush()
+	if !errors.Is(err, ErrClosed) || err.Error() != ErrClosed.Error()+": 3 of 3 events not published" {
+		t.Errorf("Expected ErrClosed for 3 lost events, got %v", err)
+	}
+	if pub.Pending() != 0 {
+		t.Errorf("Expected an empty window, got %d", pub.Pending())
+	}
+}
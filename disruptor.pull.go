@@ -0,0 +1,87 @@
+package ring
+
+import (
+	"context"
+	"github.com/dk-open/ring/pad"
+	"iter"
+)
+
+type pullReader[T any] struct {
+	tail pad.AtomicUint64
+	d    *disruptor[T]
+	id   uint64
+}
+
+// NewReader returns a pull-style reader. Its cursor joins the gating barrier
+// at the current writer cursor, so it sees every event published afterwards.
+// A pull reader must be used by a single goroutine.
+func (d *disruptor[T]) NewReader() IDisruptorRing[T] {
+	r := &pullReader[T]{d: d, id: d.readerIDs.Add(1) - 1}
+	r.tail.Store(d.writerCursor.Load() &^ 1)
+	d.addGating(&r.tail)
+	// A producer that checked for room before the cursor joined may have
+	// lapped it, the reader then starts over at the writer
+	if head := d.writerCursor.Load() &^ 1; head-r.tail.Load() >= d.capX2 {
+		r.tail.Store(head)
+	}
+	d.registry.add(r)
+	return r
+}
+
+func (r *pullReader[T]) Dequeue() (res T, ok bool) {
+	_, res, ok = r.next()
+	return res, ok
+}
+
+// next takes the next event and returns it with its sequence number.
+func (r *pullReader[T]) next() (seq uint64, res T, ok bool) {
+	tail := r.tail.Load()
+	if tail >= r.d.writerCursor.Load()&^1 {
+		return
+	}
+	if r.d.policy == DropOldest {
+		for !ok {
+			if res, ok = r.d.claim(&r.tail, tail); !ok {
+				if tail = r.tail.Load(); tail >= r.d.writerCursor.Load()&^1 {
+					return
+				}
+			}
+		}
+		r.d.occupancy()
+		return tail >> 1, res, true
+	}
+	res = *r.d.slot(tail)
+	r.tail.Store(tail + 2)
+	r.d.occupancy()
+	return tail >> 1, res, true
+}
+
+// Events waits for events with the readers' idle backoff. The reader gates
+// Close like any other, so a loop over a closing disruptor sees every event
+// published before it ends. The reader stays attached after the loop.
+func (r *pullReader[T]) Events(ctx context.Context) iter.Seq2[uint64, T] {
+	return func(yield func(uint64, T) bool) {
+		var attempt uint64
+		for ctx.Err() == nil {
+			seq, v, ok := r.next()
+			if ok {
+				attempt = 0
+				if !yield(seq, v) {
+					return
+				}
+				continue
+			}
+			// An odd cursor is a publish in flight, it still gets delivered
+			if r.d.closed.Load() && r.tail.Load() >= (r.d.writerCursor.Load()+1)&^1 {
+				return
+			}
+			defaultIdle.park(attempt, ctx.Done())
+			attempt++
+		}
+	}
+}
+
+func (r *pullReader[T]) Close() {
+	r.d.readerBarrier.Remove(&r.tail)
+	r.d.registry.remove(r)
+}
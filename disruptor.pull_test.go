@@ -0,0 +1,112 @@
+package ring
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+)
+
+func TestNewReader_Dequeue(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	d, err := Disruptor[int](ctx, 4)
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	r1 := d.NewReader()
+	r2 := d.NewReader()
+	if _, ok := r1.Dequeue(); ok {
+		t.Fatal("Expected empty reader")
+	}
+
+	for i := 0; i < 4; i++ {
+		if !d.Enqueue(i) {
+			t.Fatalf("Failed to enqueue item %d", i)
+		}
+	}
+	if d.Enqueue(4) {
+		t.Fatal("Expected enqueue to be gated by pull readers")
+	}
+	for i := 0; i < 4; i++ {
+		if v, ok := r1.Dequeue(); !ok || v != i {
+			t.Fatalf("Expected %d, got %d %v", i, v, ok)
+		}
+	}
+	if d.Enqueue(4) {
+		t.Fatal("Expected enqueue to be gated by the slowest pull reader")
+	}
+
+	r2.Close()
+	if !d.Enqueue(4) {
+		t.Fatal("Expected enqueue to succeed after closing the slow reader")
+	}
+	if v, ok := r1.Dequeue(); !ok || v != 4 {
+		t.Fatalf("Expected 4, got %d %v", v, ok)
+	}
+}
+
+func TestNewReader_JoinsRunningWriter(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	d, err := Disruptor[uint64](ctx, 4)
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	// Without readers the producer never waits, it laps a joining reader
+	// unless the reader checks for it
+	var stop atomic.Bool
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for seq := uint64(0); !stop.Load(); {
+			if d.Enqueue(seq) {
+				seq++
+			}
+		}
+	}()
+	for i := 0; i < 2000; i++ {
+		r := d.NewReader().(*pullReader[uint64])
+		if seq, v, ok := r.next(); ok && v != seq {
+			t.Fatalf("Expected event %d, got %d", seq, v)
+		}
+		r.Close()
+	}
+	stop.Store(true)
+	<-done
+}
+
+func TestNewReader_Events(t *testing.T) {
+	d, err := Disruptor[int](context.Background(), 4)
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	r := d.NewReader()
+	go func() {
+		for i := 0; i < 10; i++ {
+			d.MustEnqueue(i * 10)
+		}
+		d.Close(context.Background())
+	}()
+	var want uint64
+	for seq, v := range r.Events(context.Background()) {
+		if seq != want || v != int(want)*10 {
+			t.Fatalf("Expected event %d with value %d, got %d with %d", want, want*10, seq, v)
+		}
+		want++
+	}
+	if want != 10 {
+		t.Errorf("Expected the loop to end after the 10 events published before Close, got %d", want)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	open, err := Disruptor[int](ctx, 4)
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	cancel()
+	for range open.NewReader().Events(ctx) {
+		t.Fatal("Expected no events after ctx is done")
+	}
+}
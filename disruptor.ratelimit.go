@@ -0,0 +1,78 @@
+package ring
+
+import (
+	"github.com/dk-open/ring/pad"
+	"time"
+)
+
+// rateLimiter is a token bucket kept as the theoretical arrival time of the
+// next event in a single padded atomic (GCRA): a token is available while the
+// arrival time is less than burst intervals ahead of the clock.
+type rateLimiter struct {
+	tat      pad.AtomicInt64
+	start    time.Time
+	interval int64
+	burst    int64
+}
+
+func newRateLimiter(rate float64, burst int) *rateLimiter {
+	interval := max(int64(float64(time.Second)/rate), 1)
+	return &rateLimiter{start: time.Now(), interval: interval, burst: int64(max(burst, 1)) * interval}
+}
+
+// take claims up to n tokens and returns how many it got.
+func (l *rateLimiter) take(n int) int {
+	for {
+		now := int64(time.Since(l.start))
+		tat := l.tat.Load()
+		base := max(tat, now)
+		available := (now + l.burst - base) / l.interval
+		if available <= 0 {
+			return 0
+		}
+		k := min(int64(n), available)
+		if l.tat.CompareAndSwap(tat, base+k*l.interval) {
+			return int(k)
+		}
+	}
+}
+
+// refund returns n tokens claimed for events that were not published.
+func (l *rateLimiter) refund(n int) {
+	if n > 0 {
+		l.tat.Add(-int64(n) * l.interval)
+	}
+}
+
+// next returns the time until the next token is available.
+func (l *rateLimiter) next() time.Duration {
+	return max(time.Duration(l.tat.Load()+l.interval-l.burst)-time.Since(l.start), 0)
+}
+
+// throttle waits until the rate limit admits up to n events and returns how
+// many it admitted. Producers on a disruptor with a drop policy never wait,
+// they get ErrRateLimited instead.
+func (d *disruptor[T]) throttle(n int) (int, error) {
+	if d.limiter == nil {
+		return n, nil
+	}
+	for {
+		if d.closed.Load() {
+			return 0, ErrClosed
+		}
+		if k := d.limiter.take(n); k > 0 {
+			return k, nil
+		}
+		if d.policy != Block {
+			d.failedEnqueues.Add(1)
+			return 0, ErrRateLimited
+		}
+		t := time.NewTimer(d.limiter.next())
+		select {
+		case <-t.C:
+		case <-d.ctx.Done():
+			t.Stop()
+			return 0, ErrClosed
+		}
+	}
+}
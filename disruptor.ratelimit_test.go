@@ -0,0 +1,102 @@
+package ring
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDisruptor_MaxRateEnqueue(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	d, err := NewDisruptor[int](ctx, WithCapacity(64), WithMaxRate(10, 5))
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		if !d.Enqueue(i) {
+			t.Fatalf("Expected event %d of the burst to be published", i)
+		}
+	}
+	if d.Enqueue(5) {
+		t.Fatal("Expected the event after the burst to be rate limited")
+	}
+	if n := d.EnqueueBatch([]int{5, 6}); n != 0 {
+		t.Fatalf("Expected no batch events past the burst, got %d", n)
+	}
+	time.Sleep(110 * time.Millisecond)
+	if n := d.EnqueueBatch([]int{5, 6, 7}); n != 1 {
+		t.Errorf("Expected one token after an interval, got %d", n)
+	}
+	if got := d.Published(); got != 6 {
+		t.Errorf("Expected 6 published events, got %d", got)
+	}
+	if st := d.Stats(); st.FailedEnqueues != 2 {
+		t.Errorf("Expected 2 failed enqueues, got %d", st.FailedEnqueues)
+	}
+}
+
+func TestDisruptor_MaxRateMustEnqueue(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	d, err := NewDisruptor[int](ctx, WithCapacity(64), WithMaxRate(1000, 1))
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	start := time.Now()
+	for i := 0; i < 10; i++ {
+		if err := d.MustEnqueue(i); err != nil {
+			t.Fatalf("MustEnqueue failed: %v", err)
+		}
+	}
+	if err := d.MustEnqueueBatch([]int{10, 11, 12, 13, 14, 15, 16, 17, 18, 19}); err != nil {
+		t.Fatalf("MustEnqueueBatch failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 19*time.Millisecond {
+		t.Errorf("Expected 20 events at 1000/s to take 19ms, took %v", elapsed)
+	}
+	if got := d.Published(); got != 20 {
+		t.Errorf("Expected 20 published events, got %d", got)
+	}
+}
+
+func TestDisruptor_MaxRateDropPolicy(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	d, err := NewDisruptor[int](ctx, WithCapacity(64), WithMaxRate(1, 1), WithSlowConsumerPolicy(DropNewest))
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	if err := d.MustEnqueue(1); err != nil {
+		t.Fatalf("MustEnqueue failed: %v", err)
+	}
+	if err := d.MustEnqueue(2); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("Expected ErrRateLimited, got %v", err)
+	}
+}
+
+func TestDisruptor_MaxRateClose(t *testing.T) {
+	d, err := NewDisruptor[int](context.Background(), WithCapacity(64), WithMaxRate(1, 1))
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	d.Enqueue(1)
+	errs := make(chan error, 1)
+	go func() { errs <- d.MustEnqueue(2) }()
+	time.Sleep(10 * time.Millisecond)
+	if err := d.Close(context.Background()); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	select {
+	case err := <-errs:
+		if !errors.Is(err, ErrClosed) {
+			t.Errorf("Expected ErrClosed, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected Close to release a producer waiting for the rate limit")
+	}
+}
@@ -2,58 +2,495 @@ package ring
 
 import (
 	"context"
+	"github.com/dk-open/ring/latency"
 	"github.com/dk-open/ring/pad"
 	"runtime"
+	"runtime/pprof"
+	"strconv"
+	"sync/atomic"
 	"time"
 )
 
+// ReaderHandle controls a running reader.
+type ReaderHandle[T any] interface {
+	// Suspend removes the reader from the gating barrier and drops the events
+	// published while it is suspended.
+	Suspend()
+	// SuspendTo keeps the reader draining the ring into side without invoking
+	// its callback. Events that do not fit into side are dropped.
+	SuspendTo(side IQueue[T])
+	// Pause stops the reader and removes it from the gating barrier while it
+	// keeps its position, producers are never stalled by a paused reader. The
+	// catch-up window is the ring: events the writer overwrote meanwhile are
+	// skipped on Resume. Use SuspendTo to backlog more events than the ring
+	// holds.
+	Pause()
+	// Resume rejoins the gating barrier at the current cursor, or at the
+	// position of a paused reader. Events buffered by SuspendTo are delivered
+	// first, with UnknownSequence for sequenced readers.
+	Resume()
+	// Busy returns the cumulative time spent inside the reader's callback.
+	Busy() time.Duration
+	// Processed returns the number of events consumed by the reader.
+	Processed() uint64
+	// Sequence returns the number of events the reader has passed, which is
+	// where a reader created with FromSequence resumes.
+	Sequence() uint64
+	// Close stops the reader after its current batch and detaches it from the
+	// gating barrier.
+	Close()
+	// Ack acknowledges an event completed by an asynchronous worker of a
+	// reader created with WithInflightLimit. Acks beyond the outstanding
+	// events, or on a reader without the option, are ignored.
+	Ack()
+}
+
+type suspension[T any] struct {
+	side  IQueue[T]
+	pause bool
+}
+
 type disruptorReader[T any] struct {
-	tail pad.AtomicUint64
-	d    *disruptor[T]
-	f    ReaderCallback[T]
+	tail    pad.AtomicUint64
+	d       *disruptor[T]
+	barrier pad.Barrier
+	work    *pad.AtomicUint64
+	f       ReaderCallback[T]
+	sf      SequencedReaderCallback[T]
+	standby func() bool
+	ctx     context.Context
+	cancel  context.CancelFunc
+	readerOptions
+
+	inflight  atomic.Int64
+	busy      atomic.Int64
+	processed atomic.Uint64
+	suspended atomic.Pointer[suspension[T]]
+	gating    atomic.Bool
+	group     string
+	// owned by the reader goroutine
+	gated bool
+	side  IQueue[T]
+	kept  bool
+	// start of the idle period reported by onIdle
+	idleSince time.Time
+	// adaptive spin budget
+	spins uint64
+	// micro-batch of a coalescing reader
+	coalescer *coalescer[T]
+	// sequence last handed to the checkpointer
+	checkpointed uint64
+	// publish to callback return, nil unless WithLatencyTracking
+	latency *latency.Histogram
+	// number of the reader within the disruptor, in registration order
+	id uint64
 }
 
-func runReader[T any](ctx context.Context, d *disruptor[T], f ReaderCallback[T]) pad.Barrier {
-	r := &disruptorReader[T]{
-		d: d,
-		f: f,
+// runReader starts a reader that consumes every sequence published below the
+// given barrier, which is either the writer cursor or the upstream stage. The
+// caller positions the reader's tail and, for a gating reader, adds it to the
+// gating barrier before the reader runs.
+func runReader[T any](ctx context.Context, r *disruptorReader[T]) *disruptorReader[T] {
+	r.checkpointed = r.tail.Load() >> 1
+	r.ctx, r.cancel = context.WithCancel(ctx)
+	ctx = r.ctx
+	r.gating.Store(r.gated)
+	r.register()
+	r.d.readers.Add(1)
+	r.spins = r.d.maxSpins
+	if r.onIdle != nil {
+		r.idleSince = time.Now()
 	}
-	go func() {
+	r.start("reader", func() {
+		defer r.exit()
 		var attempt uint64
 		for {
 			select {
 			case <-ctx.Done():
 				return
 			default:
+				if !r.sync() {
+					readerYield(attempt)
+					attempt++
+					continue
+				}
 				tail := r.tail.Load()
-				if head := r.d.writerCursor.Load(); tail+1 < head {
-					for tail < head {
-						r.f(r.d.buffer[tail>>1&r.d.capMask])
-						tail += 2
+				// An odd sequence is still being written, only even ones are committed.
+				if head := r.barrier.Load() &^ 1; tail < head {
+					if limit := r.d.maxBatch; limit > 0 && head-tail > 2*limit {
+						head = tail + 2*limit
 					}
-					r.tail.Store(tail)
+					// Accounted per batch to keep clock reads off the per-event path
+					start, events := time.Now(), (head-tail)>>1
+					if r.d.policy == DropOldest {
+						events = r.consumeOverwritten(tail, head)
+					} else {
+						for tail < head {
+							r.deliver(tail>>1, *r.d.slot(tail), tail+2 == head)
+							tail += 2
+						}
+						r.tail.Store(tail)
+					}
+					if r.coalescer != nil {
+						r.coalescer.expire()
+					}
+					r.account(start, events)
+					if r.checkpointer != nil {
+						r.checkpoint(false)
+					}
+					if r.onIdle != nil {
+						r.idleSince = time.Now()
+					}
+					r.d.occupancy()
+					r.d.signal()
+					r.adapt(attempt)
 					attempt = 0 // reset attempt counter after successful read
 					continue
 				}
-				readerYield(attempt)
+				r.park(attempt, tail)
 				attempt++
 			}
 
 		}
-	}()
+	})
+
+	return r
+}
+
+// register numbers the reader and adds it to the readers reported by Stats.
+func (r *disruptorReader[T]) register() {
+	r.id = r.d.readerIDs.Add(1) - 1
+	if r.d.stamps != nil {
+		r.latency = &latency.Histogram{}
+	}
+	r.d.registry.add(r)
+}
+
+// start runs the reader's loop on a goroutine labelled with the disruptor's
+// name and the reader's number, group and kind, so CPU profiles and goroutine
+// dumps tell the readers of different rings apart. The goroutine inherits the
+// labels when it is created, before it is first scheduled.
+func (r *disruptorReader[T]) start(kind string, loop func()) {
+	labels := []string{"ring", r.d.name, "reader", strconv.FormatUint(r.id, 10), "kind", kind}
+	if r.group != "" {
+		labels = append(labels, "group", r.group)
+	}
+	r.d.launch(func() {
+		pprof.Do(r.ctx, pprof.Labels(labels...), func(context.Context) {
+			go loop()
+		})
+	})
+}
+
+// consumeOverwritten delivers the batch of a DropOldest disruptor, claiming
+// every event before its slot can be overwritten. It stops early if the
+// producer moved the reader on and returns the events delivered.
+func (r *disruptorReader[T]) consumeOverwritten(tail, head uint64) uint64 {
+	var events uint64
+	for ; tail < head; tail += 2 {
+		v, ok := r.d.claim(&r.tail, tail)
+		if !ok {
+			break
+		}
+		r.deliver(tail>>1, v, tail+2 == head)
+		events++
+	}
+	return events
+}
+
+func (r *disruptorReader[T]) Busy() time.Duration {
+	return time.Duration(r.busy.Load())
+}
 
-	return &r.tail
+func (r *disruptorReader[T]) Processed() uint64 {
+	return r.processed.Load()
 }
 
+func (r *disruptorReader[T]) account(start time.Time, events uint64) {
+	r.busy.Add(int64(time.Since(start)))
+	r.processed.Add(events)
+}
+
+func (r *disruptorReader[T]) Close() {
+	r.cancel()
+}
+
+func (r *disruptorReader[T]) exit() {
+	if r.coalescer != nil {
+		r.coalescer.flushBatch()
+	}
+	if r.checkpointer != nil {
+		r.checkpoint(true)
+	}
+	if r.gated {
+		r.d.readerBarrier.Remove(&r.tail)
+		r.setGated(false)
+	}
+	r.d.registry.remove(r)
+	r.d.readers.Done()
+}
+
+func (r *disruptorReader[T]) Suspend() {
+	r.suspended.Store(&suspension[T]{})
+}
+
+func (r *disruptorReader[T]) SuspendTo(side IQueue[T]) {
+	r.suspended.Store(&suspension[T]{side: side})
+}
+
+func (r *disruptorReader[T]) Pause() {
+	r.suspended.Store(&suspension[T]{pause: true})
+}
+
+func (r *disruptorReader[T]) Resume() {
+	r.suspended.Store(nil)
+}
+
+// sync applies standby and suspension changes between batches and reports
+// whether the reader should consume the ring.
+func (r *disruptorReader[T]) sync() bool {
+	if r.standby != nil {
+		if !r.standby() {
+			r.follow()
+			return false
+		}
+		r.standby = nil
+	}
+	s := r.suspended.Load()
+	if s != nil && s.side == nil {
+		if r.gated {
+			r.d.readerBarrier.Remove(&r.tail)
+			r.setGated(false)
+		}
+		r.kept = s.pause
+		if !r.kept {
+			r.follow()
+		}
+		return false
+	}
+	if !r.gated {
+		r.join()
+	}
+	if s != nil {
+		r.side = s.side
+	} else if r.side != nil {
+		for v, ok := r.side.Dequeue(); ok; v, ok = r.side.Dequeue() {
+			r.call(UnknownSequence, v, true)
+		}
+		r.side = nil
+	}
+	return true
+}
+
+func (r *disruptorReader[T]) deliver(seq uint64, v T, endOfBatch bool) {
+	if r.side != nil {
+		r.side.Enqueue(v)
+		return
+	}
+	if r.inflightLimit > 0 {
+		r.acquire()
+	}
+	if r.latency == nil {
+		r.call(seq, v, endOfBatch)
+		return
+	}
+	published := r.d.stamps.published(seq)
+	r.call(seq, v, endOfBatch)
+	r.d.stamps.record(r.latency, published)
+}
+
+func (r *disruptorReader[T]) call(seq uint64, v T, endOfBatch bool) {
+	if r.coalescer != nil {
+		if r.f != nil {
+			r.f(v)
+		}
+		r.coalescer.add(v)
+		return
+	}
+	if r.sf != nil {
+		r.sf(seq, v, endOfBatch)
+		return
+	}
+	r.f(v)
+}
+
+func (r *disruptorReader[T]) Ack() {
+	for {
+		n := r.inflight.Load()
+		if n <= 0 || r.inflight.CompareAndSwap(n, n-1) {
+			return
+		}
+	}
+}
+
+// acquire waits until fewer than inflightLimit events are outstanding.
+func (r *disruptorReader[T]) acquire() {
+	var attempt uint64
+	for r.inflight.Load() >= r.inflightLimit && r.ctx.Err() == nil {
+		readerYield(attempt)
+		attempt++
+	}
+	r.inflight.Add(1)
+}
+
+// follow moves a reader that does not gate the writer along with its barrier.
+func (r *disruptorReader[T]) follow() {
+	if r.work != nil {
+		r.tail.Store(r.work.Load())
+		return
+	}
+	r.tail.Store(r.barrier.Load() &^ 1)
+}
+
+// join adds the reader to the gating barrier at its current tail. If the writer
+// lapped the reader before it joined, the tail is moved forward again.
+//
+// A paused reader rejoins at its own position instead, moved past the events
+// the writer may have overwritten as for a FromOldest reader.
+func (r *disruptorReader[T]) join() {
+	if r.kept && r.work == nil {
+		r.kept = false
+		r.d.addGating(&r.tail)
+		r.setGated(true)
+		if oldest := r.d.oldest(r.d.writerCursor.Load() &^ 1); oldest > r.tail.Load() {
+			r.tail.Store(oldest)
+		}
+		return
+	}
+	r.kept = false
+	r.follow()
+	r.d.addGating(&r.tail)
+	r.setGated(true)
+	head := r.barrier.Load() &^ 1
+	if r.work != nil {
+		if next := r.work.Load(); next < head && head-next >= r.d.capX2 {
+			r.work.CompareAndSwap(next, head)
+		}
+		r.tail.Store(r.work.Load())
+		return
+	}
+	if head-r.tail.Load() >= r.d.capX2 {
+		r.tail.Store(head)
+	}
+}
+
+func (r *disruptorReader[T]) setGated(gated bool) {
+	r.gated = gated
+	r.gating.Store(gated)
+}
+
+// park backs off while the reader waits for the sequence next, fires the idle
+// callback once the reader was idle past its threshold and flushes a due
+// coalesced batch. With priority inheritance a blocked producer cuts the sleep
+// short.
+func (r *disruptorReader[T]) park(attempt, next uint64) {
+	if r.coalescer != nil {
+		r.coalescer.expire()
+	}
+	if r.onIdle != nil {
+		if now := time.Now(); now.Sub(r.idleSince) >= r.idleAfter {
+			r.onIdle(next >> 1)
+			r.idleSince = now
+		}
+	}
+	if r.d.maxSpins > 0 {
+		if attempt < r.spins {
+			pad.SpinHint() // the caller checks the barrier again
+			return
+		}
+		attempt -= r.spins
+	}
+	if r.d.blocking && attempt >= r.d.idle.yields {
+		r.block(next)
+		return
+	}
+	var wake <-chan struct{}
+	if ch := r.d.wake.Load(); ch != nil {
+		wake = *ch
+	}
+	r.d.idle.park(attempt, wake)
+}
+
+// adapt tunes the spin budget after the reader waited attempt idle rounds for
+// its batch: an event that arrived before the reader parked extends it, a wait
+// that ended parked halves it.
+func (r *disruptorReader[T]) adapt(attempt uint64) {
+	switch {
+	case r.d.maxSpins == 0 || attempt == 0:
+	case attempt < r.spins+r.d.idle.yields:
+		r.spins = min(max(2*r.spins, 1), r.d.maxSpins)
+	default:
+		r.spins /= 2
+	}
+}
+
+// block parks the reader until the barrier passes next. The waiter is counted
+// before the barrier is checked again, so a producer either sees the waiter
+// and closes the channel loaded here or published before the check.
+func (r *disruptorReader[T]) block(next uint64) {
+	r.d.waiters.Add(1)
+	defer r.d.waiters.Add(-1)
+	wake := *r.d.wake.Load()
+	if r.barrier.Load()&^1 > next {
+		return
+	}
+	var idle <-chan time.Time
+	if r.onIdle != nil {
+		t := time.NewTimer(r.idleAfter - time.Since(r.idleSince))
+		defer t.Stop()
+		idle = t.C
+	}
+	var flush <-chan time.Time
+	if r.coalescer != nil {
+		if d, ok := r.coalescer.remaining(); ok {
+			t := time.NewTimer(d)
+			defer t.Stop()
+			flush = t.C
+		}
+	}
+	select {
+	case <-wake:
+	case <-idle:
+	case <-flush:
+	case <-r.ctx.Done():
+	}
+}
+
+// idleBackoff spins briefly and yields to the scheduler for the first yields
+// attempts, then sleeps with an interval doubling up to maxSleep.
+type idleBackoff struct {
+	yields   uint64
+	maxSleep time.Duration
+}
+
+var defaultIdle = idleBackoff{yields: 20, maxSleep: time.Millisecond}
+
 func readerYield(attempt uint64) {
+	defaultIdle.park(attempt, nil)
+}
+
+// park backs off like readerYield, a sleep ends early once wake is closed.
+func (b idleBackoff) park(attempt uint64, wake <-chan struct{}) {
 	switch {
-	case attempt < 20:
+	case attempt < min(spinAttempts, b.yields):
+		pad.SpinHint()
+	case attempt < b.yields:
 		runtime.Gosched() // Let Go scheduler run another goroutine
 	default:
-		d := time.Microsecond << uint(attempt-20)
-		if d > time.Millisecond {
-			d = time.Millisecond
+		// The shift is bounded, a wrapped duration would turn the sleep into a spin
+		d := b.maxSleep
+		if shift := attempt - b.yields; shift < 20 {
+			d = min(time.Microsecond<<shift, b.maxSleep)
+		}
+		if wake == nil {
+			time.Sleep(d)
+			return
+		}
+		t := time.NewTimer(d)
+		select {
+		case <-t.C:
+		case <-wake:
+			t.Stop()
 		}
-		time.Sleep(d)
 	}
 }
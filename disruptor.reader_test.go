@@ -0,0 +1,531 @@
+package ring
+
+import (
+	"context"
+	"errors"
+	"runtime/pprof"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func waitGated(d *disruptor[int], n int) {
+	for {
+		if len(d.readerBarrier.Members()) == n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestReaderHandle_SuspendDoesNotStallProducers(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var count atomic.Int64
+	received := make(chan int, 64)
+	d, err := Disruptor[int](ctx, 4)
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	g := d.HandleWith(func(value int) {
+		count.Add(1)
+		received <- value
+	})
+	h := g.Handles()[0]
+
+	h.Suspend()
+	waitGated(d.(*disruptor[int]), 0)
+	for i := 0; i < 20; i++ {
+		if !d.Enqueue(i) {
+			t.Fatalf("Failed to enqueue item %d while reader is suspended", i)
+		}
+	}
+	if c := count.Load(); c != 0 {
+		t.Errorf("Expected no events while suspended, got %d", c)
+	}
+
+	h.Resume()
+	waitGated(d.(*disruptor[int]), 1)
+	for i := 100; i < 110; i++ {
+		if err := d.MustEnqueue(i); err != nil {
+			t.Fatalf("MustEnqueue failed: %v", err)
+		}
+	}
+	for i := 100; i < 110; i++ {
+		if v := <-received; v != i {
+			t.Fatalf("Expected %d, got %d", i, v)
+		}
+	}
+}
+
+func TestReaderHandle_SuspendTo(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	received := make(chan int, 64)
+	d, err := Disruptor[int](ctx, 8)
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	side, err := Queue[int](16)
+	if err != nil {
+		t.Fatalf("Failed to create queue: %v", err)
+	}
+	h := d.HandleWith(func(value int) { received <- value }).Handles()[0]
+
+	h.SuspendTo(side)
+	time.Sleep(10 * time.Millisecond)
+	for i := 0; i < 10; i++ {
+		if err := d.MustEnqueue(i); err != nil {
+			t.Fatalf("MustEnqueue failed: %v", err)
+		}
+	}
+	time.Sleep(10 * time.Millisecond)
+	select {
+	case v := <-received:
+		t.Fatalf("Expected no callback while suspended, got %d", v)
+	default:
+	}
+
+	h.Resume()
+	for i := 0; i < 10; i++ {
+		if v := <-received; v != i {
+			t.Fatalf("Expected buffered %d, got %d", i, v)
+		}
+	}
+}
+
+func TestReaderHandle_Busy(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	d, err := Disruptor[int](ctx, 8)
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	slow := d.HandleWith(func(value int) { time.Sleep(2 * time.Millisecond) }).Handles()[0]
+	fast := d.HandleWith(func(value int) {}).Handles()[0]
+	pool := d.HandleWithWorkerPool(func(value int) { time.Sleep(time.Millisecond) }).Handles()[0]
+
+	for i := 0; i < 5; i++ {
+		if err := d.MustEnqueue(i); err != nil {
+			t.Fatalf("MustEnqueue failed: %v", err)
+		}
+	}
+	if err := d.Close(context.Background()); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	for _, h := range []ReaderHandle[int]{slow, fast, pool} {
+		if p := h.Processed(); p != 5 {
+			t.Errorf("Expected 5 processed events, got %d", p)
+		}
+	}
+	if slow.Busy() < 10*time.Millisecond {
+		t.Errorf("Expected slow reader to be busy for at least 10ms, got %v", slow.Busy())
+	}
+	if pool.Busy() < 5*time.Millisecond {
+		t.Errorf("Expected worker to be busy for at least 5ms, got %v", pool.Busy())
+	}
+	if fast.Busy() >= slow.Busy() {
+		t.Errorf("Expected fast reader %v to be cheaper than slow reader %v", fast.Busy(), slow.Busy())
+	}
+}
+
+func TestAddReader_Close(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	d, err := Disruptor[int](ctx, 4, func(value int) {})
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	for i := 0; i < 10; i++ {
+		if err := d.MustEnqueue(i); err != nil {
+			t.Fatalf("MustEnqueue failed: %v", err)
+		}
+	}
+
+	block := make(chan struct{})
+	received := make(chan int, 1)
+	h, err := d.AddReader(func(value int) {
+		select {
+		case received <- value:
+		default:
+		}
+		<-block
+	})
+	if err != nil {
+		t.Fatalf("AddReader failed: %v", err)
+	}
+	if err := d.MustEnqueue(10); err != nil {
+		t.Fatalf("MustEnqueue failed: %v", err)
+	}
+	if v := <-received; v != 10 {
+		t.Errorf("Expected new reader to start at the writer cursor, got %d", v)
+	}
+
+	h.Close()
+	close(block)
+	waitGated(d.(*disruptor[int]), 1)
+	for i := 11; i < 30; i++ {
+		if err := d.MustEnqueue(i); err != nil {
+			t.Fatalf("MustEnqueue failed after closing reader: %v", err)
+		}
+	}
+
+	if err := d.Close(context.Background()); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if _, err := d.AddReader(func(value int) {}); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected ErrClosed, got %v", err)
+	}
+}
+
+func TestAddReader_JoinsRunningWriter(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	d, err := Disruptor[uint64](ctx, 4)
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	// Without other readers the producer never waits, it laps a joining
+	// reader unless the reader checks for it
+	var stop atomic.Bool
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for seq := uint64(0); !stop.Load(); {
+			if d.Enqueue(seq) {
+				seq++
+			}
+		}
+	}()
+	for i := 0; i < 200; i++ {
+		// A lapped reader sees an event of a later lap and that event again
+		// once it gets to its slot
+		values := make(chan uint64, 8)
+		h, err := d.AddReader(func(v uint64) {
+			select {
+			case values <- v:
+			default:
+			}
+		})
+		if err != nil {
+			t.Fatalf("AddReader failed: %v", err)
+		}
+		prev := <-values
+		for j := 0; j < 7; j++ {
+			if v := <-values; v != prev+1 {
+				t.Fatalf("Expected event %d after %d, got %d", prev+1, prev, v)
+			}
+			prev++
+		}
+		h.Close()
+	}
+	stop.Store(true)
+	<-done
+}
+
+func TestAddReader_InflightLimit(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	d, err := Disruptor[int](ctx, 16)
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	work := make(chan int, 16)
+	var outstanding, peak atomic.Int64
+	h, err := d.AddReader(func(value int) {
+		if o := outstanding.Add(1); o > peak.Load() {
+			peak.Store(o)
+		}
+		work <- value
+	}, WithInflightLimit(3))
+	if err != nil {
+		t.Fatalf("AddReader failed: %v", err)
+	}
+
+	for i := 0; i < 10; i++ {
+		if err := d.MustEnqueue(i); err != nil {
+			t.Fatalf("MustEnqueue failed: %v", err)
+		}
+	}
+	time.Sleep(10 * time.Millisecond)
+	if n := len(work); n != 3 {
+		t.Fatalf("Expected 3 events in flight, got %d", n)
+	}
+
+	// Asynchronous worker acknowledging completed events
+	for i := 0; i < 10; i++ {
+		if v := <-work; v != i {
+			t.Fatalf("Expected %d, got %d", i, v)
+		}
+		outstanding.Add(-1)
+		h.Ack()
+	}
+	if p := peak.Load(); p > 3 {
+		t.Errorf("Expected at most 3 outstanding events, got %d", p)
+	}
+}
+
+func TestAddReader_InflightExtraAck(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	d, err := Disruptor[int](ctx, 16)
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	work := make(chan int, 16)
+	h, err := d.AddReader(func(value int) { work <- value }, WithInflightLimit(1))
+	if err != nil {
+		t.Fatalf("AddReader failed: %v", err)
+	}
+	// Acks without outstanding events must not raise the limit
+	h.Ack()
+	h.Ack()
+	for i := 0; i < 3; i++ {
+		if err := d.MustEnqueue(i); err != nil {
+			t.Fatalf("MustEnqueue failed: %v", err)
+		}
+	}
+	time.Sleep(10 * time.Millisecond)
+	if n := len(work); n != 1 {
+		t.Errorf("Expected 1 event in flight, got %d", n)
+	}
+	for i := 0; i < 3; i++ {
+		<-work
+		h.Ack()
+	}
+}
+
+func TestAddReader_FromOldest(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		published int
+		first     int
+	}{
+		{name: "partial", published: 3, first: 0},
+		{name: "wrapped", published: 20, first: 13},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			d, err := Disruptor[int](ctx, 8)
+			if err != nil {
+				t.Fatalf("Failed to create disruptor: %v", err)
+			}
+			for i := 0; i < tc.published; i++ {
+				if err := d.MustEnqueue(i); err != nil {
+					t.Fatalf("MustEnqueue failed: %v", err)
+				}
+			}
+			var received []int
+			if _, err := d.AddReader(func(value int) { received = append(received, value) }, FromOldest()); err != nil {
+				t.Fatalf("AddReader failed: %v", err)
+			}
+			for i := tc.published; i < tc.published+5; i++ {
+				if err := d.MustEnqueue(i); err != nil {
+					t.Fatalf("MustEnqueue failed: %v", err)
+				}
+			}
+			if err := d.Close(context.Background()); err != nil {
+				t.Fatalf("Close failed: %v", err)
+			}
+
+			if want := tc.published + 5 - tc.first; len(received) != want {
+				t.Fatalf("Expected %d events, got %v", want, received)
+			}
+			for i, v := range received {
+				if v != tc.first+i {
+					t.Fatalf("Expected %d at position %d, got %v", tc.first+i, i, received)
+				}
+			}
+		})
+	}
+}
+
+func TestReaderHandle_Pause(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		published int
+		first     int
+	}{
+		{name: "within window", published: 5, first: 3},
+		{name: "lapped", published: 30, first: 26},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			d, err := Disruptor[int](ctx, 8)
+			if err != nil {
+				t.Fatalf("Failed to create disruptor: %v", err)
+			}
+			var count atomic.Int64
+			var received []int
+			h, err := d.AddReader(func(value int) {
+				received = append(received, value)
+				count.Add(1)
+			})
+			if err != nil {
+				t.Fatalf("AddReader failed: %v", err)
+			}
+			for i := 0; i < 3; i++ {
+				if err := d.MustEnqueue(i); err != nil {
+					t.Fatalf("MustEnqueue failed: %v", err)
+				}
+			}
+			for count.Load() < 3 {
+				time.Sleep(time.Millisecond)
+			}
+
+			h.Pause()
+			waitGated(d.(*disruptor[int]), 0)
+			// A paused reader neither consumes nor stalls producers
+			for i := 3; i < 3+tc.published; i++ {
+				if err := d.MustEnqueue(i); err != nil {
+					t.Fatalf("MustEnqueue failed: %v", err)
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			if n := count.Load(); n != 3 {
+				t.Fatalf("Expected a paused reader to stop, got %d events", n)
+			}
+
+			h.Resume()
+			waitGated(d.(*disruptor[int]), 1)
+			if err := d.Close(context.Background()); err != nil {
+				t.Fatalf("Close failed: %v", err)
+			}
+			resumed := received[3:]
+			if want := 3 + tc.published - tc.first; len(resumed) != want {
+				t.Fatalf("Expected %d events after Resume, got %v", want, resumed)
+			}
+			for i, v := range resumed {
+				if v != tc.first+i {
+					t.Fatalf("Expected %d at position %d, got %v", tc.first+i, i, resumed)
+				}
+			}
+		})
+	}
+}
+
+func TestAddReader_IdleCallback(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	d, err := Disruptor[int](ctx, 16)
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	var idle, count atomic.Int64
+	h, err := d.AddReader(func(value int) { count.Add(1) }, WithIdleCallback(5*time.Millisecond, func() {
+		idle.Add(1)
+	}))
+	if err != nil {
+		t.Fatalf("AddReader failed: %v", err)
+	}
+	time.Sleep(30 * time.Millisecond)
+	if n := idle.Load(); n < 2 {
+		t.Fatalf("Expected repeated idle callbacks, got %d", n)
+	}
+
+	// A reader does not report idleness before its threshold
+	h.Close()
+	idle.Store(0)
+	if _, err := d.AddReader(func(value int) { count.Add(1) }, WithIdleCallback(time.Hour, func() {
+		idle.Add(1)
+	})); err != nil {
+		t.Fatalf("AddReader failed: %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		if err := d.MustEnqueue(i); err != nil {
+			t.Fatalf("MustEnqueue failed: %v", err)
+		}
+	}
+	time.Sleep(10 * time.Millisecond)
+	if n := idle.Load(); n != 0 {
+		t.Errorf("Expected no idle callback before the threshold, got %d", n)
+	}
+}
+
+func TestReader_ProfilerLabels(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	d, err := NewDisruptor[int](ctx, WithName("orders"))
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	d.HandleWith(func(int) {})
+	d.HandleWithGroup("journal", func(int) {})
+
+	var dump strings.Builder
+	if err := pprof.Lookup("goroutine").WriteTo(&dump, 1); err != nil {
+		t.Fatalf("Failed to write the goroutine profile: %v", err)
+	}
+	for _, want := range []string{
+		`"kind":"reader", "reader":"0", "ring":"orders"`,
+		`"group":"journal", "kind":"worker", "reader":"1", "ring":"orders"`,
+	} {
+		if !strings.Contains(dump.String(), want) {
+			t.Errorf("Expected labels %s in the goroutine dump:\n%s", want, dump.String())
+		}
+	}
+	stats := d.Stats()
+	if stats.Name != "orders" || len(stats.Readers) != 2 || stats.Readers[1].ID != 1 {
+		t.Errorf("Expected the name and reader IDs in Stats, got %+v", stats)
+	}
+}
+
+func TestAddReader_IdleTimeout(t *testing.T) {
+	for name, opts := range map[string][]Option{
+		"Backoff":  nil,
+		"Blocking": {WithBlockingWait()},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			d, err := NewDisruptor[int](ctx, append(opts, WithCapacity(16))...)
+			if err != nil {
+				t.Fatalf("Failed to create disruptor: %v", err)
+			}
+			sequences := make(chan uint64, 64)
+			if _, err := d.AddReader(func(int) {}, WithIdleTimeout(2*time.Millisecond, func(sequence uint64) {
+				select {
+				case sequences <- sequence:
+				default:
+				}
+			})); err != nil {
+				t.Fatalf("AddReader failed: %v", err)
+			}
+			for i := 0; i < 3; i++ {
+				if err := d.MustEnqueue(i); err != nil {
+					t.Fatalf("MustEnqueue failed: %v", err)
+				}
+			}
+			if err := d.WaitDrained(ctx); err != nil {
+				t.Fatalf("WaitDrained failed: %v", err)
+			}
+			timeout := time.After(time.Second)
+			for {
+				select {
+				case seq := <-sequences:
+					if seq == 3 {
+						return
+					}
+				case <-timeout:
+					t.Fatal("Expected an idle timeout reporting sequence 3")
+				}
+			}
+		})
+	}
+}
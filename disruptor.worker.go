@@ -0,0 +1,125 @@
+package ring
+
+import (
+	"context"
+	"github.com/dk-open/ring/pad"
+	"time"
+)
+
+// HandleWithWorkerPool starts a pool of workers sharing a single work cursor.
+// Unlike HandleWith, every event is processed exactly once by whichever worker
+// claims it first. The returned group gates Then stages on all workers.
+func (d *disruptor[T]) HandleWithWorkerPool(workers ...ReaderCallback[T]) *ReaderGroup[T] {
+	return d.startWorkerPool(&d.writerCursor, "", workers)
+}
+
+// HandleWithGroup starts a named consumer group. Groups receive every event
+// while the members of a group share the group's sequence and load-balance its
+// events, as with HandleWithWorkerPool.
+func (d *disruptor[T]) HandleWithGroup(name string, members ...ReaderCallback[T]) *ReaderGroup[T] {
+	return d.startWorkerPool(&d.writerCursor, name, members)
+}
+
+// ThenWorkerPool starts a worker pool gated by the sequences of this group.
+func (g *ReaderGroup[T]) ThenWorkerPool(workers ...ReaderCallback[T]) *ReaderGroup[T] {
+	return g.d.startWorkerPool(g.Barrier(), "", workers)
+}
+
+func (d *disruptor[T]) startWorkerPool(barrier pad.Barrier, name string, workers []ReaderCallback[T]) *ReaderGroup[T] {
+	g := &ReaderGroup[T]{d: d, name: name}
+	work := &pad.AtomicUint64{}
+	work.Store(barrier.Load() &^ 1)
+	tails := make([]*pad.AtomicUint64, 0, len(workers))
+	for _, f := range workers {
+		r := &disruptorReader[T]{d: d, barrier: barrier, work: work, f: f, group: name, gated: true}
+		r.tail.Store(work.Load())
+		g.readers = append(g.readers, r)
+		tails = append(tails, &r.tail)
+	}
+	d.gate(tails...)
+	if len(tails) > 0 {
+		// The tails moved together if they were lapped, the work cursor follows
+		work.Store(tails[0].Load())
+	}
+	for _, r := range g.readers {
+		runWorker(d.ctx, r)
+	}
+	return g
+}
+
+// runWorker starts a worker that claims sequences from the shared work cursor.
+// The worker's tail is parked at the sequence it is about to claim, so neither
+// the writer nor downstream stages pass an event that is still in progress.
+func runWorker[T any](ctx context.Context, r *disruptorReader[T]) *disruptorReader[T] {
+	work := r.work
+	r.tail.Store(work.Load())
+	r.ctx, r.cancel = context.WithCancel(ctx)
+	ctx = r.ctx
+	r.gating.Store(r.gated)
+	r.register()
+	r.d.readers.Add(1)
+	r.spins = r.d.maxSpins
+	r.start("worker", func() {
+		defer r.exit()
+		var attempt uint64
+		claimed, ok := uint64(0), false
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			default:
+				if !ok {
+					if !r.sync() {
+						readerYield(attempt)
+						attempt++
+						continue
+					}
+					next := work.Load()
+					r.tail.Store(next)
+					if !work.CompareAndSwap(next, next+2) {
+						continue
+					}
+					claimed, ok = next, true
+					r.d.signal()
+				}
+				if claimed < r.barrier.Load()&^1 {
+					start := time.Now()
+					if r.d.policy == DropOldest {
+						if v, claimedOK := r.claimOverwritten(claimed); claimedOK {
+							r.deliver(claimed>>1, v, true)
+							r.account(start, 1)
+						}
+					} else {
+						r.deliver(claimed>>1, *r.d.slot(claimed), true)
+						r.account(start, 1)
+					}
+					r.d.occupancy()
+					ok = false
+					r.adapt(attempt)
+					attempt = 0
+					continue
+				}
+				r.park(attempt, claimed)
+				attempt++
+			}
+		}
+	})
+	return r
+}
+
+// claimOverwritten copies the claimed event of a DropOldest disruptor. The
+// worker's tail is parked at claimed again while the event is processed. The
+// shared work cursor is not moved by producers, so a claimed sequence that the
+// writer lapped is skipped.
+func (r *disruptorReader[T]) claimOverwritten(claimed uint64) (v T, ok bool) {
+	if !r.tail.CompareAndSwap(claimed, claimed+1) {
+		return v, false
+	}
+	if r.d.writerCursor.Load()-claimed >= 2*r.d.cap {
+		r.tail.Store(claimed)
+		return v, false
+	}
+	v = *r.d.slot(claimed)
+	r.tail.Store(claimed)
+	return v, true
+}
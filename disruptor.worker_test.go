@@ -0,0 +1,134 @@
+package ring
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHandleWithWorkerPool_ExactlyOnce(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	const n = 2000
+	const workers = 3
+	var seen [n]atomic.Int32
+	var wg sync.WaitGroup
+	wg.Add(n)
+
+	pool := make([]ReaderCallback[int], workers)
+	for i := range pool {
+		pool[i] = func(value int) {
+			seen[value].Add(1)
+			wg.Done()
+		}
+	}
+	d, err := Disruptor[int](ctx, 64)
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	d.HandleWithWorkerPool(pool...)
+
+	for i := 0; i < n; i++ {
+		if err := d.MustEnqueue(i); err != nil {
+			t.Fatalf("MustEnqueue failed: %v", err)
+		}
+	}
+	wg.Wait()
+
+	for i := range seen {
+		if c := seen[i].Load(); c != 1 {
+			t.Fatalf("Expected item %d to be processed once, got %d", i, c)
+		}
+	}
+}
+
+func TestHandleWithWorkerPool_Then(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	const n = 500
+	var processed atomic.Int64
+	var violations atomic.Int64
+	var wg sync.WaitGroup
+	wg.Add(n)
+
+	worker := func(value int) { processed.Add(1) }
+	d, err := Disruptor[int](ctx, 32)
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	d.HandleWithWorkerPool(worker, worker).Then(func(value int) {
+		if processed.Load() <= int64(value) {
+			violations.Add(1)
+		}
+		wg.Done()
+	})
+
+	for i := 0; i < n; i++ {
+		if err := d.MustEnqueue(i); err != nil {
+			t.Fatalf("MustEnqueue failed: %v", err)
+		}
+	}
+	wg.Wait()
+
+	if v := violations.Load(); v != 0 {
+		t.Errorf("Expected downstream reader to run after the pool, got %d violations", v)
+	}
+}
+
+func TestDisruptorGroups(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	const n = 1000
+	var billing, shipping atomic.Int64
+	var wg sync.WaitGroup
+	wg.Add(2 * n)
+
+	member := func(counter *atomic.Int64) ReaderCallback[int] {
+		return func(value int) {
+			counter.Add(1)
+			wg.Done()
+		}
+	}
+	d, err := DisruptorGroups[int](ctx, 64, map[string][]ReaderCallback[int]{
+		"billing":  {member(&billing), member(&billing)},
+		"shipping": {member(&shipping), member(&shipping), member(&shipping)},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	for i := 0; i < n; i++ {
+		if err := d.MustEnqueue(i); err != nil {
+			t.Fatalf("MustEnqueue failed: %v", err)
+		}
+	}
+	wg.Wait()
+
+	if b, s := billing.Load(), shipping.Load(); b != n || s != n {
+		t.Errorf("Expected each group to process %d items, got %d and %d", n, b, s)
+	}
+}
+
+func TestHandleWithGroup_Name(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	d, err := Disruptor[int](ctx, 8)
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	g := d.HandleWithGroup("audit", func(value int) {})
+	if g.Name() != "audit" {
+		t.Errorf("Expected group name audit, got %q", g.Name())
+	}
+	if !d.Enqueue(1) {
+		t.Fatal("Failed to enqueue item")
+	}
+	for g.Sequence() != 1 {
+		time.Sleep(time.Millisecond)
+	}
+}
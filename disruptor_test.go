@@ -2,7 +2,9 @@ package ring
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"github.com/dk-open/ring/latency"
 	"github.com/dk-open/ring/pad"
 	"sync"
 	"sync/atomic"
@@ -299,3 +301,186 @@ func BenchmarkDisruptorMultiThreaded(b *testing.B) {
 	}
 	wg.Wait()
 }
+
+func TestDisruptor_CloseDrainsReaders(t *testing.T) {
+	var processed atomic.Int64
+	reader := func(value int) {
+		time.Sleep(time.Microsecond)
+		processed.Add(1)
+	}
+
+	d, err := Disruptor(context.Background(), 64, reader, reader)
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	for i := 0; i < 50; i++ {
+		if err := d.MustEnqueue(i); err != nil {
+			t.Fatalf("MustEnqueue failed: %v", err)
+		}
+	}
+	if err := d.Close(context.Background()); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if p := processed.Load(); p != 100 {
+		t.Errorf("Expected 100 processed events after Close, got %d", p)
+	}
+	if d.Enqueue(1) {
+		t.Error("Expected Enqueue to fail after Close")
+	}
+	if err := d.MustEnqueue(1); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected ErrClosed, got %v", err)
+	}
+}
+
+func TestDisruptor_CloseRacingProducers(t *testing.T) {
+	for round := 0; round < 50; round++ {
+		var processed atomic.Int64
+		d, err := Disruptor(context.Background(), 16, func(int) { processed.Add(1) })
+		if err != nil {
+			t.Fatalf("Failed to create disruptor: %v", err)
+		}
+		var published atomic.Int64
+		var wg sync.WaitGroup
+		for p := 0; p < 4; p++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := 0; ; i++ {
+					if err := d.MustEnqueue(i); err != nil {
+						return
+					}
+					published.Add(1)
+				}
+			}()
+		}
+		time.Sleep(100 * time.Microsecond)
+		if err := d.Close(context.Background()); err != nil {
+			t.Fatalf("Close failed: %v", err)
+		}
+		wg.Wait()
+		// Every event a producer saw published is drained by Close
+		if p, c := published.Load(), processed.Load(); p != c {
+			t.Fatalf("Expected %d published events to be processed, got %d", p, c)
+		}
+	}
+}
+
+func TestDisruptor_CloseDeadline(t *testing.T) {
+	block := make(chan struct{})
+	defer close(block)
+	d, err := Disruptor(context.Background(), 8, func(value int) { <-block })
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	d.Enqueue(1)
+	d.Enqueue(2)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := d.Close(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got %v", err)
+	}
+}
+
+// BenchmarkDisruptorLatency publishes on a fixed schedule and reports
+// end-to-end latency from the intended publish time, free of coordinated omission.
+func BenchmarkDisruptorLatency(b *testing.B) {
+	for _, rate := range []float64{10_000, 100_000} {
+		b.Run(fmt.Sprintf("Rate_%.0f", rate), func(b *testing.B) {
+			ctx, cancel := context.WithCancel(b.Context())
+			defer cancel()
+
+			endToEnd := &latency.Histogram{}
+			d, err := Disruptor[time.Time](ctx, 1024, func(intended time.Time) {
+				endToEnd.Record(time.Since(intended))
+			})
+			if err != nil {
+				b.Fatalf("Failed to create disruptor: %v", err)
+			}
+
+			b.ResetTimer()
+			if _, err := latency.Run(ctx, b.N, rate, d.MustEnqueue); err != nil {
+				b.Fatalf("Run failed: %v", err)
+			}
+			if err := d.Close(ctx); err != nil {
+				b.Fatalf("Close failed: %v", err)
+			}
+			b.ReportMetric(float64(endToEnd.ValueAtQuantile(0.5)), "p50-ns")
+			b.ReportMetric(float64(endToEnd.ValueAtQuantile(0.99)), "p99-ns")
+			b.ReportMetric(float64(endToEnd.ValueAtQuantile(0.999)), "p999-ns")
+		})
+	}
+}
+
+func BenchmarkDisruptorProducerMode(b *testing.B) {
+	modes := []struct {
+		name string
+		opts []Option
+	}{
+		{"MultiProducer", nil},
+		{"SingleProducer", []Option{WithSingleProducer()}},
+	}
+	for _, mode := range modes {
+		b.Run(mode.name, func(b *testing.B) {
+			ctx, cancel := context.WithCancel(b.Context())
+			defer cancel()
+
+			var wg sync.WaitGroup
+			counter := pad.AtomicUint64{}
+			totalNum := uint64(b.N)
+			wg.Add(1)
+			opts := append([]Option{WithCapacity(1024), WithReaders(func(value int) {
+				if counter.Add(1) == totalNum {
+					wg.Done()
+				}
+			})}, mode.opts...)
+			d, err := NewDisruptor[int](ctx, opts...)
+			if err != nil {
+				b.Fatalf("Failed to create disruptor: %v", err)
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_ = d.MustEnqueue(i)
+			}
+			wg.Wait()
+		})
+	}
+}
+
+func BenchmarkDisruptorSlotPadding(b *testing.B) {
+	layouts := []struct {
+		name string
+		opts []Option
+	}{
+		{"Packed", nil},
+		{"Padded", []Option{WithSlotPadding()}},
+	}
+	for _, layout := range layouts {
+		b.Run(layout.name, func(b *testing.B) {
+			ctx, cancel := context.WithCancel(b.Context())
+			defer cancel()
+
+			var sum pad.AtomicUint64
+			opts := append([]Option{WithCapacity(1024), WithReaders(func(value int) {
+				sum.Add(uint64(value))
+			})}, layout.opts...)
+			d, err := NewDisruptor[int](ctx, opts...)
+			if err != nil {
+				b.Fatalf("Failed to create disruptor: %v", err)
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					_ = d.MustEnqueue(1)
+				}
+			})
+			if err := d.Close(ctx); err != nil {
+				b.Fatalf("Close failed: %v", err)
+			}
+		})
+	}
+}
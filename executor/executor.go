@@ -0,0 +1,81 @@
+// Package executor runs submitted work on a fixed set of workers sharing a
+// disruptor, each item is handled by exactly one worker.
+package executor
+
+import (
+	"context"
+	"github.com/dk-open/ring"
+)
+
+// Option configures an executor created by New or Tasks.
+type Option[T any] func(*options[T])
+
+type options[T any] struct {
+	onPanic func(item T, recovered any)
+}
+
+// WithOnPanic receives the item and the recovered value of every panic of the
+// handler. The worker recovers either way and takes the next item.
+func WithOnPanic[T any](f func(item T, recovered any)) Option[T] {
+	return func(o *options[T]) {
+		o.onPanic = f
+	}
+}
+
+// Executor is a running worker pool.
+type Executor[T any] struct {
+	d ring.IDisruptor[T]
+}
+
+// New starts workers goroutines handling the items submitted to the executor.
+// Capacity bounds the items waiting for a worker and must be a power of two,
+// Submit blocks while it is reached.
+func New[T any](capacity uint64, workers int, handler func(item T), opts ...Option[T]) (*Executor[T], error) {
+	var o options[T]
+	for _, opt := range opts {
+		opt(&o)
+	}
+	d, err := ring.NewDisruptor[T](context.Background(), ring.WithCapacity(capacity), ring.WithBlockingWait())
+	if err != nil {
+		return nil, err
+	}
+	f := ring.WithPanicHandler(handler, o.onPanic)
+	pool := make([]ring.ReaderCallback[T], workers)
+	for i := range pool {
+		pool[i] = f
+	}
+	d.HandleWithWorkerPool(pool...)
+	return &Executor[T]{d: d}, nil
+}
+
+// Tasks starts an executor running submitted functions.
+func Tasks(capacity uint64, workers int, opts ...Option[func()]) (*Executor[func()], error) {
+	return New(capacity, workers, func(task func()) { task() }, opts...)
+}
+
+// Submit hands item to the workers, waiting while capacity items are pending.
+// It fails with ring.ErrClosed once the executor is closed.
+func (e *Executor[T]) Submit(item T) error {
+	return e.d.MustEnqueue(item)
+}
+
+// TrySubmit hands item to the workers unless the executor is full or closed.
+func (e *Executor[T]) TrySubmit(item T) bool {
+	return e.d.Enqueue(item)
+}
+
+// Pending returns the number of submitted items not yet completed by a worker.
+func (e *Executor[T]) Pending() uint64 {
+	return e.d.Published() - e.d.Released()
+}
+
+// Stats reports the executor's ring, with a reader per worker.
+func (e *Executor[T]) Stats() ring.Stats {
+	return e.d.Stats()
+}
+
+// Close stops accepting items and waits until the submitted ones are handled.
+// If ctx is done first the pending items are abandoned.
+func (e *Executor[T]) Close(ctx context.Context) error {
+	return e.d.Close(ctx)
+}
@@ -0,0 +1,113 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"github.com/dk-open/ring"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTasks(t *testing.T) {
+	const n = 1000
+	var done, recovered atomic.Int64
+	e, err := Tasks(64, 4, WithOnPanic(func(task func(), v any) { recovered.Add(1) }))
+	if err != nil {
+		t.Fatalf("Failed to create executor: %v", err)
+	}
+	for i := 0; i < n; i++ {
+		task := func() { done.Add(1) }
+		if i%100 == 0 {
+			task = func() { panic("boom") }
+		}
+		if err := e.Submit(task); err != nil {
+			t.Fatalf("Submit failed: %v", err)
+		}
+	}
+	if err := e.Close(context.Background()); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if done.Load() != n-10 || recovered.Load() != 10 {
+		t.Errorf("Expected %d tasks and 10 panics, got %d and %d", n-10, done.Load(), recovered.Load())
+	}
+	if e.Pending() != 0 {
+		t.Errorf("Expected no pending tasks, got %d", e.Pending())
+	}
+	if err := e.Submit(func() {}); !errors.Is(err, ring.ErrClosed) {
+		t.Errorf("Expected ErrClosed, got %v", err)
+	}
+}
+
+func TestNew_Workers(t *testing.T) {
+	release := make(chan struct{})
+	var running atomic.Int64
+	e, err := New(16, 3, func(int) {
+		running.Add(1)
+		<-release
+	})
+	if err != nil {
+		t.Fatalf("Failed to create executor: %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		if !e.TrySubmit(i) {
+			t.Fatalf("TrySubmit %d failed", i)
+		}
+	}
+	deadline := time.Now().Add(time.Second)
+	for running.Load() < 3 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if r := running.Load(); r != 3 {
+		t.Fatalf("Expected 3 busy workers, got %d", r)
+	}
+	if p := e.Pending(); p != 5 {
+		t.Errorf("Expected 5 pending items, got %d", p)
+	}
+	if n := len(e.Stats().Readers); n != 3 {
+		t.Errorf("Expected a reader per worker, got %d", n)
+	}
+	close(release)
+	if err := e.Close(context.Background()); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+}
+
+func BenchmarkExecutor(b *testing.B) {
+	const workers = 4
+	b.Run("Executor", func(b *testing.B) {
+		var done atomic.Int64
+		e, err := New(1024, workers, func(int) { done.Add(1) })
+		if err != nil {
+			b.Fatalf("Failed to create executor: %v", err)
+		}
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			_ = e.Submit(i)
+		}
+		_ = e.Close(context.Background())
+	})
+	b.Run("Channel", func(b *testing.B) {
+		var done atomic.Int64
+		ch := make(chan int, 1024)
+		finished := make(chan struct{})
+		for w := 0; w < workers; w++ {
+			go func() {
+				for range ch {
+					done.Add(1)
+				}
+				finished <- struct{}{}
+			}()
+		}
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			ch <- i
+		}
+		close(ch)
+		for w := 0; w < workers; w++ {
+			<-finished
+		}
+	})
+}
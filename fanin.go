@@ -0,0 +1,81 @@
+package ring
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+)
+
+// Combiner drains several input disruptors into one output disruptor.
+type Combiner[T any] struct {
+	ins      []IDisruptor[T]
+	out      IDisruptor[T]
+	stopping atomic.Bool
+	done     chan struct{}
+}
+
+// FanIn starts a goroutine moving the events of ins to out. It takes one event
+// from every input in turn, so a busy input cannot starve the others, and
+// events of the same input keep their order. Inputs see the combiner as a
+// gating reader, a full out stalls their producers.
+func FanIn[T any](ctx context.Context, out IDisruptor[T], ins ...IDisruptor[T]) *Combiner[T] {
+	c := &Combiner[T]{ins: ins, out: out, done: make(chan struct{})}
+	readers := make([]IDisruptorRing[T], len(ins))
+	for i, in := range ins {
+		readers[i] = in.NewReader()
+	}
+	go c.run(ctx, readers)
+	return c
+}
+
+func (c *Combiner[T]) run(ctx context.Context, readers []IDisruptorRing[T]) {
+	defer close(c.done)
+	defer func() {
+		for _, r := range readers {
+			r.Close()
+		}
+	}()
+	var attempt uint64
+	for ctx.Err() == nil {
+		// Read before the round, a round that moved nothing after Close
+		// started has drained the closed inputs
+		stopping := c.stopping.Load()
+		moved := false
+		for _, r := range readers {
+			if v, ok := r.Dequeue(); ok {
+				forward(ctx, c.out, v)
+				moved = true
+			}
+		}
+		switch {
+		case moved:
+			attempt = 0
+		case stopping:
+			return
+		default:
+			readerYield(attempt)
+			attempt++
+		}
+	}
+}
+
+// Close closes the inputs, waits until the combiner moved their events and
+// closes out. If ctx is done first the remaining events are abandoned.
+func (c *Combiner[T]) Close(ctx context.Context) error {
+	var errs []error
+	for _, in := range c.ins {
+		if err := in.Close(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	c.stopping.Store(true)
+	select {
+	case <-c.done:
+	case <-ctx.Done():
+		return errors.Join(append(errs, ctx.Err())...)
+	}
+	if err := c.out.Close(ctx); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,107 @@
+package ring
+
+import (
+	"context"
+	"sync"
+	"testing"
+)
+
+func TestFanIn(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	const inputs, n = 3, 300
+	last := make([]int, inputs)
+	for i := range last {
+		last[i] = -1
+	}
+	var received int
+	out, err := Disruptor[[2]int](ctx, 16, func(v [2]int) {
+		if v[1] != last[v[0]]+1 {
+			t.Errorf("Input %d: expected %d, got %d", v[0], last[v[0]]+1, v[1])
+		}
+		last[v[0]] = v[1]
+		received++
+	})
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	ins := make([]IDisruptor[[2]int], inputs)
+	for i := range ins {
+		if ins[i], err = Disruptor[[2]int](ctx, 8); err != nil {
+			t.Fatalf("Failed to create disruptor: %v", err)
+		}
+	}
+	c := FanIn(ctx, out, ins...)
+
+	var wg sync.WaitGroup
+	for i, in := range ins {
+		wg.Add(1)
+		go func(i int, in IDisruptor[[2]int]) {
+			defer wg.Done()
+			for j := 0; j < n; j++ {
+				if err := in.MustEnqueue([2]int{i, j}); err != nil {
+					t.Errorf("MustEnqueue failed: %v", err)
+					return
+				}
+			}
+		}(i, in)
+	}
+	wg.Wait()
+	if err := c.Close(context.Background()); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if received != inputs*n {
+		t.Fatalf("Expected %d events, got %d", inputs*n, received)
+	}
+	if out.Enqueue([2]int{}) {
+		t.Error("Expected the output to be closed")
+	}
+}
+
+func TestFanIn_Fair(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	busy, err := Disruptor[int](ctx, 64)
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	quiet, err := Disruptor[int](ctx, 64)
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	release := make(chan struct{})
+	var order []int
+	out, err := Disruptor[int](ctx, 2, func(v int) {
+		<-release
+		order = append(order, v)
+	})
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	// The blocked output holds the combiner back while both inputs fill up
+	c := FanIn(ctx, out, busy, quiet)
+	for i := 0; i < 20; i++ {
+		if err := busy.MustEnqueue(1); err != nil {
+			t.Fatalf("MustEnqueue failed: %v", err)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		if err := quiet.MustEnqueue(2); err != nil {
+			t.Fatalf("MustEnqueue failed: %v", err)
+		}
+	}
+	close(release)
+	if err := c.Close(context.Background()); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if len(order) != 22 {
+		t.Fatalf("Expected 22 events, got %d", len(order))
+	}
+	for i, v := range order {
+		if v == 2 && i > 6 {
+			t.Errorf("Expected the quiet input served early, got %v", order)
+		}
+	}
+}
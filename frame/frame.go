@@ -0,0 +1,87 @@
+package frame
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+)
+
+// HeaderSize is the size of a frame header: payload length and CRC-32C of the
+// payload, both big-endian (network order) uint32.
+const HeaderSize = 8
+
+var (
+	ErrChecksum = errors.New("frame: checksum mismatch")
+	ErrTooLarge = errors.New("frame: frame exceeds maximum size")
+)
+
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// Writer writes length-prefixed, checksummed frames to an underlying stream
+// such as a byte ring. It is not safe for concurrent use.
+type Writer struct {
+	w   io.Writer
+	buf []byte
+}
+
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+// WriteFrame writes p as a single frame. Header and payload are handed to the
+// underlying writer in one Write call.
+func (w *Writer) WriteFrame(p []byte) error {
+	if uint64(len(p)) > 1<<32-1 {
+		return ErrTooLarge
+	}
+	var hdr [HeaderSize]byte
+	w.buf = append(w.buf[:0], hdr[:]...)
+	binary.BigEndian.PutUint32(w.buf[0:], uint32(len(p)))
+	binary.BigEndian.PutUint32(w.buf[4:], crc32.Checksum(p, castagnoli))
+	w.buf = append(w.buf, p...)
+	_, err := w.w.Write(w.buf)
+	return err
+}
+
+// Reader reads frames written by Writer and verifies their checksums.
+// It is not safe for concurrent use.
+type Reader struct {
+	r       io.Reader
+	maxSize int
+	hdr     [HeaderSize]byte
+	buf     []byte
+}
+
+// NewReader returns a reader rejecting frames larger than maxSize bytes,
+// which protects against allocating for a corrupted length.
+func NewReader(r io.Reader, maxSize int) *Reader {
+	return &Reader{r: r, maxSize: maxSize}
+}
+
+// ReadFrame returns the next payload. The returned slice is only valid until
+// the next call. A stream ending between frames returns io.EOF, a stream ending
+// inside a frame returns io.ErrUnexpectedEOF.
+func (r *Reader) ReadFrame() ([]byte, error) {
+	if _, err := io.ReadFull(r.r, r.hdr[:]); err != nil {
+		return nil, err
+	}
+	size := binary.BigEndian.Uint32(r.hdr[0:])
+	if uint64(size) > uint64(r.maxSize) {
+		return nil, ErrTooLarge
+	}
+	if cap(r.buf) < int(size) {
+		r.buf = make([]byte, size)
+	}
+	r.buf = r.buf[:size]
+	if _, err := io.ReadFull(r.r, r.buf); err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	if crc32.Checksum(r.buf, castagnoli) != binary.BigEndian.Uint32(r.hdr[4:]) {
+		return nil, ErrChecksum
+	}
+	return r.buf, nil
+}
@@ -0,0 +1,63 @@
+package frame
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestFrame_RoundTrip(t *testing.T) {
+	var stream bytes.Buffer
+	w := NewWriter(&stream)
+	payloads := [][]byte{[]byte("hello"), {}, bytes.Repeat([]byte{0xAB}, 1000)}
+	for _, p := range payloads {
+		if err := w.WriteFrame(p); err != nil {
+			t.Fatalf("WriteFrame failed: %v", err)
+		}
+	}
+
+	r := NewReader(&stream, 4096)
+	for i, want := range payloads {
+		got, err := r.ReadFrame()
+		if err != nil {
+			t.Fatalf("ReadFrame %d failed: %v", i, err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("Frame %d: expected %d bytes, got %d", i, len(want), len(got))
+		}
+	}
+	if _, err := r.ReadFrame(); err != io.EOF {
+		t.Errorf("Expected io.EOF, got %v", err)
+	}
+}
+
+func TestFrame_Header(t *testing.T) {
+	var stream bytes.Buffer
+	if err := NewWriter(&stream).WriteFrame([]byte{1, 2, 3}); err != nil {
+		t.Fatalf("WriteFrame failed: %v", err)
+	}
+	if got := stream.Bytes()[:4]; !bytes.Equal(got, []byte{0, 0, 0, 3}) {
+		t.Errorf("Expected big-endian length prefix, got %v", got)
+	}
+}
+
+func TestFrame_Corruption(t *testing.T) {
+	var stream bytes.Buffer
+	if err := NewWriter(&stream).WriteFrame([]byte("payload")); err != nil {
+		t.Fatalf("WriteFrame failed: %v", err)
+	}
+	data := stream.Bytes()
+
+	corrupted := bytes.Clone(data)
+	corrupted[HeaderSize] ^= 0xFF
+	if _, err := NewReader(bytes.NewReader(corrupted), 64).ReadFrame(); !errors.Is(err, ErrChecksum) {
+		t.Errorf("Expected ErrChecksum, got %v", err)
+	}
+	if _, err := NewReader(bytes.NewReader(data), 4).ReadFrame(); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("Expected ErrTooLarge, got %v", err)
+	}
+	if _, err := NewReader(bytes.NewReader(data[:len(data)-1]), 64).ReadFrame(); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("Expected io.ErrUnexpectedEOF, got %v", err)
+	}
+}
@@ -0,0 +1,64 @@
+// Package group publishes events to sets of disruptors in one global order,
+// so consumers of different rings can merge their events deterministically.
+package group
+
+import (
+	"github.com/dk-open/ring"
+	"sync"
+)
+
+// Event is an event stamped with its global sequence.
+type Event[T any] struct {
+	Seq   uint64
+	Value T
+}
+
+// Sequencer hands out the global sequence shared by a set of rings. The zero
+// value starts at sequence zero.
+type Sequencer struct {
+	mu   sync.Mutex
+	next uint64
+}
+
+// Publish stamps item with the next global sequence and publishes it to every
+// ring, waiting while a ring is full. Publishes through the same sequencer are
+// serialized, so every ring receives its events in global sequence order.
+//
+// If a ring fails, the rings before it already hold the event and the
+// sequence is consumed. The sequence is returned with the error.
+func Publish[T any](s *Sequencer, rings []ring.IDisruptor[Event[T]], item T) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e := Event[T]{Seq: s.next, Value: item}
+	s.next++
+	for _, r := range rings {
+		if err := r.MustEnqueue(e); err != nil {
+			return e.Seq, err
+		}
+	}
+	return e.Seq, nil
+}
+
+// Merge merges event streams consumed from different rings into global
+// sequence order. Every stream must be in sequence order, an event broadcast
+// to several rings appears once.
+func Merge[T any](streams ...[]Event[T]) []Event[T] {
+	var res []Event[T]
+	pos := make([]int, len(streams))
+	for {
+		next := -1
+		for i, s := range streams {
+			if pos[i] < len(s) && (next < 0 || s[pos[i]].Seq < streams[next][pos[next]].Seq) {
+				next = i
+			}
+		}
+		if next < 0 {
+			return res
+		}
+		e := streams[next][pos[next]]
+		if len(res) == 0 || res[len(res)-1].Seq != e.Seq {
+			res = append(res, e)
+		}
+		pos[next]++
+	}
+}
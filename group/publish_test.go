@@ -0,0 +1,66 @@
+package group
+
+import (
+	"context"
+	"github.com/dk-open/ring"
+	"sync"
+	"testing"
+)
+
+func TestPublish_GlobalOrder(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	const producers, n = 4, 200
+	streams := make([][]Event[int], 3)
+	rings := make([]ring.IDisruptor[Event[int]], 3)
+	for i := range rings {
+		d, err := ring.Disruptor[Event[int]](ctx, 16, func(e Event[int]) {
+			streams[i] = append(streams[i], e)
+		})
+		if err != nil {
+			t.Fatalf("Failed to create disruptor: %v", err)
+		}
+		rings[i] = d
+	}
+
+	var s Sequencer
+	var wg sync.WaitGroup
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			for i := 0; i < n; i++ {
+				// Every event goes to ring 0 and to one of the others
+				targets := []ring.IDisruptor[Event[int]]{rings[0], rings[1+i%2]}
+				if _, err := Publish(&s, targets, p*n+i); err != nil {
+					t.Errorf("Publish failed: %v", err)
+					return
+				}
+			}
+		}(p)
+	}
+	wg.Wait()
+	for _, d := range rings {
+		if err := d.Close(context.Background()); err != nil {
+			t.Fatalf("Close failed: %v", err)
+		}
+	}
+
+	for i, stream := range streams {
+		for j := 1; j < len(stream); j++ {
+			if stream[j].Seq <= stream[j-1].Seq {
+				t.Fatalf("Ring %d: sequence %d after %d", i, stream[j].Seq, stream[j-1].Seq)
+			}
+		}
+	}
+	if len(streams[0]) != producers*n || len(streams[1])+len(streams[2]) != producers*n {
+		t.Fatalf("Unexpected stream sizes %d, %d and %d", len(streams[0]), len(streams[1]), len(streams[2]))
+	}
+	merged := Merge(streams[1], streams[2])
+	for i, e := range merged {
+		if e != streams[0][i] {
+			t.Fatalf("Expected merged stream to match ring 0 at %d, got %+v and %+v", i, e, streams[0][i])
+		}
+	}
+}
@@ -0,0 +1,280 @@
+// Package ipcring is a ring queue in a shared memory segment, so one process
+// can enqueue and another dequeue on the same host without sockets. It uses
+// the protocol of ring.Queue: any number of producers and consumers, across
+// any number of processes, claim the cursors with CAS.
+//
+// # Layout
+//
+// The segment is laid out as follows, numbers in the byte order of the host:
+//
+//	offset  size  field
+//	0       8     magic "ringipc\x00"
+//	8       4     layout version, Version
+//	12      4     slot size in bytes, the size of T rounded up to its alignment
+//	16      8     capacity in slots, a power of two
+//	24      4     ready, set to 1 once the creator initialized the segment
+//	64      8     head cursor, alone on its 64-byte line
+//	128     8     tail cursor, alone on its 64-byte line
+//	192     ...   capacity slots
+//
+// Cursors are doubled sequences as in ring.Queue: an odd cursor is held by a
+// producer or consumer between its claim and its commit. A process that dies
+// in between leaves the ring blocked. The layout does not depend on the pad
+// build tags. A change to it increments Version, and Open rejects segments of
+// another version, slot size or byte order.
+package ipcring
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/dk-open/ring"
+	"reflect"
+	"runtime"
+	"sync/atomic"
+	"time"
+	"unsafe"
+)
+
+// Version is the version of the segment layout.
+const Version = 1
+
+const (
+	headerSize   = 192
+	readyOffset  = 24
+	headOffset   = 64
+	tailOffset   = 128
+	readyTimeout = time.Second
+)
+
+var magic = [8]byte{'r', 'i', 'n', 'g', 'i', 'p', 'c', 0}
+
+// maxAttempts bounds the attempts of MustEnqueue.
+var maxAttempts = 10000
+
+var (
+	ErrLayout   = errors.New("ipcring: segment has a different layout")
+	ErrNotReady = errors.New("ipcring: segment was not initialized")
+	ErrType     = errors.New("ipcring: type holds pointers and cannot be shared between processes")
+
+	errGaveUp     = fmt.Errorf("ipcring: %w", ring.ErrTooManyAttempts)
+	errGaveUpFull = fmt.Errorf("ipcring: %w: %w", ring.ErrTooManyAttempts, ring.ErrFull)
+)
+
+// Ring is a mapping of a shared ring of T. T must not hold pointers, slices,
+// strings, maps, interfaces, channels or functions.
+type Ring[T any] struct {
+	mem      []byte
+	unmap    func() error
+	cap      uint64
+	capMask  uint64
+	capX2    uint64
+	slotSize uint64
+	head     *atomic.Uint64
+	tail     *atomic.Uint64
+}
+
+// Create creates the named segment for capacity values, a power of two, and
+// maps it. It fails with an error matching fs.ErrExist if a segment of that
+// name exists; see Remove.
+func Create[T any](name string, capacity uint64) (*Ring[T], error) {
+	slotSize, err := slotSizeOf[T]()
+	if err != nil {
+		return nil, err
+	}
+	if capacity == 0 || capacity&(capacity-1) != 0 || capacity > ring.MaxCapacity {
+		return nil, ring.ErrCapacity
+	}
+	mem, unmap, err := createSegment(name, int(headerSize+capacity*slotSize))
+	if err != nil {
+		return nil, err
+	}
+	copy(mem, magic[:])
+	binary.NativeEndian.PutUint32(mem[8:], Version)
+	binary.NativeEndian.PutUint32(mem[12:], uint32(slotSize))
+	binary.NativeEndian.PutUint64(mem[16:], capacity)
+	r := newRing[T](mem, unmap, capacity, slotSize)
+	r.word(readyOffset).Store(1)
+	return r, nil
+}
+
+// Open maps the named segment created by Create, in this or another process.
+func Open[T any](name string) (*Ring[T], error) {
+	slotSize, err := slotSizeOf[T]()
+	if err != nil {
+		return nil, err
+	}
+	mem, unmap, err := openSegment(name, headerSize, func(header []byte) int {
+		return int(headerSize + binary.NativeEndian.Uint64(header[16:])*slotSize)
+	})
+	if err != nil {
+		return nil, err
+	}
+	capacity, err := validate(mem, slotSize)
+	if err != nil {
+		_ = unmap()
+		return nil, err
+	}
+	return newRing[T](mem, unmap, capacity, slotSize), nil
+}
+
+// validate checks the header of a mapped segment, waiting briefly for a
+// creator that is still initializing it.
+func validate(mem []byte, slotSize uint64) (uint64, error) {
+	if len(mem) < headerSize {
+		return 0, ErrLayout
+	}
+	ready := (*atomic.Uint32)(unsafe.Pointer(&mem[readyOffset]))
+	for deadline := time.Now().Add(readyTimeout); ready.Load() != 1; time.Sleep(time.Millisecond) {
+		if time.Now().After(deadline) {
+			return 0, ErrNotReady
+		}
+	}
+	if [8]byte(mem[:8]) != magic {
+		return 0, ErrLayout
+	}
+	version, size := binary.NativeEndian.Uint32(mem[8:]), binary.NativeEndian.Uint32(mem[12:])
+	capacity := binary.NativeEndian.Uint64(mem[16:])
+	if version != Version || uint64(size) != slotSize || uint64(len(mem)) < headerSize+capacity*slotSize {
+		return 0, fmt.Errorf("%w: version %d, %d slots of %d bytes", ErrLayout, version, capacity, size)
+	}
+	return capacity, nil
+}
+
+func newRing[T any](mem []byte, unmap func() error, capacity, slotSize uint64) *Ring[T] {
+	r := &Ring[T]{
+		mem:      mem,
+		unmap:    unmap,
+		cap:      capacity,
+		capMask:  capacity - 1,
+		capX2:    capacity*2 - 1,
+		slotSize: slotSize,
+	}
+	r.head = r.word(headOffset)
+	r.tail = r.word(tailOffset)
+	return r
+}
+
+func (r *Ring[T]) word(offset int) *atomic.Uint64 {
+	return (*atomic.Uint64)(unsafe.Pointer(&r.mem[offset]))
+}
+
+func (r *Ring[T]) slot(seq uint64) *T {
+	return (*T)(unsafe.Pointer(&r.mem[headerSize+(seq>>1&r.capMask)*r.slotSize]))
+}
+
+// Enqueue adds v unless the ring is full or another producer holds the head.
+func (r *Ring[T]) Enqueue(v T) bool {
+	ok, _ := r.enqueue(v)
+	return ok
+}
+
+// enqueue makes one attempt to add v and reports whether the ring was full.
+func (r *Ring[T]) enqueue(v T) (ok, full bool) {
+	head := r.head.Load()
+	if head&1 == 1 {
+		return false, false
+	}
+	if head-r.tail.Load() >= r.capX2 {
+		return false, true
+	}
+	if !r.head.CompareAndSwap(head, head+1) {
+		return false, false
+	}
+	*r.slot(head) = v
+	r.head.Store(head + 2)
+	return true, false
+}
+
+// MustEnqueue adds v, waiting while the ring is full. It gives up after
+// maxAttempts attempts with an error matching ring.ErrTooManyAttempts, and
+// ring.ErrFull if the ring was full on the last one. The errors are
+// predeclared as in ring.Queue.
+func (r *Ring[T]) MustEnqueue(v T) error {
+	for attempt := 0; ; attempt++ {
+		ok, full := r.enqueue(v)
+		switch {
+		case ok:
+			return nil
+		case attempt >= maxAttempts:
+			if full {
+				return errGaveUpFull
+			}
+			return errGaveUp
+		case attempt < 20:
+			runtime.Gosched()
+		default:
+			time.Sleep(time.Microsecond << min(attempt-20, 12))
+		}
+	}
+}
+
+// Dequeue removes the oldest value.
+func (r *Ring[T]) Dequeue() (v T, ok bool) {
+	for {
+		tail := r.tail.Load()
+		head := r.head.Load()
+		if tail == head {
+			return v, false
+		}
+		if tail&1 == 1 || head-tail < 2 {
+			runtime.Gosched()
+			continue
+		}
+		if r.tail.CompareAndSwap(tail, tail+1) {
+			v = *r.slot(tail)
+			r.tail.Store(tail + 2)
+			return v, true
+		}
+		runtime.Gosched()
+	}
+}
+
+// Len returns the number of committed values in the ring.
+func (r *Ring[T]) Len() uint64 {
+	tail := r.tail.Load() &^ 1
+	if head := r.head.Load() &^ 1; tail < head {
+		return (head - tail) >> 1
+	}
+	return 0
+}
+
+// Close unmaps the segment. The segment itself stays until Remove.
+func (r *Ring[T]) Close() error {
+	return r.unmap()
+}
+
+// Remove deletes the named segment. Processes that mapped it keep their
+// mapping until they close it.
+func Remove(name string) error {
+	return removeSegment(name)
+}
+
+// slotSizeOf returns the size of T rounded up to its alignment and at least
+// to 8 bytes, after checking that T holds no pointers.
+func slotSizeOf[T any]() (uint64, error) {
+	t := reflect.TypeFor[T]()
+	if hasPointers(t) {
+		return 0, fmt.Errorf("%w: %v", ErrType, t)
+	}
+	align := uint64(max(t.Align(), 8))
+	return max((uint64(t.Size())+align-1)&^(align-1), align), nil
+}
+
+func hasPointers(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Array:
+		return t.Len() > 0 && hasPointers(t.Elem())
+	case reflect.Struct:
+		for i := range t.NumField() {
+			if hasPointers(t.Field(i).Type) {
+				return true
+			}
+		}
+		return false
+	case reflect.Pointer, reflect.UnsafePointer, reflect.Slice, reflect.String, reflect.Map,
+		reflect.Interface, reflect.Chan, reflect.Func:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,153 @@
+package ipcring
+
+import (
+	"errors"
+	"fmt"
+	"github.com/dk-open/ring"
+	"io/fs"
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+)
+
+type tick struct {
+	Seq   uint64
+	Price float64
+	Side  [4]byte
+}
+
+func segmentName(t *testing.T) string {
+	name := fmt.Sprintf("ipcring-test-%d-%s", os.Getpid(), t.Name())
+	t.Cleanup(func() { _ = Remove(name) })
+	return name
+}
+
+func TestRing_CreateOpen(t *testing.T) {
+	name := segmentName(t)
+	producer, err := Create[tick](name, 8)
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	defer producer.Close()
+	consumer, err := Open[tick](name)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer consumer.Close()
+
+	for i := uint64(0); i < 8; i++ {
+		if !producer.Enqueue(tick{Seq: i, Price: float64(i) / 2, Side: [4]byte{'b'}}) {
+			t.Fatalf("Enqueue %d failed", i)
+		}
+	}
+	if producer.Enqueue(tick{}) {
+		t.Fatal("Expected a full ring")
+	}
+	if n := consumer.Len(); n != 8 {
+		t.Fatalf("Expected 8 values through the second mapping, got %d", n)
+	}
+	for i := uint64(0); i < 8; i++ {
+		v, ok := consumer.Dequeue()
+		if !ok || v.Seq != i || v.Price != float64(i)/2 || v.Side[0] != 'b' {
+			t.Fatalf("Expected tick %d, got %+v, %v", i, v, ok)
+		}
+	}
+	if _, ok := consumer.Dequeue(); ok {
+		t.Error("Expected an empty ring")
+	}
+}
+
+func TestRing_Errors(t *testing.T) {
+	name := segmentName(t)
+	r, err := Create[tick](name, 8)
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	defer r.Close()
+	if _, err := Create[tick](name, 8); !errors.Is(err, fs.ErrExist) {
+		t.Errorf("Expected fs.ErrExist for an existing segment, got %v", err)
+	}
+	if _, err := Open[uint64](name); !errors.Is(err, ErrLayout) {
+		t.Errorf("Expected ErrLayout for another type, got %v", err)
+	}
+	if _, err := Create[string](segmentName(t)+"-str", 8); !errors.Is(err, ErrType) {
+		t.Errorf("Expected ErrType, got %v", err)
+	}
+	if _, err := Create[struct{ P *int }](segmentName(t)+"-ptr", 8); !errors.Is(err, ErrType) {
+		t.Errorf("Expected ErrType, got %v", err)
+	}
+}
+
+func TestRing_MustEnqueueGivesUp(t *testing.T) {
+	defer func(n int) { maxAttempts = n }(maxAttempts)
+	maxAttempts = 25
+
+	r, err := Create[tick](segmentName(t), 8)
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	defer r.Close()
+	for i := uint64(0); i < 8; i++ {
+		if err := r.MustEnqueue(tick{Seq: i}); err != nil {
+			t.Fatalf("MustEnqueue %d failed: %v", i, err)
+		}
+	}
+	err = r.MustEnqueue(tick{})
+	if !errors.Is(err, ring.ErrTooManyAttempts) || !errors.Is(err, ring.ErrFull) {
+		t.Errorf("Expected ErrTooManyAttempts and ErrFull, got %v", err)
+	}
+	// The errors are predeclared, giving up does not allocate
+	if allocs := testing.AllocsPerRun(3, func() { _ = r.MustEnqueue(tick{}) }); allocs != 0 {
+		t.Errorf("Expected no allocations, got %v", allocs)
+	}
+}
+
+// TestRing_CrossProcess consumes in this process what a child process, the
+// test binary itself, produces.
+func TestRing_CrossProcess(t *testing.T) {
+	const n = 10000
+	if name := os.Getenv("IPCRING_PRODUCER"); name != "" {
+		r, err := Open[tick](name)
+		if err != nil {
+			t.Fatalf("Open failed: %v", err)
+		}
+		defer r.Close()
+		for i := uint64(0); i < n; i++ {
+			if err := r.MustEnqueue(tick{Seq: i}); err != nil {
+				t.Fatalf("MustEnqueue failed: %v", err)
+			}
+		}
+		return
+	}
+
+	name := segmentName(t)
+	r, err := Create[tick](name, 64)
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	defer r.Close()
+	cmd := exec.Command(os.Args[0], "-test.run=^TestRing_CrossProcess$")
+	cmd.Env = append(os.Environ(), "IPCRING_PRODUCER="+name)
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("Failed to start the producer: %v", err)
+	}
+	deadline := time.Now().Add(10 * time.Second)
+	for want := uint64(0); want < n; {
+		v, ok := r.Dequeue()
+		if !ok {
+			if time.Now().After(deadline) {
+				t.Fatalf("Timed out at %d", want)
+			}
+			time.Sleep(10 * time.Microsecond)
+			continue
+		}
+		if v.Seq != want {
+			t.Fatalf("Expected %d, got %d", want, v.Seq)
+		}
+		want++
+	}
+	if err := cmd.Wait(); err != nil {
+		t.Errorf("Producer failed: %v", err)
+	}
+}
@@ -0,0 +1,17 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || windows)
+
+package ipcring
+
+import "errors"
+
+func createSegment(string, int) ([]byte, func() error, error) {
+	return nil, nil, errors.ErrUnsupported
+}
+
+func openSegment(string, int, func([]byte) int) ([]byte, func() error, error) {
+	return nil, nil, errors.ErrUnsupported
+}
+
+func removeSegment(string) error {
+	return errors.ErrUnsupported
+}
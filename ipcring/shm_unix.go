@@ -0,0 +1,55 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd
+
+package ipcring
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// segmentPath places segments in /dev/shm where it exists, which is what
+// shm_open does on Linux, and in the temporary directory otherwise.
+func segmentPath(name string) string {
+	if info, err := os.Stat("/dev/shm"); err == nil && info.IsDir() {
+		return filepath.Join("/dev/shm", name)
+	}
+	return filepath.Join(os.TempDir(), name)
+}
+
+func createSegment(name string, size int) ([]byte, func() error, error) {
+	f, err := os.OpenFile(segmentPath(name), os.O_RDWR|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+	if err = f.Truncate(int64(size)); err != nil {
+		return nil, nil, err
+	}
+	return mapFile(f, size)
+}
+
+func openSegment(name string, _ int, _ func(header []byte) int) ([]byte, func() error, error) {
+	f, err := os.OpenFile(segmentPath(name), os.O_RDWR, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, nil, err
+	}
+	return mapFile(f, int(info.Size()))
+}
+
+func mapFile(f *os.File, size int) ([]byte, func() error, error) {
+	mem, err := syscall.Mmap(int(f.Fd()), 0, size, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, nil, err
+	}
+	return mem, func() error { return syscall.Munmap(mem) }, nil
+}
+
+func removeSegment(name string) error {
+	return os.Remove(segmentPath(name))
+}
@@ -0,0 +1,65 @@
+package ipcring
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// createFileMapping is called through the DLL rather than through syscall,
+// which does not report ERROR_ALREADY_EXISTS for a mapping it attached to.
+var createFileMapping = syscall.NewLazyDLL("kernel32.dll").NewProc("CreateFileMappingW")
+
+// Segments are named file mappings backed by the paging file. Windows removes
+// a mapping once the last process closed it. Like O_EXCL on other platforms,
+// Create fails with an error matching fs.ErrExist if a mapping of the same
+// name is still open.
+func createSegment(name string, size int) ([]byte, func() error, error) {
+	return mapSegment(name, size, true)
+}
+
+func openSegment(name string, headerSize int, size func(header []byte) int) ([]byte, func() error, error) {
+	header, unmap, err := mapSegment(name, headerSize, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	n := size(header)
+	if err = unmap(); err != nil {
+		return nil, nil, err
+	}
+	return mapSegment(name, n, false)
+}
+
+func mapSegment(name string, size int, exclusive bool) ([]byte, func() error, error) {
+	path, err := syscall.UTF16PtrFromString(`Local\` + name)
+	if err != nil {
+		return nil, nil, err
+	}
+	r, _, errno := createFileMapping.Call(uintptr(syscall.InvalidHandle), 0, syscall.PAGE_READWRITE,
+		uintptr(uint32(uint64(size)>>32)), uintptr(uint32(size)), uintptr(unsafe.Pointer(path)))
+	h := syscall.Handle(r)
+	if h == 0 {
+		return nil, nil, &os.PathError{Op: "create", Path: name, Err: errno}
+	}
+	if exclusive && errno == syscall.ERROR_ALREADY_EXISTS {
+		_ = syscall.CloseHandle(h)
+		return nil, nil, &os.PathError{Op: "create", Path: name, Err: errno}
+	}
+	addr, err := syscall.MapViewOfFile(h, syscall.FILE_MAP_WRITE, 0, 0, uintptr(size))
+	if err != nil {
+		_ = syscall.CloseHandle(h)
+		return nil, nil, err
+	}
+	mem := unsafe.Slice((*byte)(*(*unsafe.Pointer)(unsafe.Pointer(&addr))), size)
+	return mem, func() error {
+		err := syscall.UnmapViewOfFile(addr)
+		if cerr := syscall.CloseHandle(h); err == nil {
+			err = cerr
+		}
+		return err
+	}, nil
+}
+
+func removeSegment(string) error {
+	return nil
+}
@@ -0,0 +1,371 @@
+// Package journal is a write-ahead journal for disruptor events. A journal is
+// a sequenced reader appending every event to segmented append-only files,
+// which Replay reads back to rebuild state, LMAX-style event sourcing.
+package journal
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Every record is framed as payload length uint32, CRC-32 of sequence and
+// payload uint32 and sequence uint64, little-endian, followed by the payload.
+const recordHeaderSize = 16
+
+const segmentExt = ".journal"
+
+// maxRecordSize bounds the payload length read from a record, so a corrupt
+// length does not turn into a huge allocation.
+const maxRecordSize = 1 << 30
+
+// DefaultSegmentSize is the size at which a segment is rotated by default.
+const DefaultSegmentSize = 64 << 20
+
+var ErrCorrupt = errors.New("journal: corrupt record")
+
+// Codec encodes events into journal records. Decode must not retain data.
+type Codec[T any] interface {
+	Append(dst []byte, v T) ([]byte, error)
+	Decode(data []byte) (T, error)
+}
+
+// SyncPolicy decides when appended records are flushed to stable storage.
+type SyncPolicy int
+
+const (
+	// SyncBatch syncs at the end of every batch the reader receives.
+	SyncBatch SyncPolicy = iota
+	// SyncAlways syncs after every record.
+	SyncAlways
+	// SyncNone leaves syncing to the operating system. Records are still
+	// written at the end of every batch and survive a crash of the process.
+	SyncNone
+)
+
+// Option configures a journal opened by Open.
+type Option func(*options)
+
+type options struct {
+	segmentSize  int64
+	sync         SyncPolicy
+	syncInterval time.Duration
+}
+
+// WithSegmentSize rotates to a new segment file once a segment reaches size
+// bytes.
+func WithSegmentSize(size int64) Option {
+	return func(o *options) {
+		o.segmentSize = size
+	}
+}
+
+// WithSync sets the sync policy, SyncBatch by default.
+func WithSync(policy SyncPolicy) Option {
+	return func(o *options) {
+		o.sync = policy
+	}
+}
+
+// WithSyncInterval syncs at the end of a batch only once d passed since the
+// last sync, bounding the records a machine crash can lose to about d.
+func WithSyncInterval(d time.Duration) Option {
+	return func(o *options) {
+		o.sync = SyncBatch
+		o.syncInterval = d
+	}
+}
+
+// Journal appends events to the segment files of a directory.
+type Journal[T any] struct {
+	dir   string
+	codec Codec[T]
+	options
+
+	mu       sync.Mutex
+	segments []uint64 // first sequence of every segment
+	f        *os.File
+	w        *bufio.Writer
+	size     int64
+	next     uint64
+	buf      []byte
+	lastSync time.Time
+	err      error
+}
+
+// Open opens the journal in dir, creating the directory if needed. A record
+// torn by a crash at the end of the last segment is truncated, appending
+// continues after the last complete record.
+func Open[T any](dir string, codec Codec[T], opts ...Option) (*Journal[T], error) {
+	o := options{segmentSize: DefaultSegmentSize}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	segments, err := listSegments(dir)
+	if err != nil {
+		return nil, err
+	}
+	j := &Journal[T]{dir: dir, codec: codec, options: o, segments: segments, lastSync: time.Now()}
+	if len(segments) == 0 {
+		return j, j.rotate()
+	}
+	last := segments[len(segments)-1]
+	f, err := os.OpenFile(j.segmentPath(last), os.O_RDWR, 0)
+	if err != nil {
+		return nil, err
+	}
+	next, size, err := scan(f, last, nil)
+	if err != nil && !errors.Is(err, ErrCorrupt) {
+		_ = f.Close()
+		return nil, err
+	}
+	if err = f.Truncate(size); err == nil {
+		_, err = f.Seek(size, io.SeekStart)
+	}
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	j.f, j.w, j.size, j.next = f, bufio.NewWriter(f), size, next
+	return j, nil
+}
+
+// Handle appends an event, it is a ring.SequencedReaderCallback:
+//
+//	d.HandleWithSequenced(j.Handle)
+//
+// Records are numbered by the journal, contiguously across restarts, rather
+// than by the ring sequence, which starts over with every disruptor. After a
+// failed write the journal stops appending and reports the error from Err.
+func (j *Journal[T]) Handle(_ uint64, v T, endOfBatch bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.err != nil {
+		return
+	}
+	if j.err = j.append(v); j.err == nil && (endOfBatch || j.sync == SyncAlways) {
+		j.err = j.flush(false)
+	}
+}
+
+func (j *Journal[T]) append(v T) error {
+	payload, err := j.codec.Append(j.buf[:0], v)
+	if err != nil {
+		return fmt.Errorf("journal: encode %d: %w", j.next, err)
+	}
+	j.buf = payload[:0]
+	var header [recordHeaderSize]byte
+	binary.LittleEndian.PutUint32(header[0:], uint32(len(payload)))
+	binary.LittleEndian.PutUint64(header[8:], j.next)
+	binary.LittleEndian.PutUint32(header[4:], checksum(header[8:], payload))
+	if _, err = j.w.Write(header[:]); err == nil {
+		_, err = j.w.Write(payload)
+	}
+	if err != nil {
+		return err
+	}
+	j.next++
+	j.size += int64(recordHeaderSize + len(payload))
+	if j.size >= j.segmentSize {
+		return j.rotate()
+	}
+	return nil
+}
+
+// flush writes the buffered records and syncs them as the policy asks, or
+// unconditionally if force is set.
+func (j *Journal[T]) flush(force bool) error {
+	if err := j.w.Flush(); err != nil {
+		return err
+	}
+	if !force && (j.sync == SyncNone || time.Since(j.lastSync) < j.syncInterval) {
+		return nil
+	}
+	j.lastSync = time.Now()
+	return j.f.Sync()
+}
+
+// rotate closes the current segment and starts the next one at the next
+// sequence.
+func (j *Journal[T]) rotate() error {
+	if j.f != nil {
+		if err := j.flush(j.sync != SyncNone); err != nil {
+			return err
+		}
+		if err := j.f.Close(); err != nil {
+			return err
+		}
+	}
+	f, err := os.OpenFile(j.segmentPath(j.next), os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	if len(j.segments) == 0 || j.segments[len(j.segments)-1] != j.next {
+		j.segments = append(j.segments, j.next)
+	}
+	j.f, j.w, j.size = f, bufio.NewWriter(f), 0
+	return nil
+}
+
+// Sequence returns the sequence the next record gets, the number of records
+// journaled so far.
+func (j *Journal[T]) Sequence() uint64 {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.next
+}
+
+// Err returns the error that stopped the journal, if any.
+func (j *Journal[T]) Err() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.err
+}
+
+// Sync writes and syncs the buffered records.
+func (j *Journal[T]) Sync() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.flush(true)
+}
+
+// Close writes and syncs the buffered records and closes the journal. Close
+// the disruptor first, so the reader does not append afterwards.
+func (j *Journal[T]) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	err := j.flush(true)
+	if cerr := j.f.Close(); err == nil {
+		err = cerr
+	}
+	if j.err == nil {
+		j.err = os.ErrClosed
+	}
+	return err
+}
+
+// Replay calls cb for every journaled record from sequence from on, in order,
+// and stops at the first error cb returns. It reads the records written so
+// far and may run while the journal is appended to.
+func (j *Journal[T]) Replay(from uint64, cb func(seq uint64, v T) error) error {
+	j.mu.Lock()
+	if j.w != nil {
+		if err := j.w.Flush(); err != nil {
+			j.mu.Unlock()
+			return err
+		}
+	}
+	segments := slices.Clone(j.segments)
+	j.mu.Unlock()
+
+	start := 0
+	for i, first := range segments {
+		if first <= from {
+			start = i
+		}
+	}
+	for i, first := range segments[start:] {
+		f, err := os.Open(j.segmentPath(first))
+		if err != nil {
+			return err
+		}
+		_, _, err = scan(f, first, func(seq uint64, payload []byte) error {
+			if seq < from {
+				return nil
+			}
+			v, err := j.codec.Decode(payload)
+			if err != nil {
+				return fmt.Errorf("journal: decode %d: %w", seq, err)
+			}
+			return cb(seq, v)
+		})
+		_ = f.Close()
+		// A torn record can only end the segment being written
+		if errors.Is(err, ErrCorrupt) && start+i == len(segments)-1 {
+			err = nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (j *Journal[T]) segmentPath(first uint64) string {
+	return filepath.Join(j.dir, fmt.Sprintf("%020d%s", first, segmentExt))
+}
+
+// scan reads the records of a segment starting at sequence first and passes
+// them to fn. It returns the sequence after the last complete record and the
+// offset it ends at, with ErrCorrupt if a torn or corrupt record follows.
+func scan(r io.Reader, first uint64, fn func(seq uint64, payload []byte) error) (next uint64, size int64, err error) {
+	br := bufio.NewReader(r)
+	next = first
+	var header [recordHeaderSize]byte
+	var payload []byte
+	for {
+		if _, err = io.ReadFull(br, header[:]); err != nil {
+			if errors.Is(err, io.EOF) {
+				return next, size, nil
+			}
+			return next, size, fmt.Errorf("%w: at %d", ErrCorrupt, next)
+		}
+		n := binary.LittleEndian.Uint32(header[0:])
+		if n > maxRecordSize {
+			return next, size, fmt.Errorf("%w: at %d", ErrCorrupt, next)
+		}
+		payload = slices.Grow(payload[:0], int(n))[:n]
+		if _, err = io.ReadFull(br, payload); err != nil {
+			return next, size, fmt.Errorf("%w: at %d", ErrCorrupt, next)
+		}
+		if seq := binary.LittleEndian.Uint64(header[8:]); seq != next || checksum(header[8:], payload) != binary.LittleEndian.Uint32(header[4:]) {
+			return next, size, fmt.Errorf("%w: at %d", ErrCorrupt, next)
+		}
+		if fn != nil {
+			if err = fn(next, payload); err != nil {
+				return next, size, err
+			}
+		}
+		next++
+		size += int64(recordHeaderSize) + int64(n)
+	}
+}
+
+func checksum(seq, payload []byte) uint32 {
+	return crc32.Update(crc32.ChecksumIEEE(seq), crc32.IEEETable, payload)
+}
+
+// listSegments returns the first sequences of the segments in dir, in order.
+func listSegments(dir string) ([]uint64, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var res []uint64
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), segmentExt)
+		if !ok || e.IsDir() {
+			continue
+		}
+		first, err := strconv.ParseUint(name, 10, 64)
+		if err != nil {
+			continue
+		}
+		res = append(res, first)
+	}
+	slices.Sort(res)
+	return res, nil
+}
@@ -0,0 +1,146 @@
+package journal
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"github.com/dk-open/ring"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type uint64Codec struct{}
+
+func (uint64Codec) Append(dst []byte, v uint64) ([]byte, error) {
+	return binary.LittleEndian.AppendUint64(dst, v), nil
+}
+
+func (uint64Codec) Decode(data []byte) (uint64, error) {
+	if len(data) != 8 {
+		return 0, errors.New("short value")
+	}
+	return binary.LittleEndian.Uint64(data), nil
+}
+
+func journalEvents(t *testing.T, j *Journal[uint64], from, to uint64) {
+	t.Helper()
+	d, err := ring.Disruptor[uint64](context.Background(), 64)
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	d.HandleWithSequenced(j.Handle)
+	for v := from; v < to; v++ {
+		if err := d.MustEnqueue(v); err != nil {
+			t.Fatalf("MustEnqueue failed: %v", err)
+		}
+	}
+	if err := d.Close(context.Background()); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if err := j.Err(); err != nil {
+		t.Fatalf("Journal failed: %v", err)
+	}
+}
+
+func replay(t *testing.T, j *Journal[uint64], from uint64) []uint64 {
+	t.Helper()
+	var got []uint64
+	err := j.Replay(from, func(seq uint64, v uint64) error {
+		if seq != v {
+			t.Fatalf("Record %d holds %d", seq, v)
+		}
+		got = append(got, v)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Replay failed: %v", err)
+	}
+	return got
+}
+
+func TestJournal_ReplayAcrossSegments(t *testing.T) {
+	dir := t.TempDir()
+	// 24-byte records, a segment holds 4 of them
+	j, err := Open[uint64](dir, uint64Codec{}, WithSegmentSize(96), WithSync(SyncAlways))
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	journalEvents(t, j, 0, 10)
+	if err := j.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if segments, _ := listSegments(dir); len(segments) != 3 {
+		t.Errorf("Expected 3 segments, got %v", segments)
+	}
+
+	// Sequences continue after a restart
+	j, err = Open[uint64](dir, uint64Codec{}, WithSegmentSize(96))
+	if err != nil {
+		t.Fatalf("Reopen failed: %v", err)
+	}
+	defer j.Close()
+	if seq := j.Sequence(); seq != 10 {
+		t.Fatalf("Expected to continue at 10, got %d", seq)
+	}
+	journalEvents(t, j, 10, 12)
+	if got := replay(t, j, 0); len(got) != 12 {
+		t.Errorf("Expected 12 records, got %v", got)
+	}
+	if got := replay(t, j, 6); len(got) != 6 || got[0] != 6 {
+		t.Errorf("Expected records 6-11, got %v", got)
+	}
+}
+
+func TestJournal_TornRecord(t *testing.T) {
+	dir := t.TempDir()
+	j, err := Open[uint64](dir, uint64Codec{})
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	journalEvents(t, j, 0, 3)
+	if err := j.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	// A crash in the middle of the fourth record
+	path := j.segmentPath(0)
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatalf("Failed to open segment: %v", err)
+	}
+	_, _ = f.Write([]byte{8, 0, 0, 0, 1, 2})
+	_ = f.Close()
+
+	j, err = Open[uint64](dir, uint64Codec{})
+	if err != nil {
+		t.Fatalf("Reopen failed: %v", err)
+	}
+	defer j.Close()
+	if seq := j.Sequence(); seq != 3 {
+		t.Fatalf("Expected the torn record to be dropped, next is %d", seq)
+	}
+	journalEvents(t, j, 3, 5)
+	if got := replay(t, j, 0); len(got) != 5 {
+		t.Errorf("Expected 5 records, got %v", got)
+	}
+}
+
+func TestJournal_ReplayStops(t *testing.T) {
+	j, err := Open[uint64](filepath.Join(t.TempDir(), "j"), uint64Codec{}, WithSync(SyncNone))
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer j.Close()
+	journalEvents(t, j, 0, 5)
+	stop := errors.New("stop")
+	var n int
+	err = j.Replay(0, func(seq uint64, v uint64) error {
+		if n++; n == 2 {
+			return stop
+		}
+		return nil
+	})
+	if !errors.Is(err, stop) || n != 2 {
+		t.Errorf("Expected Replay to stop at the second record, got %v after %d", err, n)
+	}
+}
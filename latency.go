@@ -0,0 +1,59 @@
+package ring
+
+import (
+	"github.com/dk-open/ring/latency"
+	"sync/atomic"
+	"time"
+)
+
+// WithLatencyTracking timestamps every event when it is published and records
+// the time until each reader callback returns into the reader's histogram,
+// reported as ReaderStats.Latency. It costs a clock read per publish and per
+// delivered event, without it the disruptor only checks a nil pointer.
+func WithLatencyTracking() Option {
+	return func(o *options) {
+		o.latency = true
+	}
+}
+
+// WithQueueLatencyTracking records the time from Enqueue to Dequeue of every
+// item, reported as QueueStats.Latency.
+func WithQueueLatencyTracking() QueueOption {
+	return func(o *queueOptions) {
+		o.latency = true
+	}
+}
+
+// stamps holds the publish time of every slot, as nanoseconds since epoch on
+// the monotonic clock.
+type stamps struct {
+	epoch time.Time
+	at    []atomic.Int64
+	mask  uint64
+}
+
+func newStamps(capacity uint64) *stamps {
+	return &stamps{epoch: time.Now(), at: make([]atomic.Int64, capacity), mask: capacity - 1}
+}
+
+func (s *stamps) now() int64 {
+	return int64(time.Since(s.epoch))
+}
+
+// publish stamps the n slots from position pos with a single clock read.
+func (s *stamps) publish(pos uint64, n int) {
+	now := s.now()
+	for i := range uint64(n) {
+		s.at[(pos+i)&s.mask].Store(now)
+	}
+}
+
+// published returns the publish time of the slot at position pos.
+func (s *stamps) published(pos uint64) int64 {
+	return s.at[pos&s.mask].Load()
+}
+
+// record adds the time since published to h.
+func (s *stamps) record(h *latency.Histogram, published int64) {
+	h.RecordValue(uint64(max(s.now()-published, 0)))
+}
@@ -0,0 +1,118 @@
+package latency
+
+import (
+	"math/bits"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	subBits    = 7
+	subBuckets = 1 << subBits
+	halfSub    = subBuckets / 2
+	buckets    = subBuckets + (64-subBits)*halfSub
+)
+
+// Histogram is an HDR-style log-linear histogram of nanosecond values with a
+// relative precision better than 1%. Recording is lock-free, so a histogram
+// can be shared by producers and reader callbacks.
+type Histogram struct {
+	counts [buckets]atomic.Uint64
+	total  atomic.Uint64
+	sum    atomic.Uint64
+	max    atomic.Uint64
+}
+
+func bucketOf(v uint64) int {
+	if v < subBuckets {
+		return int(v)
+	}
+	shift := bits.Len64(v) - subBits
+	return subBuckets + (shift-1)*halfSub + int(v>>uint(shift)) - halfSub
+}
+
+// highestEquivalent returns the largest value that falls into the bucket.
+func highestEquivalent(idx int) uint64 {
+	if idx < subBuckets {
+		return uint64(idx)
+	}
+	shift := uint((idx-subBuckets)/halfSub + 1)
+	m := uint64((idx-subBuckets)%halfSub + halfSub)
+	return (m+1)<<shift - 1
+}
+
+func (h *Histogram) Record(d time.Duration) {
+	if d < 0 {
+		d = 0
+	}
+	h.RecordValue(uint64(d))
+}
+
+func (h *Histogram) RecordValue(v uint64) {
+	h.counts[bucketOf(v)].Add(1)
+	h.total.Add(1)
+	h.sum.Add(v)
+	for m := h.max.Load(); v > m && !h.max.CompareAndSwap(m, v); m = h.max.Load() {
+	}
+}
+
+func (h *Histogram) Count() uint64 {
+	return h.total.Load()
+}
+
+func (h *Histogram) Max() time.Duration {
+	return time.Duration(h.max.Load())
+}
+
+func (h *Histogram) Mean() time.Duration {
+	n := h.total.Load()
+	if n == 0 {
+		return 0
+	}
+	return time.Duration(h.sum.Load() / n)
+}
+
+// ValueAtQuantile returns the value below which the fraction q of the recorded
+// values fall, reported as the highest value of its bucket.
+func (h *Histogram) ValueAtQuantile(q float64) time.Duration {
+	n := h.total.Load()
+	if n == 0 {
+		return 0
+	}
+	q = min(max(q, 0), 1)
+	target := uint64(q*float64(n) + 0.5)
+	if target == 0 {
+		target = 1
+	}
+	var seen uint64
+	for i := range h.counts {
+		if seen += h.counts[i].Load(); seen >= target {
+			return time.Duration(min(highestEquivalent(i), h.max.Load()))
+		}
+	}
+	return h.Max()
+}
+
+// Merge adds the values recorded by other.
+func (h *Histogram) Merge(other *Histogram) {
+	for i := range other.counts {
+		if c := other.counts[i].Load(); c > 0 {
+			h.counts[i].Add(c)
+		}
+	}
+	h.total.Add(other.total.Load())
+	h.sum.Add(other.sum.Load())
+	for v, m := other.max.Load(), h.max.Load(); v > m && !h.max.CompareAndSwap(m, v); m = h.max.Load() {
+	}
+}
+
+// Reset clears the histogram. It is not atomic with respect to concurrent
+// recording.
+func (h *Histogram) Reset() {
+	for i := range h.counts {
+		h.counts[i].Store(0)
+	}
+	h.total.Store(0)
+	h.sum.Store(0)
+	h.max.Store(0)
+}
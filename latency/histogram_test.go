@@ -0,0 +1,89 @@
+package latency
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestHistogram_Quantiles(t *testing.T) {
+	h := &Histogram{}
+	for i := 1; i <= 1000; i++ {
+		h.Record(time.Duration(i) * time.Microsecond)
+	}
+	if h.Count() != 1000 {
+		t.Fatalf("Expected 1000 values, got %d", h.Count())
+	}
+	for _, tc := range []struct {
+		q    float64
+		want time.Duration
+	}{
+		{0.5, 500 * time.Microsecond},
+		{0.99, 990 * time.Microsecond},
+		{1, 1000 * time.Microsecond},
+	} {
+		got := h.ValueAtQuantile(tc.q)
+		if diff := float64(got-tc.want) / float64(tc.want); diff < 0 || diff > 0.01 {
+			t.Errorf("Quantile %v: expected ~%v, got %v", tc.q, tc.want, got)
+		}
+	}
+	if h.Max() != time.Millisecond {
+		t.Errorf("Expected max 1ms, got %v", h.Max())
+	}
+}
+
+func TestHistogram_Buckets(t *testing.T) {
+	for _, v := range []uint64{0, 1, 127, 128, 129, 1000, 1 << 40, 1<<64 - 1} {
+		idx := bucketOf(v)
+		if idx < 0 || idx >= buckets {
+			t.Fatalf("Value %d mapped outside histogram: %d", v, idx)
+		}
+		if hi := highestEquivalent(idx); hi < v {
+			t.Errorf("Value %d above its bucket bound %d", v, hi)
+		}
+	}
+}
+
+func TestHistogram_Merge(t *testing.T) {
+	a, b := &Histogram{}, &Histogram{}
+	a.Record(time.Microsecond)
+	b.Record(time.Millisecond)
+	a.Merge(b)
+	if a.Count() != 2 || a.Max() != time.Millisecond {
+		t.Errorf("Expected 2 values with max 1ms, got %d and %v", a.Count(), a.Max())
+	}
+	a.Reset()
+	if a.Count() != 0 || a.ValueAtQuantile(0.5) != 0 {
+		t.Error("Expected empty histogram after Reset")
+	}
+}
+
+func TestRun_ChargesStalls(t *testing.T) {
+	calls := 0
+	h, err := Run(context.Background(), 20, 1000, func(time.Time) error {
+		calls++
+		if calls == 1 {
+			time.Sleep(10 * time.Millisecond)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if h.Count() != 20 {
+		t.Fatalf("Expected 20 values, got %d", h.Count())
+	}
+	// Operations scheduled during the stall wait for it and must be charged
+	if p := h.ValueAtQuantile(0.75); p < 3*time.Millisecond {
+		t.Errorf("Expected p75 to include the stall, got %v", p)
+	}
+}
+
+func TestRun_Error(t *testing.T) {
+	errFull := errors.New("full")
+	h, err := Run(context.Background(), 10, 1e6, func(time.Time) error { return errFull })
+	if !errors.Is(err, errFull) || h.Count() != 0 {
+		t.Errorf("Expected errFull and no values, got %v and %d", err, h.Count())
+	}
+}
@@ -0,0 +1,46 @@
+package latency
+
+import (
+	"context"
+	"runtime"
+	"time"
+)
+
+// Run invokes op n times on a fixed schedule of rate operations per second and
+// records the latency from each operation's intended start to its completion.
+// Operations that start late because earlier ones stalled are charged for the
+// wait, so the histogram is not skewed by coordinated omission. The intended
+// start is passed to op so it can be carried along for end-to-end latency.
+func Run(ctx context.Context, n int, rate float64, op func(intended time.Time) error) (*Histogram, error) {
+	h := &Histogram{}
+	interval := time.Duration(float64(time.Second) / rate)
+	start := time.Now()
+	for i := 0; i < n; i++ {
+		intended := start.Add(time.Duration(i) * interval)
+		if err := waitUntil(ctx, intended); err != nil {
+			return h, err
+		}
+		if err := op(intended); err != nil {
+			return h, err
+		}
+		h.Record(time.Since(intended))
+	}
+	return h, nil
+}
+
+func waitUntil(ctx context.Context, t time.Time) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		d := time.Until(t)
+		switch {
+		case d <= 0:
+			return nil
+		case d > 100*time.Microsecond:
+			time.Sleep(d - 50*time.Microsecond)
+		default:
+			runtime.Gosched()
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package ring
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestDisruptor_LatencyTracking(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan struct{})
+	count := 0
+	d, err := NewDisruptor[int](ctx, WithCapacity(16), WithLatencyTracking())
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	d.HandleWith(func(int) {
+		time.Sleep(time.Millisecond)
+		if count++; count == 10 {
+			close(done)
+		}
+	})
+	d.EnqueueBatch([]int{0, 1, 2, 3, 4})
+	for i := 5; i < 10; i++ {
+		if err := d.MustEnqueue(i); err != nil {
+			t.Fatalf("Failed to enqueue %d: %v", i, err)
+		}
+	}
+	<-done
+	readers := d.Stats().Readers
+	if len(readers) != 1 || readers[0].Latency == nil {
+		t.Fatalf("Expected one reader with a latency histogram, got %+v", readers)
+	}
+	// The histogram is recorded after the callback returns
+	h := readers[0].Latency
+	for deadline := time.Now().Add(time.Second); h.Count() < 10 && time.Now().Before(deadline); {
+		time.Sleep(time.Millisecond)
+	}
+	if got := h.Count(); got != 10 {
+		t.Fatalf("Expected 10 recorded latencies, got %d", got)
+	}
+	if p50 := h.ValueAtQuantile(0.5); p50 < time.Millisecond {
+		t.Errorf("Expected a median of at least the 1ms handler time, got %v", p50)
+	}
+	if h.Max() < 10*time.Millisecond {
+		t.Errorf("Expected the last event to wait for the 9 before it, max %v", h.Max())
+	}
+}
+
+func TestDisruptor_LatencyTrackingDisabled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	d, err := NewDisruptor[int](ctx, WithReaders(func(int) {}))
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	if readers := d.Stats().Readers; len(readers) != 1 || readers[0].Latency != nil {
+		t.Errorf("Expected no latency histogram without tracking, got %+v", readers)
+	}
+}
+
+func TestQueue_LatencyTracking(t *testing.T) {
+	for _, slots := range []bool{false, true} {
+		opts := []QueueOption{WithQueueLatencyTracking()}
+		if slots {
+			opts = append(opts, WithQueueSlotSequences())
+		}
+		q, err := Queue[int](8, opts...)
+		if err != nil {
+			t.Fatalf("Failed to create queue: %v", err)
+		}
+		for i := 0; i < 4; i++ {
+			q.Enqueue(i)
+		}
+		time.Sleep(5 * time.Millisecond)
+		for i := 0; i < 4; i++ {
+			if _, ok := q.Dequeue(); !ok {
+				t.Fatalf("Expected item %d", i)
+			}
+		}
+		h := q.Stats().Latency
+		if h == nil || h.Count() != 4 {
+			t.Fatalf("Expected 4 recorded latencies, got %v", h)
+		}
+		if p := h.ValueAtQuantile(0); p < 5*time.Millisecond {
+			t.Errorf("Expected every item to wait at least 5ms, got %v", p)
+		}
+	}
+
+	q, err := Queue[int](8)
+	if err != nil {
+		t.Fatalf("Failed to create queue: %v", err)
+	}
+	if h := q.Stats().Latency; h != nil {
+		t.Errorf("Expected no latency histogram without tracking, got %v", h)
+	}
+}
+
+func TestShardedQueue_LatencyTracking(t *testing.T) {
+	q, err := ShardedQueue[int](2, 4, RoundRobin[int](), WithQueueLatencyTracking())
+	if err != nil {
+		t.Fatalf("Failed to create queue: %v", err)
+	}
+	for i := 0; i < 4; i++ {
+		q.Enqueue(i)
+	}
+	for i := 0; i < 4; i++ {
+		q.Dequeue()
+	}
+	if h := q.Stats().Latency; h == nil || h.Count() != 4 {
+		t.Fatalf("Expected the merged histogram to hold 4 latencies, got %v", h)
+	}
+}
+
+// BenchmarkQueue_LatencyTracking measures the cost of timestamping items.
+func BenchmarkQueue_LatencyTracking(b *testing.B) {
+	for _, tracking := range []bool{false, true} {
+		var opts []QueueOption
+		name := "Disabled"
+		if tracking {
+			opts, name = append(opts, WithQueueLatencyTracking()), "Enabled"
+		}
+		b.Run(name, func(b *testing.B) {
+			q, err := Queue[int](1024, opts...)
+			if err != nil {
+				b.Fatalf("Failed to create queue: %v", err)
+			}
+			for i := 0; i < b.N; i++ {
+				q.Enqueue(i)
+				q.Dequeue()
+			}
+		})
+	}
+}
+
+func TestDisruptor_LatencyTrackingWorkers(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	d, err := NewDisruptor[int](ctx, WithLatencyTracking())
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	d.HandleWithWorkerPool(func(int) {}, func(int) {})
+	for i := 0; i < 8; i++ {
+		if err := d.MustEnqueue(i); err != nil {
+			t.Fatalf("Failed to enqueue %d: %v", i, err)
+		}
+	}
+	var total uint64
+	for deadline := time.Now().Add(time.Second); total < 8 && time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+		total = 0
+		for _, r := range d.Stats().Readers {
+			total += r.Latency.Count()
+		}
+	}
+	if total != 8 {
+		t.Errorf("Expected the workers to record 8 latencies, got %d", total)
+	}
+}
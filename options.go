@@ -0,0 +1,406 @@
+package ring
+
+import (
+	"context"
+	"fmt"
+	"github.com/dk-open/ring/pad"
+	"time"
+)
+
+// DefaultCapacity is the disruptor capacity used when WithCapacity is not given.
+const DefaultCapacity = 1024
+
+var (
+	ErrReaderType = fmt.Errorf("reader callback does not match the element type")
+	ErrLayout     = fmt.Errorf("memory layout does not match the build")
+	ErrBackoff    = fmt.Errorf("reader backoff needs a positive maximum sleep")
+)
+
+// Option configures a disruptor created by NewDisruptor.
+type Option func(*options)
+
+type options struct {
+	capacity       uint64
+	name           string
+	singleProducer bool
+	inheritance    bool
+	fair           bool
+	rate           float64
+	burst          int
+	blocking       bool
+	maxSpins       uint64
+	layout         *pad.Layout
+	policy         SlowConsumerPolicy
+	onDrop         any
+	dropHandler    any
+	buffer         any
+	slotPadding    bool
+	watermarks     *watermarkOptions
+	idle           idleBackoff
+	maxBatch       uint64
+	retry          retryPolicy
+	latency        bool
+	reclaim        time.Duration
+	readers        []any
+	sequenced      []any
+}
+
+// WithCapacity sets the number of slots, which must be a power of two.
+func WithCapacity(capacity uint64) Option {
+	return func(o *options) {
+		o.capacity = capacity
+	}
+}
+
+// WithName names the disruptor, the name is reported by Stats.
+func WithName(name string) Option {
+	return func(o *options) {
+		o.name = name
+	}
+}
+
+// WithSingleProducer declares that a single goroutine publishes. The writer
+// cursor is then advanced with a single atomic store instead of a CAS and a
+// second store. Publishing from more than one goroutine is a contract
+// violation that corrupts the ring, builds with the ringdebug tag detect it.
+func WithSingleProducer() Option {
+	return func(o *options) {
+		o.singleProducer = true
+	}
+}
+
+// WithPriorityInheritance lets producers blocked on a full ring wake readers
+// that are sleeping in their backoff, so the ring drains while producers wait
+// instead of both sides sleeping. It costs a timer per reader sleep.
+func WithPriorityInheritance() Option {
+	return func(o *options) {
+		o.inheritance = true
+	}
+}
+
+// WithFairAdmission admits producers that find the ring full in the order
+// they arrived, instead of to whichever CAS lands first once a reader moves
+// on. While producers are queued, Enqueue fails and other producers queue up
+// behind them. It applies to the Block slow-consumer policy.
+func WithFairAdmission() Option {
+	return func(o *options) {
+		o.fair = true
+	}
+}
+
+// WithMaxRate caps the publish rate at rate events per second, allowing
+// bursts of up to burst events. A rate-limited Enqueue fails, MustEnqueue
+// waits for the rate limit under the Block slow-consumer policy and returns
+// ErrRateLimited under the drop policies, which never make producers wait.
+func WithMaxRate(rate float64, burst int) Option {
+	return func(o *options) {
+		o.rate = rate
+		o.burst = burst
+	}
+}
+
+// WithBlockingWait parks idle readers until a producer publishes instead of
+// polling with a sleeping backoff, so an idle disruptor costs no CPU. Readers
+// still yield first as configured by WithReaderBackoff, producers then signal
+// parked readers after every publish, which adds wake-up latency.
+func WithBlockingWait() Option {
+	return func(o *options) {
+		o.blocking = true
+	}
+}
+
+// WithAdaptiveWait makes idle readers spin, then yield, then park as with
+// WithBlockingWait. Every reader tunes its spin budget up to maxSpins: it is
+// doubled when events arrive before the reader parks and halved when it had to
+// park, so bursty readers stay hot while quiet ones stop burning CPU.
+func WithAdaptiveWait(maxSpins int) Option {
+	return func(o *options) {
+		o.blocking = true
+		o.maxSpins = uint64(maxSpins)
+	}
+}
+
+// WithLayout declares the memory layout a deployment was tuned for. Padding is
+// fixed at build time by the target architecture and the pad tags, so NewDisruptor fails
+// with ErrLayout if the binary was built with a different layout.
+func WithLayout(layout pad.Layout) Option {
+	return func(o *options) {
+		o.layout = &layout
+	}
+}
+
+// WithSlowConsumerPolicy selects what producers do when the slowest reader is
+// a full ring behind, Block by default.
+func WithSlowConsumerPolicy(policy SlowConsumerPolicy) Option {
+	return func(o *options) {
+		o.policy = policy
+	}
+}
+
+// WithOnDrop sets the callback receiving events dropped by the DropNewest and
+// DropOldest policies. It runs on the producer's goroutine.
+func WithOnDrop[T any](f func(value T)) Option {
+	return func(o *options) {
+		o.onDrop = f
+	}
+}
+
+// WithDropHandler calls f with every event a non-blocking Enqueue or
+// TryEnqueue rejects because the ring is full, so overload is visible without
+// wrapping every call site. It runs on the producer's goroutine. Rejections by
+// the rate limit and events dropped by a slow-consumer policy, which go to
+// WithOnDrop, are not reported.
+func WithDropHandler[T any](f func(item T)) Option {
+	return func(o *options) {
+		o.dropHandler = f
+	}
+}
+
+// typedHandler asserts a type-erased per-item callback option.
+func typedHandler[T any](f any) (func(item T), error) {
+	if f == nil {
+		return nil, nil
+	}
+	res, ok := f.(func(item T))
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", ErrReaderType, f)
+	}
+	return res, nil
+}
+
+// WithLatencyBias tunes the disruptor for the lowest event latency at the cost
+// of CPU: idle readers wait as with WithAdaptiveWait(1000), spinning while
+// events keep arriving and yielding for long before they park, readers
+// publish their cursor at least every 64 events and producers blocked on a
+// full ring wake them. Options given afterwards override single choices.
+func WithLatencyBias() Option {
+	return func(o *options) {
+		o.blocking = true
+		o.maxSpins = 1000
+		o.idle = idleBackoff{yields: 1000, maxSleep: 50 * time.Microsecond}
+		o.maxBatch = 64
+		o.inheritance = true
+	}
+}
+
+// WithThroughputBias tunes the disruptor for throughput and low CPU use: idle
+// readers wait as with WithBlockingWait and park after a few yields, so
+// events accumulate into large batches consumed without a size limit.
+func WithThroughputBias() Option {
+	return func(o *options) {
+		o.blocking = true
+		o.maxSpins = 0
+		o.idle = idleBackoff{yields: 5, maxSleep: 5 * time.Millisecond}
+		o.maxBatch = 0
+		o.inheritance = false
+	}
+}
+
+// WithReaderBackoff sets how idle readers back off: they yield to the
+// scheduler yields times, then sleep with an interval doubling up to maxSleep.
+// NewDisruptor fails with ErrBackoff unless maxSleep is positive.
+func WithReaderBackoff(yields int, maxSleep time.Duration) Option {
+	return func(o *options) {
+		o.idle = idleBackoff{yields: uint64(max(yields, 0)), maxSleep: maxSleep}
+	}
+}
+
+// WithMaxBatch bounds the events a reader consumes before publishing its
+// cursor, releasing slots to producers sooner. Zero means no bound.
+func WithMaxBatch(n int) Option {
+	return func(o *options) {
+		o.maxBatch = uint64(n)
+	}
+}
+
+// WithReclaimInterval sets how often retainers attached with Retain release
+// the entries of passed events. A non-positive interval keeps the default.
+func WithReclaimInterval(interval time.Duration) Option {
+	return func(o *options) {
+		if interval > 0 {
+			o.reclaim = interval
+		}
+	}
+}
+
+// WithReaders registers readers that are started with the disruptor.
+func WithReaders[T any](readers ...ReaderCallback[T]) Option {
+	return func(o *options) {
+		o.readers = append(o.readers, readers)
+	}
+}
+
+// WithSequencedReaders registers sequenced readers that are started with the
+// disruptor, alongside the readers of WithReaders.
+func WithSequencedReaders[T any](readers ...SequencedReaderCallback[T]) Option {
+	return func(o *options) {
+		o.sequenced = append(o.sequenced, readers)
+	}
+}
+
+// NewDisruptor creates a disruptor configured by options. Without options it
+// has DefaultCapacity slots, accepts multiple producers and has no readers.
+func NewDisruptor[T any](ctx context.Context, opts ...Option) (IDisruptor[T], error) {
+	return newDisruptor[T](ctx, false, opts...)
+}
+
+// newDisruptor creates a disruptor, a deferred one runs no reader until Start.
+func newDisruptor[T any](ctx context.Context, deferred bool, opts ...Option) (*disruptor[T], error) {
+	o := options{capacity: DefaultCapacity, idle: defaultIdle, reclaim: DefaultReclaimInterval}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	capacity := o.capacity
+	if err := validCapacity(capacity); err != nil {
+		return nil, err
+	}
+	if o.idle.maxSleep <= 0 {
+		return nil, fmt.Errorf("%w: %v", ErrBackoff, o.idle.maxSleep)
+	}
+	if o.layout != nil && *o.layout != pad.Current {
+		return nil, fmt.Errorf("%w: want %+v, built with %+v", ErrLayout, *o.layout, pad.Current)
+	}
+	onDrop, err := typedHandler[T](o.onDrop)
+	if err != nil {
+		return nil, err
+	}
+	dropHandler, err := typedHandler[T](o.dropHandler)
+	if err != nil {
+		return nil, err
+	}
+	var readers []ReaderCallback[T]
+	for _, r := range o.readers {
+		typed, ok := r.([]ReaderCallback[T])
+		if !ok {
+			return nil, fmt.Errorf("%w: %T", ErrReaderType, r)
+		}
+		readers = append(readers, typed...)
+	}
+	var sequenced []SequencedReaderCallback[T]
+	for _, r := range o.sequenced {
+		typed, ok := r.([]SequencedReaderCallback[T])
+		if !ok {
+			return nil, fmt.Errorf("%w: %T", ErrReaderType, r)
+		}
+		sequenced = append(sequenced, typed...)
+	}
+
+	shift := slotShift[T](o.slotPadding)
+	buffer, err := newSlots[T](o.buffer, capacity, shift)
+	if err != nil {
+		return nil, err
+	}
+	var marks *watermarks
+	if w := o.watermarks; w != nil {
+		if marks, err = newWatermarks(w.high, w.low, w.fn, capacity); err != nil {
+			return nil, err
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	res := &disruptor[T]{
+		ctx:            ctx,
+		cancel:         cancel,
+		name:           o.name,
+		buffer:         buffer,
+		slotShift:      shift,
+		capMask:        capacity - 1,
+		cap:            capacity,
+		capX2:          capacity*2 - 1,
+		limit:          sequenceLimit(capacity),
+		singleProducer: o.singleProducer,
+		blocking:       o.blocking,
+		maxSpins:       o.maxSpins,
+		policy:         o.policy,
+		onDrop:         onDrop,
+		dropHandler:    dropHandler,
+		watermarks:     marks,
+		idle:           o.idle,
+		maxBatch:       o.maxBatch,
+		retry:          o.retry,
+	}
+	res.lifecycle.started = !deferred
+	res.readerBarrier = pad.NewCompositeBarrier(&res.writerCursor)
+	res.gating = pad.NewCachingMinBarrier(res.readerBarrier)
+	res.retention.interval = o.reclaim
+	if o.latency {
+		res.stamps = newStamps(capacity)
+	}
+	if o.fair {
+		res.admission = &admission{}
+	}
+	if o.rate > 0 {
+		res.limiter = newRateLimiter(o.rate, o.burst)
+	}
+	if o.inheritance || o.blocking {
+		wake := make(chan struct{})
+		res.wake.Store(&wake)
+	}
+	if len(readers) > 0 {
+		res.HandleWith(readers...)
+	}
+	if len(sequenced) > 0 {
+		res.HandleWithSequenced(sequenced...)
+	}
+	return res, nil
+}
+
+// ReaderOption configures a reader attached with AddReader.
+type ReaderOption func(*readerOptions)
+
+type readerOptions struct {
+	inflightLimit int64
+	fromOldest    bool
+	fromSequence  bool
+	sequence      uint64
+	idleAfter     time.Duration
+	onIdle        func(sequence uint64)
+	// coalescing[T], checked against the reader's event type
+	coalescing any
+
+	checkpointEvery uint64
+	checkpointer    Checkpointer
+}
+
+// WithInflightLimit bounds the events a reader has handed to asynchronous
+// workers without acknowledgement. The reader stops advancing once n events
+// are outstanding and resumes as ReaderHandle.Ack is called.
+//
+// It is flow control only and does not protect against loss: the reader's
+// position moves past an event once the callback returns, not once it is
+// acknowledged. Producers may overwrite its slot, and Close, WaitDrained,
+// checkpoints, source commits and downstream stages treat up to n
+// unacknowledged events as done. The callback must copy what the worker
+// needs, and callers that need delivery guarantees must wait for their
+// workers themselves.
+func WithInflightLimit(n int) ReaderOption {
+	return func(o *readerOptions) {
+		o.inflightLimit = int64(n)
+	}
+}
+
+// FromOldest starts the reader at the oldest event still held in the ring
+// instead of the writer cursor, replaying up to capacity-1 recent events.
+func FromOldest() ReaderOption {
+	return func(o *readerOptions) {
+		o.fromOldest = true
+	}
+}
+
+// WithIdleCallback calls fn on the reader goroutine once the reader has found
+// no events for d, and again every d while it stays idle. It lets readers
+// flush partial batches or update heartbeats between events.
+func WithIdleCallback(d time.Duration, fn func()) ReaderOption {
+	return WithIdleTimeout(d, func(uint64) { fn() })
+}
+
+// WithIdleTimeout is WithIdleCallback passing fn the reader's sequence, the
+// number of events it has passed, as ReaderStats.Sequence. Consumers use it
+// to advance watermarks while no data arrives. It is driven by the reader's
+// own backoff, no timer goroutine is involved.
+func WithIdleTimeout(d time.Duration, fn func(sequence uint64)) ReaderOption {
+	return func(o *readerOptions) {
+		o.idleAfter = d
+		o.onIdle = fn
+	}
+}
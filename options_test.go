@@ -0,0 +1,340 @@
+package ring
+
+import (
+	"context"
+	"errors"
+	"github.com/dk-open/ring/pad"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestNewDisruptor_SingleProducer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	const n = 10000
+	var mu sync.Mutex
+	var received []int
+	d, err := NewDisruptor[int](ctx,
+		WithCapacity(64),
+		WithSingleProducer(),
+		WithReaders(func(value int) {
+			mu.Lock()
+			received = append(received, value)
+			mu.Unlock()
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	for i := 0; i < n; i++ {
+		if err := d.MustEnqueue(i); err != nil {
+			t.Fatalf("MustEnqueue failed: %v", err)
+		}
+	}
+	if err := d.Close(context.Background()); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	if len(received) != n {
+		t.Fatalf("Expected %d items, got %d", n, len(received))
+	}
+	for i, v := range received {
+		if v != i {
+			t.Fatalf("Expected %d at position %d, got %d", i, i, v)
+		}
+	}
+}
+
+func TestNewDisruptor_Options(t *testing.T) {
+	ctx := context.Background()
+	d, err := NewDisruptor[int](ctx)
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	if c := d.(*disruptor[int]).cap; c != DefaultCapacity {
+		t.Errorf("Expected default capacity %d, got %d", DefaultCapacity, c)
+	}
+	if _, err := NewDisruptor[int](ctx, WithCapacity(3)); !errors.Is(err, ErrCapacity) {
+		t.Errorf("Expected ErrCapacity, got %v", err)
+	}
+	if _, err := NewDisruptor[int](ctx, WithReaders(func(value string) {})); !errors.Is(err, ErrReaderType) {
+		t.Errorf("Expected ErrReaderType, got %v", err)
+	}
+	if _, err := NewDisruptor[int](ctx, WithLayout(pad.Current)); err != nil {
+		t.Errorf("Expected the build layout to be accepted, got %v", err)
+	}
+	if _, err := NewDisruptor[int](ctx, WithLayout(pad.Layout{CacheLineSize: 2 * pad.CacheLineSize, Padded: true})); !errors.Is(err, ErrLayout) {
+		t.Errorf("Expected ErrLayout, got %v", err)
+	}
+}
+
+func TestNewDisruptor_PriorityInheritance(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	const n = 2000
+	var received atomic.Int64
+	d, err := NewDisruptor[int](ctx,
+		WithCapacity(4),
+		WithPriorityInheritance(),
+		WithReaders(func(value int) {
+			received.Add(1)
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	// Let the reader settle into its sleeping backoff
+	time.Sleep(5 * time.Millisecond)
+	for i := 0; i < n; i++ {
+		if err := d.MustEnqueue(i); err != nil {
+			t.Fatalf("MustEnqueue failed: %v", err)
+		}
+	}
+	if err := d.Close(context.Background()); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if got := received.Load(); got != n {
+		t.Fatalf("Expected %d items, got %d", n, got)
+	}
+}
+
+func TestIdleBackoff_Wake(t *testing.T) {
+	wake := make(chan struct{})
+	close(wake)
+	start := time.Now()
+	for i := 0; i < 100; i++ {
+		defaultIdle.park(1000, wake)
+	}
+	if elapsed := time.Since(start); elapsed >= 100*time.Millisecond {
+		t.Errorf("Expected a closed wake channel to cut sleeps short, took %v", elapsed)
+	}
+}
+
+func TestNewDisruptor_NameAndSequencedReaders(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var plain, sequenced atomic.Int64
+	d, err := NewDisruptor[int](ctx,
+		WithName("orders"),
+		WithCapacity(16),
+		WithReaders(func(int) { plain.Add(1) }),
+		WithSequencedReaders(func(seq uint64, value int, endOfBatch bool) { sequenced.Add(1) }),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	for i := 0; i < 100; i++ {
+		if err := d.MustEnqueue(i); err != nil {
+			t.Fatalf("MustEnqueue failed: %v", err)
+		}
+	}
+	if err := d.Close(context.Background()); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if plain.Load() != 100 || sequenced.Load() != 100 {
+		t.Errorf("Expected 100 events per reader, got %d and %d", plain.Load(), sequenced.Load())
+	}
+	if name := d.Stats().Name; name != "orders" {
+		t.Errorf("Expected name orders, got %q", name)
+	}
+	if _, err := NewDisruptor[int](ctx, WithSequencedReaders(func(uint64, string, bool) {})); !errors.Is(err, ErrReaderType) {
+		t.Errorf("Expected ErrReaderType, got %v", err)
+	}
+}
+
+func TestNewDisruptor_Bias(t *testing.T) {
+	ctx := context.Background()
+	d, err := NewDisruptor[int](ctx, WithLatencyBias(), WithMaxBatch(16))
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	if ld := d.(*disruptor[int]); ld.maxBatch != 16 || ld.wake.Load() == nil || ld.idle.maxSleep != 50*time.Microsecond ||
+		!ld.blocking || ld.maxSpins == 0 {
+		t.Errorf("Expected latency defaults with the batch overridden, got %+v batch %d", ld.idle, ld.maxBatch)
+	}
+	d, err = NewDisruptor[int](ctx, WithThroughputBias())
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	if td := d.(*disruptor[int]); td.maxBatch != 0 || !td.blocking || td.maxSpins != 0 {
+		t.Errorf("Expected unbounded batches with a blocking wait")
+	}
+	if _, err := NewDisruptor[int](ctx, WithReaderBackoff(10, 0)); !errors.Is(err, ErrBackoff) {
+		t.Errorf("Expected ErrBackoff, got %v", err)
+	}
+}
+
+func TestNewDisruptor_MaxBatch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	const n, limit = 1000, 8
+	release := make(chan struct{})
+	var batch, largest int
+	d, err := NewDisruptor[int](ctx,
+		WithCapacity(256),
+		WithMaxBatch(limit),
+		WithSequencedReaders(func(seq uint64, value int, endOfBatch bool) {
+			<-release
+			batch++
+			if endOfBatch {
+				largest = max(largest, batch)
+				batch = 0
+			}
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	close(release)
+	for i := 0; i < n; i++ {
+		if err := d.MustEnqueue(i); err != nil {
+			t.Fatalf("MustEnqueue failed: %v", err)
+		}
+	}
+	if err := d.Close(context.Background()); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if largest == 0 || largest > limit {
+		t.Errorf("Expected batches of at most %d events, got %d", limit, largest)
+	}
+}
+
+func TestIdleBackoff_LongIdleStillSleeps(t *testing.T) {
+	b := idleBackoff{yields: 20, maxSleep: 2 * time.Millisecond}
+	start := time.Now()
+	b.park(1000, nil)
+	if elapsed := time.Since(start); elapsed < time.Millisecond {
+		t.Errorf("Expected a long idle reader to sleep the maximum, slept %v", elapsed)
+	}
+}
+
+func TestNewDisruptor_SequenceExhausted(t *testing.T) {
+	ctx := context.Background()
+	for _, opts := range [][]Option{{WithCapacity(8)}, {WithCapacity(8), WithSingleProducer()}} {
+		d, err := NewDisruptor[int](ctx, opts...)
+		if err != nil {
+			t.Fatalf("Failed to create disruptor: %v", err)
+		}
+		impl := d.(*disruptor[int])
+		impl.writerCursor.Store(impl.limit - 2)
+		if !d.Enqueue(1) {
+			t.Fatalf("Expected the last sequence below the limit to publish")
+		}
+		if d.Enqueue(2) {
+			t.Errorf("Expected Enqueue to fail at the sequence limit")
+		}
+		if err := d.MustEnqueue(3); !errors.Is(err, ErrSequenceExhausted) {
+			t.Errorf("Expected ErrSequenceExhausted, got %v", err)
+		}
+	}
+}
+
+func TestMaxSupportedThroughputDuration(t *testing.T) {
+	// 2^63 events at a billion events per second last for about 292 years
+	if got := MaxSupportedThroughputDuration(1024, 1e9); got < 290*365*24*time.Hour {
+		t.Errorf("Expected centuries of headroom, got %v", got)
+	}
+	if got := MaxSupportedThroughputDuration(1024, 0); got != time.Duration(1<<63-1) {
+		t.Errorf("Expected the largest duration without throughput, got %v", got)
+	}
+	if _, err := NewDisruptor[int](context.Background(), WithCapacity(MaxCapacity<<1)); !errors.Is(err, ErrCapacity) {
+		t.Errorf("Expected ErrCapacity above MaxCapacity, got %v", err)
+	}
+}
+
+func TestNewDisruptor_BlockingWait(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	const n = 2000
+	var first, second, pooled atomic.Int64
+	d, err := NewDisruptor[int](ctx, WithCapacity(16), WithBlockingWait())
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	d.HandleWith(func(int) { first.Add(1) }).Then(func(int) { second.Add(1) })
+	d.HandleWithWorkerPool(func(int) { pooled.Add(1) }, func(int) { pooled.Add(1) })
+
+	// Idle readers park instead of polling
+	bd := d.(*disruptor[int])
+	deadline := time.Now().Add(time.Second)
+	for bd.waiters.Load() != 4 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if w := bd.waiters.Load(); w != 4 {
+		t.Fatalf("Expected 4 parked readers, got %d", w)
+	}
+
+	for i := 0; i < n; i++ {
+		if err := d.MustEnqueue(i); err != nil {
+			t.Fatalf("MustEnqueue failed: %v", err)
+		}
+		if i%100 == 0 {
+			time.Sleep(time.Millisecond)
+		}
+	}
+	if err := d.Close(context.Background()); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if first.Load() != n || second.Load() != n || pooled.Load() != n {
+		t.Fatalf("Expected %d events per stage, got %d, %d and %d", n, first.Load(), second.Load(), pooled.Load())
+	}
+}
+
+func TestNewDisruptor_AdaptiveWait(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	const n = 2000
+	var received atomic.Int64
+	d, err := NewDisruptor[int](ctx, WithCapacity(16), WithAdaptiveWait(1000), WithReaders(func(int) {
+		received.Add(1)
+	}))
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	for i := 0; i < n; i++ {
+		if err := d.MustEnqueue(i); err != nil {
+			t.Fatalf("MustEnqueue failed: %v", err)
+		}
+		if i%500 == 0 {
+			time.Sleep(2 * time.Millisecond)
+		}
+	}
+	if err := d.Close(context.Background()); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if got := received.Load(); got != n {
+		t.Fatalf("Expected %d items, got %d", n, got)
+	}
+}
+
+func TestDisruptorReader_Adapt(t *testing.T) {
+	d := &disruptor[int]{maxSpins: 64, idle: idleBackoff{yields: 10}}
+	r := &disruptorReader[int]{d: d, spins: 8}
+	r.adapt(5) // caught while spinning
+	if r.spins != 16 {
+		t.Errorf("Expected the budget to double, got %d", r.spins)
+	}
+	r.adapt(100) // parked
+	if r.spins != 8 {
+		t.Errorf("Expected the budget to halve, got %d", r.spins)
+	}
+	for i := 0; i < 10; i++ {
+		r.adapt(1)
+	}
+	if r.spins != 64 {
+		t.Errorf("Expected the budget capped at 64, got %d", r.spins)
+	}
+	r.spins = 0
+	r.adapt(3) // caught while yielding
+	if r.spins != 1 {
+		t.Errorf("Expected an exhausted budget to recover, got %d", r.spins)
+	}
+}
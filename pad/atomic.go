@@ -2,28 +2,29 @@ package pad
 
 import "sync/atomic"
 
-// AtomicBool is an atomic boolean that is padded
+// AtomicBool is an atomic boolean that is padded. The padding leads the
+// value, a trailing zero-size field would still grow the padnone layout.
 type AtomicBool struct {
+	_ [pad4]byte
 	atomic.Bool
-	_ [60]byte
 }
 
 type AtomicInt32 struct {
+	_ [pad4]byte
 	atomic.Int32
-	_ [60]byte
 }
 
 type AtomicInt64 struct {
+	_ [pad8]byte
 	atomic.Int64
-	_ [56]byte
 }
 
 type AtomicUint32 struct {
+	_ [pad4]byte
 	atomic.Uint32
-	_ [60]byte
 }
 
 type AtomicUint64 struct {
+	_ [pad8]byte
 	atomic.Uint64
-	_ [56]byte
 }
@@ -6,6 +6,9 @@ type Barrier interface {
 
 type MinBarrier []Barrier
 
+// Load compiles to a conditional move per sequence on amd64 and arm64, so it
+// does not suffer from readers overtaking each other. BenchmarkMinVariants
+// compares it with an explicitly branchless fold across sizes.
 func (m MinBarrier) Load() uint64 {
 	minimum := m[0].Load()
 	for i := 1; i < len(m); i++ {
@@ -15,3 +18,25 @@ func (m MinBarrier) Load() uint64 {
 	}
 	return minimum
 }
+
+// MaxBarrier is the most advanced of its barriers, e.g. the leading reader
+// that bounds a replay or retention window.
+type MaxBarrier []Barrier
+
+func (m MaxBarrier) Load() uint64 {
+	maximum := m[0].Load()
+	for i := 1; i < len(m); i++ {
+		if seq := m[i].Load(); seq > maximum {
+			maximum = seq
+		}
+	}
+	return maximum
+}
+
+// ConstBarrier is a barrier that never moves, for tests or to hold a stage at
+// a fixed sequence.
+type ConstBarrier uint64
+
+func (c ConstBarrier) Load() uint64 {
+	return uint64(c)
+}
@@ -2,6 +2,7 @@ package pad
 
 import (
 	"fmt"
+	"math/bits"
 	"math/rand"
 	"testing"
 	"time"
@@ -39,14 +40,63 @@ func TestMinBarrier_EmptyPanic(t *testing.T) {
 	_ = barriers.Load()
 }
 
-// Branchless
+func TestMaxBarrier_MultipleBarriers(t *testing.T) {
+	var a1, a2 AtomicUint64
+	a1.Store(42)
+	a2.Store(17)
+	barriers := MaxBarrier{&a1, &a2, ConstBarrier(19)}
+	if got := barriers.Load(); got != 42 {
+		t.Fatalf("expected 42, got %d", got)
+	}
+	a2.Store(50)
+	if got := barriers.Load(); got != 50 {
+		t.Fatalf("expected 50, got %d", got)
+	}
+}
+
+func TestMaxBarrier_EmptyPanic(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatal("expected panic for empty MaxBarrier, but got none")
+		}
+	}()
+	var barriers MaxBarrier
+	_ = barriers.Load()
+}
+
+func TestConstBarrier(t *testing.T) {
+	var a AtomicUint64
+	a.Store(30)
+	if got := (MinBarrier{&a, ConstBarrier(12)}).Load(); got != 12 {
+		t.Fatalf("expected the constant 12 to hold the barrier, got %d", got)
+	}
+}
+
+func TestMinBarrier_Branchless(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for n := 1; n <= 32; n++ {
+		vals := make([]uint64, n)
+		for i := range vals {
+			// Spans the whole range, including values more than 1<<63 apart
+			vals[i] = rnd.Uint64()
+		}
+		barriers := genAtomicUInt64s(vals)
+		if got, want := branchlessMin(barriers), barriers.Load(); got != want {
+			t.Fatalf("n=%d: expected %d, got %d", n, want, got)
+		}
+	}
+}
+
+// branchlessMin folds the minimum with the borrow of a subtraction. It is the
+// baseline MinBarrier is measured against: the compiler already turns the
+// comparison in MinBarrier.Load into a conditional move, so it is no faster.
 func branchlessMin(m MinBarrier) uint64 {
 	minimum := m[0].Load()
 	for i := 1; i < len(m); i++ {
 		seq := m[i].Load()
-		diff := minimum - seq
-		mask := diff >> 63
-		minimum = seq + (diff & mask)
+		diff, borrow := bits.Sub64(minimum, seq, 0)
+		// All ones if minimum < seq: keeps seq+diff == minimum
+		minimum = seq + diff&-borrow
 	}
 	return minimum
 }
@@ -64,7 +114,7 @@ func genAtomicUInt64s(vals []uint64) MinBarrier {
 func BenchmarkMinVariants(b *testing.B) {
 	rnd := rand.New(rand.NewSource(time.Now().UnixNano()))
 
-	for n := 1; n <= 4; n++ {
+	for _, n := range []int{1, 2, 4, 8, 16, 32} {
 		// Prepare data for this size
 		vals := make([]uint64, n)
 		for i := range vals {
@@ -93,3 +143,34 @@ func BenchmarkMinVariants(b *testing.B) {
 			})
 	}
 }
+
+// BenchmarkMinVariantsMoving lets readers overtake each other between loads,
+// as they do in a running disruptor, so the position of the minimum changes.
+func BenchmarkMinVariantsMoving(b *testing.B) {
+	variants := []struct {
+		name string
+		load func(MinBarrier) uint64
+	}{
+		{"Branch-if", MinBarrier.Load},
+		{"Branch-less", branchlessMin},
+	}
+	for _, n := range []int{2, 4, 8, 16, 32} {
+		for _, v := range variants {
+			b.Run(fmt.Sprintf("%s-%d", v.name, n), func(b *testing.B) {
+				barriers := genAtomicUInt64s(make([]uint64, n))
+				steps := make([]uint64, 1024)
+				rnd := rand.New(rand.NewSource(1))
+				for i := range steps {
+					steps[i] = uint64(rnd.Intn(n))<<8 | uint64(rnd.Intn(64))
+				}
+				var r uint64
+				for i := 0; i < b.N; i++ {
+					step := steps[i&1023]
+					barriers[step>>8].(*AtomicUint64).Add(step & 0xff)
+					r = v.load(barriers)
+				}
+				_ = r
+			})
+		}
+	}
+}
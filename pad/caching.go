@@ -0,0 +1,47 @@
+package pad
+
+import "sync/atomic"
+
+// CachingMinBarrier remembers the last value of a minimum over many sequences,
+// e.g. a CompositeBarrier of readers, and re-scans them only when a caller
+// needs more than the cached value shows. A producer far from wrapping the
+// ring then checks one word instead of every reader. Readers only move
+// forward, so the cached value stays a safe lower bound until a sequence
+// behind it joins; Reset must follow such a join.
+type CachingMinBarrier struct {
+	inner Barrier
+	// cell holds the cached value. Reset installs a new cell, a refresh that
+	// loaded the old one before the join writes its stale value there unseen.
+	cell atomic.Pointer[AtomicUint64]
+}
+
+func NewCachingMinBarrier(inner Barrier) *CachingMinBarrier {
+	c := &CachingMinBarrier{inner: inner}
+	c.cell.Store(&AtomicUint64{})
+	return c
+}
+
+// Load re-scans the underlying sequences and caches the result.
+func (c *CachingMinBarrier) Load() uint64 {
+	cell := c.cell.Load()
+	seq := c.inner.Load()
+	cell.Store(seq)
+	return seq
+}
+
+// Cached returns the value of the last Load, which may lag behind.
+func (c *CachingMinBarrier) Cached() uint64 {
+	return c.cell.Load().Load()
+}
+
+// Reaches reports whether the barrier has reached seq, re-scanning only if the
+// cached value is below it.
+func (c *CachingMinBarrier) Reaches(seq uint64) bool {
+	return c.Cached() >= seq || c.Load() >= seq
+}
+
+// Reset drops the cached value after a sequence joined the underlying barrier
+// behind it.
+func (c *CachingMinBarrier) Reset() {
+	c.cell.Store(&AtomicUint64{})
+}
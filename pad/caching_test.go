@@ -0,0 +1,56 @@
+package pad
+
+import "testing"
+
+type countingBarrier struct {
+	seq   AtomicUint64
+	loads int
+}
+
+func (b *countingBarrier) Load() uint64 {
+	b.loads++
+	return b.seq.Load()
+}
+
+func TestCachingMinBarrier_RescansOnlyWhenBehind(t *testing.T) {
+	var inner countingBarrier
+	inner.seq.Store(100)
+	c := NewCachingMinBarrier(&inner)
+	if !c.Reaches(50) || inner.loads != 1 {
+		t.Fatalf("expected one scan to fill the cache, got %d", inner.loads)
+	}
+	for seq := uint64(0); seq <= 100; seq++ {
+		if !c.Reaches(seq) {
+			t.Fatalf("expected the barrier to reach %d", seq)
+		}
+	}
+	if inner.loads != 1 {
+		t.Fatalf("expected cached checks not to scan, got %d scans", inner.loads)
+	}
+	if c.Reaches(120) || inner.loads != 2 {
+		t.Fatalf("expected a scan past the cached value, got %d scans", inner.loads)
+	}
+	inner.seq.Store(130)
+	if !c.Reaches(120) || c.Cached() != 130 {
+		t.Fatalf("expected a rescan to see 130, cached %d", c.Cached())
+	}
+}
+
+func TestCachingMinBarrier_Reset(t *testing.T) {
+	var a, b AtomicUint64
+	a.Store(200)
+	b.Store(40)
+	composite := NewCompositeBarrier(ConstBarrier(0), &a)
+	c := NewCachingMinBarrier(composite)
+	if got := c.Load(); got != 200 {
+		t.Fatalf("expected 200, got %d", got)
+	}
+	composite.Add(&b)
+	c.Reset()
+	if c.Reaches(100) {
+		t.Fatal("expected the joined sequence at 40 to hold the barrier")
+	}
+	if got := c.Cached(); got != 40 {
+		t.Fatalf("expected the rescan to cache 40, got %d", got)
+	}
+}
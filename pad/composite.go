@@ -0,0 +1,70 @@
+package pad
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// CompositeBarrier is the minimum over a set of barriers that may change while
+// it is loaded, e.g. readers attached and detached at runtime. The members are
+// an immutable slice replaced on every change, so Load takes no lock; Add and
+// Remove serialize on a mutex.
+type CompositeBarrier struct {
+	mu      sync.Mutex
+	members atomic.Pointer[MinBarrier]
+	empty   Barrier
+}
+
+// NewCompositeBarrier creates a composite of members that loads as empty
+// while it has none.
+func NewCompositeBarrier(empty Barrier, members ...Barrier) *CompositeBarrier {
+	c := &CompositeBarrier{empty: empty}
+	if len(members) > 0 {
+		c.Add(members...)
+	}
+	return c
+}
+
+func (c *CompositeBarrier) Load() uint64 {
+	if m := c.members.Load(); m != nil && len(*m) > 0 {
+		return m.Load()
+	}
+	return c.empty.Load()
+}
+
+// Add attaches barriers. A producer that loaded the composite just before may
+// still pass the new members' sequences once.
+func (c *CompositeBarrier) Add(barriers ...Barrier) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	next := append(append(MinBarrier(nil), c.Members()...), barriers...)
+	c.members.Store(&next)
+}
+
+// Remove detaches every occurrence of barrier and reports whether it was a
+// member.
+func (c *CompositeBarrier) Remove(barrier Barrier) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cur := c.Members()
+	next := make(MinBarrier, 0, len(cur))
+	for _, b := range cur {
+		if b != barrier {
+			next = append(next, b)
+		}
+	}
+	if len(next) == len(cur) {
+		return false
+	}
+	c.members.Store(&next)
+	return true
+}
+
+// Members returns a snapshot of the current members. The snapshot must not be
+// modified.
+func (c *CompositeBarrier) Members() MinBarrier {
+	if m := c.members.Load(); m != nil {
+		return *m
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package pad
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestCompositeBarrier_AddRemove(t *testing.T) {
+	var cursor, a, b AtomicUint64
+	cursor.Store(100)
+	a.Store(40)
+	b.Store(60)
+	c := NewCompositeBarrier(&cursor)
+	if got := c.Load(); got != 100 {
+		t.Fatalf("expected the empty barrier 100, got %d", got)
+	}
+	c.Add(&a, &b)
+	if got := c.Load(); got != 40 {
+		t.Fatalf("expected 40, got %d", got)
+	}
+	if !c.Remove(&a) {
+		t.Fatal("expected a to be a member")
+	}
+	if c.Remove(&a) {
+		t.Fatal("expected a to be removed already")
+	}
+	if got := c.Load(); got != 60 {
+		t.Fatalf("expected 60, got %d", got)
+	}
+	c.Remove(&b)
+	if got := c.Load(); got != 100 {
+		t.Fatalf("expected the empty barrier again, got %d", got)
+	}
+}
+
+func TestCompositeBarrier_ConcurrentMembership(t *testing.T) {
+	var cursor AtomicUint64
+	cursor.Store(1000)
+	floor := ConstBarrier(10)
+	c := NewCompositeBarrier(&cursor, floor)
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				var seq AtomicUint64
+				seq.Store(uint64(500 + j))
+				c.Add(&seq)
+				c.Remove(&seq)
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	for {
+		if got := c.Load(); got != 10 {
+			t.Fatalf("expected the floor member to hold the barrier at 10, got %d", got)
+		}
+		select {
+		case <-done:
+			if n := len(c.Members()); n != 1 {
+				t.Fatalf("expected only the floor member left, got %d", n)
+			}
+			return
+		default:
+		}
+	}
+}
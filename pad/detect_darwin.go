@@ -0,0 +1,16 @@
+package pad
+
+import "syscall"
+
+func detectLineSize() int {
+	// The value is a little-endian integer, Sysctl trims a trailing zero byte
+	s, err := syscall.Sysctl("hw.cachelinesize")
+	if err != nil {
+		return 0
+	}
+	n := 0
+	for i := len(s) - 1; i >= 0; i-- {
+		n = n<<8 | int(s[i])
+	}
+	return n
+}
@@ -0,0 +1,19 @@
+package pad
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+func detectLineSize() int {
+	b, err := os.ReadFile("/sys/devices/system/cpu/cpu0/cache/index0/coherency_line_size")
+	if err != nil {
+		return 0
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(string(b)))
+	if err != nil || n <= 0 {
+		return 0
+	}
+	return n
+}
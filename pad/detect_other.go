@@ -0,0 +1,7 @@
+//go:build !linux && !darwin
+
+package pad
+
+func detectLineSize() int {
+	return 0
+}
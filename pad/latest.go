@@ -0,0 +1,75 @@
+package pad
+
+import "runtime"
+
+// LatestValue broadcasts the most recent value to any number of readers,
+// "latest state wins" instead of a queue. It is a single slot under a
+// seqlock: the sequence is odd while a store writes the value in place and
+// its half is the version of the value. Stores do not allocate. A reader
+// copies the value between two loads of the sequence and retries if a store
+// ran in between, so it never returns a partially written value. The zero
+// value is ready to use and holds the zero value of T at version 0.
+//
+// The optimistic copy of T races with a store by design. Builds with the race
+// detector have readers take the slot like a store instead, so the detector
+// only reports real misuse.
+type LatestValue[T any] struct {
+	seq   AtomicUint64
+	value T
+}
+
+// Store publishes v and returns its version. Concurrent stores are ordered by
+// the slot, a store only waits for one in progress to finish its copy.
+func (l *LatestValue[T]) Store(v T) uint64 {
+	seq := l.lock()
+	l.value = v
+	l.seq.Store(seq + 2)
+	return (seq + 2) >> 1
+}
+
+// lock marks the slot odd and returns the even sequence it held.
+func (l *LatestValue[T]) lock() uint64 {
+	for attempt := 0; ; attempt++ {
+		if seq := l.seq.Load(); seq&1 == 0 && l.seq.CompareAndSwap(seq, seq+1) {
+			return seq
+		}
+		latestBackoff(attempt)
+	}
+}
+
+// Load returns the latest value and its version.
+func (l *LatestValue[T]) Load() (v T, version uint64) {
+	if raceEnabled {
+		seq := l.lock()
+		v = l.value
+		l.seq.Store(seq)
+		return v, seq >> 1
+	}
+	for attempt := 0; ; attempt++ {
+		if seq := l.seq.Load(); seq&1 == 0 {
+			v = l.value
+			if l.seq.Load() == seq {
+				return v, seq >> 1
+			}
+		}
+		latestBackoff(attempt)
+	}
+}
+
+// LoadIfNewer returns the latest value if its version is newer than seen.
+// It does not copy the value if nothing changed.
+func (l *LatestValue[T]) LoadIfNewer(seen uint64) (v T, version uint64, ok bool) {
+	if l.seq.Load()>>1 <= seen {
+		return v, seen, false
+	}
+	v, version = l.Load()
+	return v, version, version > seen
+}
+
+func latestBackoff(attempt int) {
+	if attempt < 16 {
+		SpinHint()
+		return
+	}
+	runtime.Gosched()
+}
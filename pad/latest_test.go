@@ -0,0 +1,75 @@
+package pad
+
+import (
+	"sync"
+	"testing"
+)
+
+type quote struct {
+	bid, ask uint64
+}
+
+func TestLatestValue_StoreLoad(t *testing.T) {
+	var l LatestValue[quote]
+	if v, version := l.Load(); version != 0 || v != (quote{}) {
+		t.Fatalf("Expected the zero value at version 0, got %+v at %d", v, version)
+	}
+	v1 := l.Store(quote{1, 2})
+	if _, _, ok := l.LoadIfNewer(v1); ok {
+		t.Error("Expected no newer value than the last store")
+	}
+	l.Store(quote{3, 4})
+	v, version, ok := l.LoadIfNewer(v1)
+	if !ok || v != (quote{3, 4}) || version <= v1 {
+		t.Errorf("Expected the second store, got %+v at %d", v, version)
+	}
+}
+
+func TestLatestValue_Concurrent(t *testing.T) {
+	var l LatestValue[quote]
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := uint64(1); i <= 10000; i++ {
+				l.Store(quote{i, i + 1})
+			}
+		}()
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		var seen uint64
+		for i := 0; i < 10000; i++ {
+			v, version := l.Load()
+			if v.ask != v.bid+1 && version != 0 {
+				t.Errorf("Torn read %+v", v)
+				return
+			}
+			if version < seen {
+				t.Errorf("Version went back from %d to %d", seen, version)
+				return
+			}
+			seen = version
+		}
+	}()
+	wg.Wait()
+	<-done
+	if _, version := l.Load(); version != 40000 {
+		t.Errorf("Expected the last version 40000 to win, got %d", version)
+	}
+}
+
+func TestLatestValue_NoAlloc(t *testing.T) {
+	var l LatestValue[quote]
+	allocs := testing.AllocsPerRun(100, func() {
+		version := l.Store(quote{1, 2})
+		if _, seen := l.Load(); seen != version {
+			t.Fatalf("Expected version %d, got %d", version, seen)
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("Expected no allocations per store, got %v", allocs)
+	}
+}
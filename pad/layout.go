@@ -0,0 +1,21 @@
+package pad
+
+// Layout describes how the padded types are laid out in memory. It is chosen
+// at build time: by default every value is padded to the cache line of the
+// target architecture, 128 bytes on Apple M-series and POWER, 256 on s390x
+// and 64 elsewhere. The pad64 and pad32 tags force 64- or 32-byte lines and
+// the padnone tag drops padding to save memory at the cost of false sharing.
+type Layout struct {
+	CacheLineSize int
+	Padded        bool
+}
+
+// Current is the layout this binary was built with.
+var Current = Layout{CacheLineSize: CacheLineSize, Padded: padded}
+
+// Detected reports the cache line size of the machine the binary runs on, or
+// zero where it cannot be read. A binary whose CacheLineSize is smaller than
+// Detected shares lines between padded values.
+func Detected() int {
+	return detectLineSize()
+}
@@ -0,0 +1,13 @@
+//go:build pad32 && !padnone
+
+package pad
+
+const CacheLineSize = 32
+
+const (
+	padded  = true
+	pad4    = CacheLineSize - 4
+	pad8    = CacheLineSize - 8
+	padPtr  = CacheLineSize - ptrSize
+	padLine = CacheLineSize
+)
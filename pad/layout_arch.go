@@ -0,0 +1,16 @@
+//go:build !pad32 && !pad64 && !padnone
+
+package pad
+
+// CacheLineSize is the cache line size the padded types are aligned to, the
+// line size of the target architecture unless a pad tag overrides it. Use it
+// to pad structs of your own.
+const CacheLineSize = archLineSize
+
+const (
+	padded  = true
+	pad4    = CacheLineSize - 4
+	pad8    = CacheLineSize - 8
+	padPtr  = CacheLineSize - ptrSize
+	padLine = CacheLineSize
+)
@@ -0,0 +1,13 @@
+//go:build padnone
+
+package pad
+
+const CacheLineSize = archLineSize
+
+const (
+	padded  = false
+	pad4    = 0
+	pad8    = 0
+	padPtr  = 0
+	padLine = 0
+)
@@ -0,0 +1,37 @@
+package pad
+
+import (
+	"testing"
+	"unsafe"
+)
+
+func TestLayout_Sizes(t *testing.T) {
+	want := uintptr(8)
+	if Current.Padded {
+		want = uintptr(Current.CacheLineSize)
+	}
+	if got := unsafe.Sizeof(AtomicUint64{}); got != want {
+		t.Errorf("Expected AtomicUint64 of %d bytes, got %d", want, got)
+	}
+	if got := unsafe.Sizeof(AtomicInt64{}); got != want {
+		t.Errorf("Expected AtomicInt64 of %d bytes, got %d", want, got)
+	}
+	if Current.Padded {
+		if got := unsafe.Sizeof(AtomicUint32{}); got != want {
+			t.Errorf("Expected AtomicUint32 of %d bytes, got %d", want, got)
+		}
+	}
+}
+
+func TestLayout_Detected(t *testing.T) {
+	n := Detected()
+	if n == 0 {
+		t.Skip("cache line size not available")
+	}
+	if n&(n-1) != 0 {
+		t.Errorf("Expected a power of two line size, got %d", n)
+	}
+	if Current.Padded && CacheLineSize < n {
+		t.Logf("Built for %d-byte lines on a machine with %d-byte lines", CacheLineSize, n)
+	}
+}
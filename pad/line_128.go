@@ -0,0 +1,7 @@
+//go:build (darwin && arm64) || ppc64 || ppc64le
+
+package pad
+
+// archLineSize is the cache line size of the target architecture. Apple
+// M-series cores and POWER use 128-byte lines.
+const archLineSize = 128
@@ -0,0 +1,6 @@
+//go:build s390x
+
+package pad
+
+// archLineSize is the cache line size of the target architecture.
+const archLineSize = 256
@@ -0,0 +1,6 @@
+//go:build !(darwin && arm64) && !ppc64 && !ppc64le && !s390x
+
+package pad
+
+// archLineSize is the cache line size of the target architecture.
+const archLineSize = 64
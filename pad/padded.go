@@ -0,0 +1,55 @@
+package pad
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// ptrSize is the size of a pointer and a uintptr on the target platform.
+const ptrSize = 4 << (^uintptr(0) >> 63)
+
+// Padded keeps a value of T off the cache lines of whatever precedes it, e.g.
+// per-shard counters in a slice. Go cannot size an array from a type
+// parameter, so a full line of padding leads the value instead of the rest of
+// its line: elements of a []Padded[T] are at least CacheLineSize apart.
+type Padded[T any] struct {
+	_     [padLine]byte
+	Value T
+}
+
+// AtomicPointer is an atomic.Pointer padded to a cache line.
+type AtomicPointer[T any] struct {
+	_ [padPtr]byte
+	atomic.Pointer[T]
+}
+
+type AtomicUintptr struct {
+	_ [padPtr]byte
+	atomic.Uintptr
+}
+
+// AtomicDuration is an atomic time.Duration padded to a cache line.
+type AtomicDuration struct {
+	_ [pad8]byte
+	v atomic.Int64
+}
+
+func (d *AtomicDuration) Load() time.Duration {
+	return time.Duration(d.v.Load())
+}
+
+func (d *AtomicDuration) Store(v time.Duration) {
+	d.v.Store(int64(v))
+}
+
+func (d *AtomicDuration) Add(delta time.Duration) time.Duration {
+	return time.Duration(d.v.Add(int64(delta)))
+}
+
+func (d *AtomicDuration) Swap(v time.Duration) time.Duration {
+	return time.Duration(d.v.Swap(int64(v)))
+}
+
+func (d *AtomicDuration) CompareAndSwap(old, new time.Duration) bool {
+	return d.v.CompareAndSwap(int64(old), int64(new))
+}
@@ -0,0 +1,55 @@
+package pad
+
+import (
+	"testing"
+	"time"
+	"unsafe"
+)
+
+func TestPadded_Sizes(t *testing.T) {
+	want := func(size uintptr) uintptr {
+		if Current.Padded {
+			return uintptr(Current.CacheLineSize)
+		}
+		return size
+	}
+	if got := unsafe.Sizeof(AtomicPointer[int]{}); got != want(unsafe.Sizeof(uintptr(0))) {
+		t.Errorf("Expected AtomicPointer of %d bytes, got %d", want(unsafe.Sizeof(uintptr(0))), got)
+	}
+	if got := unsafe.Sizeof(AtomicUintptr{}); got != want(unsafe.Sizeof(uintptr(0))) {
+		t.Errorf("Expected AtomicUintptr of %d bytes, got %d", want(unsafe.Sizeof(uintptr(0))), got)
+	}
+	if got := unsafe.Sizeof(AtomicDuration{}); got != want(8) {
+		t.Errorf("Expected AtomicDuration of %d bytes, got %d", want(8), got)
+	}
+}
+
+func TestPadded_Slice(t *testing.T) {
+	type counter struct{ hits, misses uint32 }
+	shards := make([]Padded[counter], 4)
+	shards[1].Value.hits = 7
+	if shards[0].Value.hits != 0 || shards[1].Value.hits != 7 {
+		t.Fatalf("Expected independent values, got %+v", shards)
+	}
+	if !Current.Padded {
+		return
+	}
+	apart := uintptr(unsafe.Pointer(&shards[1].Value)) - uintptr(unsafe.Pointer(&shards[0].Value))
+	if apart < uintptr(CacheLineSize) {
+		t.Errorf("Expected values at least %d bytes apart, got %d", CacheLineSize, apart)
+	}
+}
+
+func TestAtomicDuration(t *testing.T) {
+	var d AtomicDuration
+	d.Store(time.Second)
+	if got := d.Add(time.Millisecond); got != time.Second+time.Millisecond {
+		t.Errorf("Expected 1.001s, got %v", got)
+	}
+	if !d.CompareAndSwap(time.Second+time.Millisecond, time.Minute) || d.Load() != time.Minute {
+		t.Errorf("Expected a successful swap to 1m, got %v", d.Load())
+	}
+	if old := d.Swap(0); old != time.Minute {
+		t.Errorf("Expected the old value 1m, got %v", old)
+	}
+}
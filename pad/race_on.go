@@ -0,0 +1,6 @@
+//go:build race
+
+package pad
+
+// raceEnabled reports whether the race detector is on, see LatestValue.
+const raceEnabled = true
@@ -0,0 +1,78 @@
+package pad
+
+import "runtime"
+
+// Sequence hands out sequence numbers to producers with the protocol of the
+// disruptor's writer cursor. A producer claims a range, fills the slots and
+// publishes it. Until then the cursor is odd, so the range's slots are owned
+// by one producer and readers never see a partly written range. Sequence
+// numbers start at 0. Load returns the number published, so a Sequence gates
+// readers like any other Barrier.
+type Sequence struct {
+	cursor   AtomicUint64
+	capacity uint64
+	gating   Barrier
+}
+
+// NewSequence creates a sequence whose claims stay within capacity of gating,
+// the number of sequences the slowest reader has consumed. A nil gating
+// barrier leaves claims unbounded.
+func NewSequence(capacity uint64, gating Barrier) *Sequence {
+	return &Sequence{capacity: capacity, gating: gating}
+}
+
+// Load returns the number of published sequences, which is also the next
+// sequence readers wait for.
+func (s *Sequence) Load() uint64 {
+	return s.cursor.Load() >> 1
+}
+
+// TryNext claims the n sequences after the published ones and returns the
+// highest, the range is hi-n+1 to hi. It fails while another producer holds a
+// claim or if the range would run more than capacity ahead of the gating
+// barrier. n must be at least 1.
+func (s *Sequence) TryNext(n uint64) (hi uint64, ok bool) {
+	head := s.cursor.Load()
+	if head&1 == 1 {
+		return 0, false
+	}
+	next := head>>1 + n
+	if s.gating != nil && next > s.gating.Load()+s.capacity {
+		return 0, false
+	}
+	if !s.cursor.CompareAndSwap(head, head+1) {
+		return 0, false
+	}
+	return next - 1, true
+}
+
+// Next claims one sequence, waiting until it can.
+func (s *Sequence) Next() uint64 {
+	return s.NextN(1)
+}
+
+// NextN claims n sequences and returns the highest, waiting until it can. n
+// larger than the capacity never fits.
+func (s *Sequence) NextN(n uint64) (hi uint64) {
+	for attempt := 0; ; attempt++ {
+		if hi, ok := s.TryNext(n); ok {
+			return hi
+		}
+		if attempt < 5 {
+			SpinHint()
+		} else {
+			runtime.Gosched()
+		}
+	}
+}
+
+// Publish makes the claimed range ending at hi visible to readers and ends
+// the claim.
+func (s *Sequence) Publish(hi uint64) {
+	s.cursor.Store(2 * (hi + 1))
+}
+
+// IsAvailable reports whether seq has been published.
+func (s *Sequence) IsAvailable(seq uint64) bool {
+	return seq < s.Load()
+}
@@ -0,0 +1,80 @@
+package pad
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+)
+
+func TestSequence_Claims(t *testing.T) {
+	s := NewSequence(0, nil)
+	if hi := s.Next(); hi != 0 {
+		t.Fatalf("expected the first sequence 0, got %d", hi)
+	}
+	if _, ok := s.TryNext(1); ok {
+		t.Fatal("expected a claim to exclude other producers")
+	}
+	if s.IsAvailable(0) {
+		t.Fatal("expected an unpublished sequence to be unavailable")
+	}
+	s.Publish(0)
+	if !s.IsAvailable(0) || s.Load() != 1 {
+		t.Fatalf("expected 1 published, got %d", s.Load())
+	}
+	hi := s.NextN(4)
+	if hi != 4 {
+		t.Fatalf("expected the range 1-4, got hi %d", hi)
+	}
+	s.Publish(hi)
+	if s.Load() != 5 || s.IsAvailable(5) {
+		t.Fatalf("expected 5 published, got %d", s.Load())
+	}
+}
+
+func TestSequence_Gating(t *testing.T) {
+	var consumed AtomicUint64
+	s := NewSequence(4, &consumed)
+	s.Publish(s.NextN(4))
+	if _, ok := s.TryNext(1); ok {
+		t.Fatal("expected a full ring to refuse a claim")
+	}
+	consumed.Store(2)
+	hi, ok := s.TryNext(2)
+	if !ok || hi != 5 {
+		t.Fatalf("expected the range 4-5, got %d %v", hi, ok)
+	}
+	s.Publish(hi)
+}
+
+func TestSequence_Ring(t *testing.T) {
+	const capacity, producers, perProducer = 8, 4, 1000
+	var consumed AtomicUint64
+	s := NewSequence(capacity, &consumed)
+	slots := make([]uint64, capacity)
+
+	var wg sync.WaitGroup
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perProducer; i += 2 {
+				hi := s.NextN(2)
+				slots[(hi-1)%capacity] = 1
+				slots[hi%capacity] = 1
+				s.Publish(hi)
+			}
+		}()
+	}
+	var sum uint64
+	for seq := uint64(0); seq < producers*perProducer; seq++ {
+		for !s.IsAvailable(seq) {
+			runtime.Gosched()
+		}
+		sum += slots[seq%capacity]
+		consumed.Store(seq + 1)
+	}
+	wg.Wait()
+	if sum != producers*perProducer {
+		t.Fatalf("expected %d events, got %d", producers*perProducer, sum)
+	}
+}
@@ -0,0 +1,9 @@
+//go:build amd64 || arm64
+
+package pad
+
+// SpinHint tells the core that the caller is in a spin-wait loop: PAUSE on
+// amd64 and YIELD on arm64. It lowers the power drawn by the spin, frees
+// execution resources for a sibling hyper-thread and avoids the memory-order
+// pipeline flush when the awaited cache line finally changes.
+func SpinHint()
@@ -0,0 +1,6 @@
+#include "textflag.h"
+
+// func SpinHint()
+TEXT ·SpinHint(SB), NOSPLIT, $0-0
+	YIELD
+	RET
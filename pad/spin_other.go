@@ -0,0 +1,7 @@
+//go:build !amd64 && !arm64
+
+package pad
+
+// SpinHint tells the core that the caller is in a spin-wait loop. The target
+// architecture has no hint, so it does nothing.
+func SpinHint() {}
@@ -0,0 +1,15 @@
+package pad
+
+import "testing"
+
+func TestSpinHint(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		SpinHint()
+	}
+}
+
+func BenchmarkSpinHint(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		SpinHint()
+	}
+}
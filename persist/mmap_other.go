@@ -0,0 +1,16 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd)
+
+package persist
+
+import (
+	"errors"
+	"os"
+)
+
+func mmap(*os.File, int) ([]byte, error) {
+	return nil, errors.ErrUnsupported
+}
+
+func munmap([]byte) error {
+	return nil
+}
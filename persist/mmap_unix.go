@@ -0,0 +1,16 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd
+
+package persist
+
+import (
+	"os"
+	"syscall"
+)
+
+func mmap(f *os.File, size int) ([]byte, error) {
+	return syscall.Mmap(int(f.Fd()), 0, size, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED)
+}
+
+func munmap(mem []byte) error {
+	return syscall.Munmap(mem)
+}
@@ -0,0 +1,308 @@
+// Package persist provides a queue whose contents and consumer position live
+// in a memory-mapped file, so unconsumed entries survive a crash of the
+// process and are delivered again when the file is reopened.
+package persist
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/dk-open/ring"
+	"github.com/dk-open/ring/pad"
+	"hash/crc32"
+	"os"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unsafe"
+)
+
+// The file starts with a header of headerSize bytes:
+//
+//	magic [8]byte, payload size uint32, reserved uint32, capacity uint64,
+//	consumed sequence uint64
+//
+// followed by capacity slots. A slot holds the sequence of its entry plus one,
+// the payload length and its CRC-32 in front of the payload. The sequence is
+// stored last, a slot whose sequence does not match its position or whose
+// checksum fails is not part of the queue. A payload length of tombstone marks
+// a hole left by a producer that crashed while later entries were complete.
+// Numbers are stored in the byte order of the machine.
+const (
+	headerSize     = 64
+	slotHeaderSize = 16
+	tailOffset     = 24
+	tombstone      = ^uint32(0)
+)
+
+var magic = [8]byte{'r', 'i', 'n', 'g', 'p', 'q', 0, 1}
+
+var (
+	ErrLayout   = errors.New("persist: file was created with a different layout")
+	ErrTooLarge = errors.New("persist: encoded entry exceeds the slot size")
+	ErrCorrupt  = errors.New("persist: file is not a persistent queue")
+)
+
+// Codec encodes entries into slots. MaxSize is the largest encoded size and
+// sets the slot size of the file. Decode must not retain data.
+type Codec[T any] interface {
+	MaxSize() int
+	Append(dst []byte, v T) ([]byte, error)
+	Decode(data []byte) (T, error)
+}
+
+// Fixed returns a codec for fixed-size values as defined by encoding/binary,
+// e.g. structs of numbers and arrays. Opening a queue with the codec of a
+// type without a fixed size fails.
+func Fixed[T any]() Codec[T] {
+	var zero T
+	return fixed[T]{size: binary.Size(zero)}
+}
+
+type fixed[T any] struct {
+	size int
+}
+
+func (c fixed[T]) MaxSize() int {
+	return c.size
+}
+
+func (c fixed[T]) Append(dst []byte, v T) ([]byte, error) {
+	return binary.Append(dst, binary.LittleEndian, v)
+}
+
+func (c fixed[T]) Decode(data []byte) (v T, err error) {
+	_, err = binary.Decode(data, binary.LittleEndian, &v)
+	return v, err
+}
+
+// Queue is a persistent queue for any number of producers and a single
+// consumer goroutine.
+type Queue[T any] struct {
+	f        *os.File
+	mem      []byte
+	codec    Codec[T]
+	cap      uint64
+	slotSize uint64
+	maxSize  int
+	head     pad.AtomicUint64
+	// tail is the consumed sequence in the header, owned by the consumer
+	tail    *atomic.Uint64
+	buffers sync.Pool
+	closed  atomic.Bool
+}
+
+// Open maps the queue file at path, creating it for capacity entries, a power
+// of two, if it does not exist. An existing file must have been created with
+// the same capacity and slot size; its unconsumed entries are dequeued first.
+func Open[T any](path string, capacity uint64, codec Codec[T]) (*Queue[T], error) {
+	if capacity == 0 || capacity&(capacity-1) != 0 || capacity > ring.MaxCapacity {
+		return nil, ring.ErrCapacity
+	}
+	maxSize := codec.MaxSize()
+	if maxSize <= 0 || uint64(maxSize) > 1<<31 {
+		return nil, fmt.Errorf("persist: invalid codec size %d", maxSize)
+	}
+	slotSize := slotHeaderSize + (uint64(maxSize)+7)&^7
+	size := headerSize + capacity*slotSize
+
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	created := info.Size() == 0
+	if created {
+		err = f.Truncate(int64(size))
+	} else if uint64(info.Size()) != size {
+		err = fmt.Errorf("%w: %d bytes, expected %d", ErrLayout, info.Size(), size)
+	}
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	mem, err := mmap(f, int(size))
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	q := &Queue[T]{
+		f:        f,
+		mem:      mem,
+		codec:    codec,
+		cap:      capacity,
+		slotSize: slotSize,
+		maxSize:  maxSize,
+		tail:     (*atomic.Uint64)(unsafe.Pointer(&mem[tailOffset])),
+	}
+	q.buffers.New = func() any {
+		buf := make([]byte, 0, maxSize)
+		return &buf
+	}
+	if created {
+		copy(mem, magic[:])
+		binary.NativeEndian.PutUint32(mem[8:], uint32(maxSize))
+		binary.NativeEndian.PutUint64(mem[16:], capacity)
+	} else if err = q.recover(); err != nil {
+		_ = q.Close()
+		return nil, err
+	}
+	return q, nil
+}
+
+// recover validates the header and finds the entries written after the
+// consumed sequence. Producers commit out of order, so the whole window is
+// scanned: incomplete entries before the last complete one are tombstoned and
+// skipped by Dequeue, the slots after it are cleared, so entries of an earlier
+// lap are never mistaken for new ones.
+func (q *Queue[T]) recover() error {
+	if [8]byte(q.mem[:8]) != magic {
+		return ErrCorrupt
+	}
+	if size, capacity := binary.NativeEndian.Uint32(q.mem[8:]), binary.NativeEndian.Uint64(q.mem[16:]); int(size) != q.maxSize || capacity != q.cap {
+		return fmt.Errorf("%w: %d slots of %d bytes", ErrLayout, capacity, size)
+	}
+	tail := q.tail.Load()
+	head := tail
+	for seq := tail; seq < tail+q.cap; seq++ {
+		if q.valid(seq) {
+			head = seq + 1
+		}
+	}
+	for seq := tail; seq < head; seq++ {
+		if !q.valid(seq) {
+			binary.NativeEndian.PutUint32(q.slot(seq)[8:], tombstone)
+			q.marker(seq).Store(seq + 1)
+		}
+	}
+	for seq := head; seq < tail+q.cap; seq++ {
+		q.marker(seq).Store(0)
+	}
+	q.head.Store(head)
+	return nil
+}
+
+// valid reports whether the slot of seq holds a complete entry of seq.
+func (q *Queue[T]) valid(seq uint64) bool {
+	if q.marker(seq).Load() != seq+1 {
+		return false
+	}
+	slot := q.slot(seq)
+	n := binary.NativeEndian.Uint32(slot[8:])
+	if int(n) > q.maxSize {
+		return false
+	}
+	return crc32.ChecksumIEEE(slot[slotHeaderSize:slotHeaderSize+n]) == binary.NativeEndian.Uint32(slot[12:])
+}
+
+func (q *Queue[T]) slot(seq uint64) []byte {
+	off := headerSize + (seq&(q.cap-1))*q.slotSize
+	return q.mem[off : off+q.slotSize]
+}
+
+func (q *Queue[T]) marker(seq uint64) *atomic.Uint64 {
+	return (*atomic.Uint64)(unsafe.Pointer(&q.slot(seq)[0]))
+}
+
+// Enqueue appends v unless the queue is full or closed.
+func (q *Queue[T]) Enqueue(v T) (bool, error) {
+	if q.closed.Load() {
+		return false, ring.ErrClosed
+	}
+	buf := q.buffers.Get().(*[]byte)
+	defer q.buffers.Put(buf)
+	data, err := q.codec.Append((*buf)[:0], v)
+	if err != nil {
+		return false, err
+	}
+	if len(data) > q.maxSize {
+		return false, fmt.Errorf("%w: %d bytes", ErrTooLarge, len(data))
+	}
+	for {
+		head := q.head.Load()
+		if head-q.tail.Load() >= q.cap {
+			return false, nil
+		}
+		if q.head.CompareAndSwap(head, head+1) {
+			q.write(head, data)
+			return true, nil
+		}
+		runtime.Gosched()
+	}
+}
+
+// MustEnqueue appends v, waiting while the queue is full.
+func (q *Queue[T]) MustEnqueue(v T) error {
+	for attempt := 0; ; attempt++ {
+		ok, err := q.Enqueue(v)
+		if ok || err != nil {
+			return err
+		}
+		if attempt < 20 {
+			runtime.Gosched()
+			continue
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func (q *Queue[T]) write(seq uint64, data []byte) {
+	slot := q.slot(seq)
+	copy(slot[slotHeaderSize:], data)
+	binary.NativeEndian.PutUint32(slot[8:], uint32(len(data)))
+	binary.NativeEndian.PutUint32(slot[12:], crc32.ChecksumIEEE(data))
+	q.marker(seq).Store(seq + 1)
+}
+
+// Dequeue removes the oldest entry. It must be called from a single consumer
+// goroutine. An entry that fails to decode is consumed and its error returned.
+// Holes tombstoned on reopening are skipped.
+func (q *Queue[T]) Dequeue() (v T, ok bool, err error) {
+	if q.closed.Load() {
+		return v, false, ring.ErrClosed
+	}
+	for {
+		tail := q.tail.Load()
+		if q.marker(tail).Load() != tail+1 {
+			return v, false, nil
+		}
+		slot := q.slot(tail)
+		n := binary.NativeEndian.Uint32(slot[8:])
+		if n == tombstone {
+			q.tail.Store(tail + 1)
+			continue
+		}
+		v, err = q.codec.Decode(slot[slotHeaderSize : slotHeaderSize+n])
+		q.tail.Store(tail + 1)
+		return v, err == nil, err
+	}
+}
+
+// Len returns the number of entries claimed by producers and not consumed,
+// including tombstoned holes.
+func (q *Queue[T]) Len() uint64 {
+	return q.head.Load() - q.tail.Load()
+}
+
+// Sync flushes the mapped file to stable storage. Without it entries survive
+// a crash of the process but not necessarily of the machine.
+func (q *Queue[T]) Sync() error {
+	return q.f.Sync()
+}
+
+// Close unmaps and closes the file. No other method may be in progress.
+func (q *Queue[T]) Close() error {
+	if q.closed.Swap(true) {
+		return nil
+	}
+	err := munmap(q.mem)
+	if cerr := q.f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
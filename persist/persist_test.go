@@ -0,0 +1,208 @@
+package persist
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+type entry struct {
+	ID    uint64
+	Value int32
+}
+
+type stringCodec struct{}
+
+func (stringCodec) MaxSize() int { return 16 }
+
+func (stringCodec) Append(dst []byte, v string) ([]byte, error) {
+	return append(dst, v...), nil
+}
+
+func (stringCodec) Decode(data []byte) (string, error) {
+	return string(data), nil
+}
+
+func TestQueue_Reopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue")
+	q, err := Open(path, 8, Fixed[entry]())
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	for i := uint64(0); i < 5; i++ {
+		if err := q.MustEnqueue(entry{ID: i, Value: int32(i * 10)}); err != nil {
+			t.Fatalf("MustEnqueue failed: %v", err)
+		}
+	}
+	if v, ok, err := q.Dequeue(); !ok || err != nil || v.ID != 0 {
+		t.Fatalf("Expected entry 0, got %+v, %v, %v", v, ok, err)
+	}
+	if err := q.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	q, err = Open(path, 8, Fixed[entry]())
+	if err != nil {
+		t.Fatalf("Reopen failed: %v", err)
+	}
+	defer q.Close()
+	if n := q.Len(); n != 4 {
+		t.Fatalf("Expected 4 unconsumed entries, got %d", n)
+	}
+	for want := uint64(1); want < 5; want++ {
+		v, ok, err := q.Dequeue()
+		if !ok || err != nil || v != (entry{ID: want, Value: int32(want * 10)}) {
+			t.Fatalf("Expected entry %d, got %+v, %v, %v", want, v, ok, err)
+		}
+	}
+	if _, ok, _ := q.Dequeue(); ok {
+		t.Error("Expected an empty queue")
+	}
+}
+
+func TestQueue_IncompleteEntry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue")
+	q, err := Open[string](path, 4, stringCodec{})
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	for _, s := range []string{"a", "b", "c"} {
+		if err := q.MustEnqueue(s); err != nil {
+			t.Fatalf("MustEnqueue failed: %v", err)
+		}
+	}
+	// A torn write of "b": the checksum no longer matches the payload
+	q.slot(1)[slotHeaderSize] = 'x'
+	if err := q.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	q, err = Open[string](path, 4, stringCodec{})
+	if err != nil {
+		t.Fatalf("Reopen failed: %v", err)
+	}
+	defer q.Close()
+	// "c" was complete when "b" was torn, it is kept behind a tombstone
+	if n := q.Len(); n != 3 {
+		t.Fatalf("Expected the entries up to the last complete one, got %d", n)
+	}
+	if err := q.MustEnqueue("d"); err != nil {
+		t.Fatalf("MustEnqueue failed: %v", err)
+	}
+	for _, want := range []string{"a", "c", "d"} {
+		if v, ok, err := q.Dequeue(); !ok || err != nil || v != want {
+			t.Fatalf("Expected %q, got %q, %v, %v", want, v, ok, err)
+		}
+	}
+	if _, ok, _ := q.Dequeue(); ok {
+		t.Error("Expected an empty queue")
+	}
+}
+
+func TestQueue_OutOfOrderCommit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue")
+	q, err := Open[string](path, 4, stringCodec{})
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	for _, s := range []string{"a", "b", "c"} {
+		if err := q.MustEnqueue(s); err != nil {
+			t.Fatalf("MustEnqueue failed: %v", err)
+		}
+	}
+	// The producer of "b" crashed before committing, "c" was committed
+	q.marker(1).Store(0)
+	if err := q.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	q, err = Open[string](path, 4, stringCodec{})
+	if err != nil {
+		t.Fatalf("Reopen failed: %v", err)
+	}
+	defer q.Close()
+	for _, want := range []string{"a", "c"} {
+		if v, ok, err := q.Dequeue(); !ok || err != nil || v != want {
+			t.Fatalf("Expected %q, got %q, %v, %v", want, v, ok, err)
+		}
+	}
+	if _, ok, _ := q.Dequeue(); ok {
+		t.Error("Expected an empty queue")
+	}
+}
+
+func TestQueue_Errors(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "queue")
+	q, err := Open[string](path, 4, stringCodec{})
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	if _, err := q.Enqueue("longer than sixteen bytes"); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("Expected ErrTooLarge, got %v", err)
+	}
+	for i := 0; i < 4; i++ {
+		if ok, err := q.Enqueue("x"); !ok || err != nil {
+			t.Fatalf("Enqueue failed: %v", err)
+		}
+	}
+	if ok, err := q.Enqueue("x"); ok || err != nil {
+		t.Errorf("Expected a full queue, got %v, %v", ok, err)
+	}
+	_ = q.Close()
+
+	if _, err := Open[string](path, 8, stringCodec{}); !errors.Is(err, ErrLayout) {
+		t.Errorf("Expected ErrLayout for another capacity, got %v", err)
+	}
+	if _, err := Open(filepath.Join(dir, "bad"), 4, Fixed[[]int]()); err == nil {
+		t.Error("Expected an error for a codec without a fixed size")
+	}
+	other := filepath.Join(dir, "other")
+	if err := os.WriteFile(other, make([]byte, headerSize+4*32), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if _, err := Open[string](other, 4, stringCodec{}); !errors.Is(err, ErrCorrupt) {
+		t.Errorf("Expected ErrCorrupt, got %v", err)
+	}
+}
+
+func TestQueue_Concurrent(t *testing.T) {
+	q, err := Open(filepath.Join(t.TempDir(), "queue"), 64, Fixed[entry]())
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer q.Close()
+
+	const producers, perProducer = 4, 1000
+	var wg sync.WaitGroup
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perProducer; i++ {
+				if err := q.MustEnqueue(entry{ID: uint64(p), Value: int32(i)}); err != nil {
+					t.Errorf("MustEnqueue failed: %v", err)
+					return
+				}
+			}
+		}()
+	}
+	next := make([]int32, producers)
+	for n := 0; n < producers*perProducer; {
+		v, ok, err := q.Dequeue()
+		if err != nil {
+			t.Fatalf("Dequeue failed: %v", err)
+		}
+		if !ok {
+			continue
+		}
+		if v.Value != next[v.ID] {
+			t.Fatalf("Producer %d: expected %d, got %d", v.ID, next[v.ID], v.Value)
+		}
+		next[v.ID]++
+		n++
+	}
+	wg.Wait()
+}
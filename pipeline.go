@@ -0,0 +1,71 @@
+package ring
+
+import (
+	"context"
+	"errors"
+)
+
+// Pipeline is a chain of disruptors connected by transforming readers, ending
+// in a disruptor of T. Consumers attach readers to Output.
+type Pipeline[T any] struct {
+	ctx      context.Context
+	out      IDisruptor[T]
+	upstream func(ctx context.Context) error
+}
+
+// From starts a pipeline at src. Stages created from it run until ctx is done
+// or the pipeline is closed.
+func From[T any](ctx context.Context, src IDisruptor[T]) *Pipeline[T] {
+	return &Pipeline[T]{ctx: ctx, out: src}
+}
+
+// Stage appends a disruptor configured by opts and a reader transforming the
+// events of src into it. f reports false to filter an event out. A full stage
+// blocks the reader, so backpressure reaches the producers of the source.
+func Stage[A, B any](src *Pipeline[A], f func(A) (B, bool), opts ...Option) (*Pipeline[B], error) {
+	dst, err := NewDisruptor[B](src.ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	src.out.HandleWith(func(value A) {
+		derived, ok := f(value)
+		if !ok {
+			return
+		}
+		_ = forward(src.ctx, dst, derived)
+	})
+	return &Pipeline[B]{ctx: src.ctx, out: dst, upstream: src.Close}, nil
+}
+
+// Output returns the disruptor at the end of the pipeline.
+func (p *Pipeline[T]) Output() IDisruptor[T] {
+	return p.out
+}
+
+// Close closes the stages from the source on, each after the previous one has
+// drained into it, so every published event reaches the output readers. If
+// ctx is done first the remaining stages are cancelled.
+func (p *Pipeline[T]) Close(ctx context.Context) error {
+	if p.upstream != nil {
+		if err := p.upstream(ctx); err != nil {
+			return err
+		}
+	}
+	return p.out.Close(ctx)
+}
+
+// forward publishes value to dst, waiting while dst is full. MustEnqueue gives
+// up after a bounded backoff, forward keeps waiting until dst is closed or ctx
+// is done. It returns the reason value was not published.
+func forward[T any](ctx context.Context, dst IDisruptor[T], value T) error {
+	for {
+		err := dst.MustEnqueue(value)
+		if err == nil || errors.Is(err, ErrClosed) || errors.Is(err, ErrDropped) ||
+			errors.Is(err, ErrSequenceExhausted) {
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+}
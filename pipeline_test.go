@@ -0,0 +1,55 @@
+package ring
+
+import (
+	"context"
+	"strconv"
+	"testing"
+)
+
+func TestPipeline(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	src, err := Disruptor[int](ctx, 8)
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	evens, err := Stage(From(ctx, src), func(v int) (int, bool) { return v, v%2 == 0 }, WithCapacity(4))
+	if err != nil {
+		t.Fatalf("Stage failed: %v", err)
+	}
+	labels, err := Stage(evens, func(v int) (string, bool) { return strconv.Itoa(v), true }, WithCapacity(4))
+	if err != nil {
+		t.Fatalf("Stage failed: %v", err)
+	}
+	// Small stages make the source producer wait for the sink
+	var received []string
+	labels.Output().HandleWith(func(v string) {
+		received = append(received, v)
+	})
+
+	const n = 200
+	for i := 0; i < n; i++ {
+		if err := src.MustEnqueue(i); err != nil {
+			t.Fatalf("MustEnqueue failed: %v", err)
+		}
+	}
+	if err := labels.Close(context.Background()); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if len(received) != n/2 {
+		t.Fatalf("Expected %d events, got %d", n/2, len(received))
+	}
+	for i, v := range received {
+		if v != strconv.Itoa(2*i) {
+			t.Fatalf("Expected %d at %d, got %s", 2*i, i, v)
+		}
+	}
+	if src.Enqueue(1) {
+		t.Error("Expected the source to be closed")
+	}
+
+	if _, err := Stage(From(ctx, src), func(v int) (int, bool) { return v, true }, WithCapacity(3)); err == nil {
+		t.Error("Expected an invalid stage capacity to fail")
+	}
+}
@@ -0,0 +1,100 @@
+// Package pool provides a bounded object pool backed by a ring queue. Unlike
+// sync.Pool it keeps its objects across garbage collections and never holds
+// more than its capacity.
+package pool
+
+import (
+	"github.com/dk-open/ring"
+	"sync/atomic"
+)
+
+// Option configures a pool created by New.
+type Option[T any] func(*Pool[T])
+
+// WithReset sets a hook applied to every object returned by Put, e.g. to
+// truncate a buffer before it is reused.
+func WithReset[T any](reset func(v T)) Option[T] {
+	return func(p *Pool[T]) {
+		p.reset = reset
+	}
+}
+
+// WithPrefill fills the pool with n objects from the factory up front, so the
+// first Get calls do not allocate.
+func WithPrefill[T any](n int) Option[T] {
+	return func(p *Pool[T]) {
+		p.prefill = n
+	}
+}
+
+// Pool reuses objects of type T. It is safe for concurrent use.
+type Pool[T any] struct {
+	free     ring.IQueue[T]
+	capacity uint64
+	factory  func() T
+	reset    func(v T)
+	prefill  int
+
+	created   atomic.Uint64
+	discarded atomic.Uint64
+}
+
+// Stats counts the objects created by the factory and the objects dropped by
+// Put because the pool was full.
+type Stats struct {
+	Created   uint64
+	Discarded uint64
+	Idle      uint64
+}
+
+// New creates a pool holding up to capacity idle objects, capacity must be a
+// power of two. Get falls back to factory when the pool is empty.
+func New[T any](capacity uint64, factory func() T, opts ...Option[T]) (*Pool[T], error) {
+	free, err := ring.Queue[T](capacity)
+	if err != nil {
+		return nil, err
+	}
+	p := &Pool[T]{free: free, capacity: capacity, factory: factory}
+	for _, opt := range opts {
+		opt(p)
+	}
+	for i := 0; i < p.prefill && free.Enqueue(p.create()); i++ {
+	}
+	return p, nil
+}
+
+func (p *Pool[T]) create() T {
+	p.created.Add(1)
+	return p.factory()
+}
+
+// Get takes an idle object or creates one with the factory.
+func (p *Pool[T]) Get() T {
+	if v, ok := p.free.Dequeue(); ok {
+		return v
+	}
+	return p.create()
+}
+
+// Put resets v and keeps it for reuse. If the pool is full v is left to the
+// garbage collector.
+func (p *Pool[T]) Put(v T) {
+	if p.reset != nil {
+		p.reset(v)
+	}
+	// Enqueue also fails on a contended queue, only a full pool drops v
+	for !p.free.Enqueue(v) {
+		if st := p.free.Stats(); st.Len >= p.capacity {
+			p.discarded.Add(1)
+			return
+		}
+	}
+}
+
+func (p *Pool[T]) Stats() Stats {
+	return Stats{
+		Created:   p.created.Load(),
+		Discarded: p.discarded.Load(),
+		Idle:      p.free.Stats().Len,
+	}
+}
@@ -0,0 +1,72 @@
+package pool
+
+import (
+	"bytes"
+	"runtime"
+	"sync"
+	"testing"
+)
+
+func TestPool_Reuse(t *testing.T) {
+	p, err := New(4, func() *bytes.Buffer { return &bytes.Buffer{} },
+		WithReset(func(b *bytes.Buffer) { b.Reset() }),
+		WithPrefill[*bytes.Buffer](2),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create pool: %v", err)
+	}
+	if st := p.Stats(); st.Created != 2 || st.Idle != 2 {
+		t.Fatalf("Expected 2 prefilled objects, got %+v", st)
+	}
+
+	b := p.Get()
+	b.WriteString("payload")
+	p.Put(b)
+	// Idle objects survive garbage collection
+	runtime.GC()
+	seen := false
+	for i := 0; i < 2; i++ {
+		v := p.Get()
+		if v == b {
+			seen = true
+			if v.Len() != 0 {
+				t.Errorf("Expected a reset buffer, got %q", v.String())
+			}
+		}
+	}
+	if !seen {
+		t.Error("Expected the buffer to be reused")
+	}
+
+	for i := 0; i < 6; i++ {
+		p.Put(&bytes.Buffer{})
+	}
+	if st := p.Stats(); st.Idle != 4 || st.Discarded != 2 {
+		t.Errorf("Expected 4 idle and 2 discarded objects, got %+v", st)
+	}
+	if _, err := New(3, func() int { return 0 }); err == nil {
+		t.Error("Expected an invalid capacity to fail")
+	}
+}
+
+func TestPool_Concurrent(t *testing.T) {
+	p, err := New(64, func() []byte { return make([]byte, 0, 64) })
+	if err != nil {
+		t.Fatalf("Failed to create pool: %v", err)
+	}
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				b := p.Get()
+				p.Put(append(b[:0], byte(i)))
+			}
+		}()
+	}
+	wg.Wait()
+	if st := p.Stats(); st.Created > 8 {
+		t.Errorf("Expected objects to be reused, created %d", st.Created)
+	}
+}
@@ -0,0 +1,130 @@
+// Package promise provides futures recycled through a ring, completing and
+// awaiting them does not allocate.
+package promise
+
+import (
+	"context"
+	"fmt"
+	"github.com/dk-open/ring"
+	"runtime"
+	"sync/atomic"
+)
+
+var ErrExhausted = fmt.Errorf("promise pool exhausted")
+
+// A future's state word holds its generation above two status bits.
+const (
+	pending uint64 = iota
+	completing
+	done
+
+	statusBits = 2
+	statusMask = 1<<statusBits - 1
+)
+
+// Future is a value completed once by a producer and awaited by a consumer.
+// It belongs to its pool. Every Get hands it out under a new generation id,
+// Complete and Release with the id of an earlier generation have no effect,
+// so a late reply to a timed-out request cannot complete the future of the
+// next caller.
+type Future[T any] struct {
+	pool  *Pool[T]
+	state completion
+	wait  *ring.WaitableBarrier
+	value T
+	err   error
+}
+
+// completion is the barrier Await waits on, it loads 1 once the future is
+// completed.
+type completion struct {
+	word atomic.Uint64
+}
+
+func (c *completion) Load() uint64 {
+	if c.word.Load()&statusMask == done {
+		return 1
+	}
+	return 0
+}
+
+// Pool is a bounded set of reusable futures.
+type Pool[T any] struct {
+	free    ring.IQueue[*Future[T]]
+	futures []Future[T]
+}
+
+// New creates a pool of capacity futures, capacity must be a power of two.
+func New[T any](capacity uint64) (*Pool[T], error) {
+	free, err := ring.Queue[*Future[T]](capacity)
+	if err != nil {
+		return nil, err
+	}
+	p := &Pool[T]{free: free, futures: make([]Future[T], capacity)}
+	for i := range p.futures {
+		f := &p.futures[i]
+		f.pool = p
+		f.wait = ring.NewWaitableBarrier(&f.state)
+		free.Enqueue(f)
+	}
+	return p, nil
+}
+
+// Get takes a pending future from the pool together with the id of its
+// generation. It fails with ErrExhausted if every future is in use.
+func (p *Pool[T]) Get() (uint64, *Future[T], error) {
+	f, ok := p.free.Dequeue()
+	if !ok {
+		return 0, nil, ErrExhausted
+	}
+	return f.state.word.Load() >> statusBits, f, nil
+}
+
+// Complete sets the result of generation id and reports false if it was
+// completed before or the future was released since.
+func (f *Future[T]) Complete(id uint64, value T, err error) bool {
+	if !f.state.word.CompareAndSwap(id<<statusBits|pending, id<<statusBits|completing) {
+		return false
+	}
+	f.value, f.err = value, err
+	f.state.word.Store(id<<statusBits | done)
+	return true
+}
+
+// Done reports whether the future is completed.
+func (f *Future[T]) Done() bool {
+	return f.state.word.Load()&statusMask == done
+}
+
+// Await waits with the readers' backoff of the ring until the future is
+// completed or ctx is done.
+func (f *Future[T]) Await(ctx context.Context) (T, error) {
+	if _, err := f.wait.WaitFor(ctx, 1); err != nil {
+		var zero T
+		return zero, err
+	}
+	return f.value, f.err
+}
+
+// Release resets generation id of the future and returns it to its pool. It
+// reports false if generation id was released before.
+func (f *Future[T]) Release(id uint64) bool {
+	for {
+		state := f.state.word.Load()
+		if state>>statusBits != id {
+			return false
+		}
+		// A Complete in progress finishes writing the value first
+		if state&statusMask == completing {
+			runtime.Gosched()
+			continue
+		}
+		if f.state.word.CompareAndSwap(state, (id+1)<<statusBits|pending) {
+			break
+		}
+	}
+	var zero T
+	f.value, f.err = zero, nil
+	f.pool.free.Enqueue(f)
+	return true
+}
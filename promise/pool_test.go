@@ -0,0 +1,101 @@
+package promise
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPool_CompleteAndAwait(t *testing.T) {
+	p, err := New[int](4)
+	if err != nil {
+		t.Fatalf("Failed to create pool: %v", err)
+	}
+	id, f, err := p.Get()
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	go func() {
+		time.Sleep(time.Millisecond)
+		f.Complete(id, 42, nil)
+	}()
+	v, err := f.Await(context.Background())
+	if err != nil || v != 42 {
+		t.Fatalf("Expected 42, got %d: %v", v, err)
+	}
+	if f.Complete(id, 43, nil) {
+		t.Errorf("Expected a second Complete to fail")
+	}
+	f.Release(id)
+	if f.Done() {
+		t.Errorf("Expected a released future to be pending")
+	}
+}
+
+func TestPool_Exhausted(t *testing.T) {
+	p, err := New[int](2)
+	if err != nil {
+		t.Fatalf("Failed to create pool: %v", err)
+	}
+	id, a, _ := p.Get()
+	if _, _, err := p.Get(); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if _, _, err := p.Get(); !errors.Is(err, ErrExhausted) {
+		t.Fatalf("Expected ErrExhausted, got %v", err)
+	}
+	a.Release(id)
+	if _, _, err := p.Get(); err != nil {
+		t.Errorf("Expected a released future to be reusable, got %v", err)
+	}
+}
+
+func TestFuture_Generations(t *testing.T) {
+	p, _ := New[int](1)
+	stale, f, _ := p.Get()
+	if !f.Release(stale) {
+		t.Fatal("Expected Release to succeed")
+	}
+	if f.Release(stale) {
+		t.Error("Expected a second Release to fail")
+	}
+	if _, _, err := p.Get(); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if _, _, err := p.Get(); !errors.Is(err, ErrExhausted) {
+		t.Errorf("Expected the future to be pooled once, got %v", err)
+	}
+
+	// A late reply to the timed-out first caller does not reach the second
+	if f.Complete(stale, 1, nil) {
+		t.Error("Expected Complete of a released generation to fail")
+	}
+	if f.Done() {
+		t.Error("Expected the recycled future to stay pending")
+	}
+}
+
+func TestFuture_AwaitContext(t *testing.T) {
+	p, _ := New[int](1)
+	_, f, _ := p.Get()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	if _, err := f.Await(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestPool_ZeroAlloc(t *testing.T) {
+	p, _ := New[int](8)
+	ctx := context.Background()
+	allocs := testing.AllocsPerRun(1000, func() {
+		id, f, _ := p.Get()
+		f.Complete(id, 1, nil)
+		_, _ = f.Await(ctx)
+		f.Release(id)
+	})
+	if allocs != 0 {
+		t.Errorf("Expected no allocations, got %v", allocs)
+	}
+}
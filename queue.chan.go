@@ -0,0 +1,63 @@
+package ring
+
+import "context"
+
+// AsChan starts a goroutine draining q into the returned unbuffered channel
+// until ctx is done, then closes it. The pump holds at most one item while
+// the consumer is not receiving, the queue fills up behind it and producers
+// see the usual backpressure. An item held when ctx is done is lost.
+func AsChan[T any](ctx context.Context, q IQueue[T]) <-chan T {
+	ch := make(chan T)
+	go func() {
+		defer close(ch)
+		var attempt uint64
+		for ctx.Err() == nil {
+			v, ok := q.Dequeue()
+			if !ok {
+				defaultIdle.park(attempt, ctx.Done())
+				attempt++
+				continue
+			}
+			attempt = 0
+			select {
+			case ch <- v:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+// FromChan enqueues the values received from ch into q until ch is closed or
+// ctx is done. While q is full it holds the received value and stops
+// receiving, so senders on ch block as they would on a full channel. It returns ctx.Err() or the enqueue error
+// that stopped it, nil once ch is closed.
+func FromChan[T any](ctx context.Context, ch <-chan T, q IQueue[T]) error {
+	for {
+		select {
+		case v, ok := <-ch:
+			if !ok {
+				return nil
+			}
+			if err := enqueueWait(ctx, q, v); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// enqueueWait enqueues v, waiting while q is full until ctx is done.
+func enqueueWait[T any](ctx context.Context, q IQueue[T], v T) error {
+	var attempt uint64
+	for !q.Enqueue(v) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		defaultIdle.park(attempt, ctx.Done())
+		attempt++
+	}
+	return nil
+}
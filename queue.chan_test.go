@@ -0,0 +1,84 @@
+package ring
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestQueue_AsChan(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	q, err := Queue[int](4)
+	if err != nil {
+		t.Fatalf("Failed to create queue: %v", err)
+	}
+	ch := AsChan(ctx, q)
+	go func() {
+		for i := 0; i < 100; i++ {
+			if err := q.MustEnqueue(i); err != nil {
+				t.Errorf("MustEnqueue failed: %v", err)
+				return
+			}
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		if v := <-ch; v != i {
+			t.Fatalf("Expected %d, got %d", i, v)
+		}
+	}
+
+	cancel()
+	select {
+	case _, ok := <-ch:
+		if ok {
+			t.Error("Expected no value after cancel")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the channel closed after cancel")
+	}
+}
+
+func TestFromChan(t *testing.T) {
+	q, err := Queue[int](4)
+	if err != nil {
+		t.Fatalf("Failed to create queue: %v", err)
+	}
+	ch := make(chan int)
+	done := make(chan error, 1)
+	go func() { done <- FromChan(context.Background(), ch, q) }()
+
+	// A full queue blocks the sender once FromChan holds an item
+	for i := 0; i < 5; i++ {
+		ch <- i
+	}
+	select {
+	case ch <- 5:
+		t.Fatal("Expected the sender to block on a full queue")
+	case <-time.After(10 * time.Millisecond):
+	}
+	// Every dequeue makes room for one more value
+	for i := 0; i < 2; i++ {
+		if v, _ := q.Dequeue(); v != i {
+			t.Fatalf("Expected %d, got %d", i, v)
+		}
+		if i == 0 {
+			ch <- 5
+			close(ch)
+		}
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("FromChan failed: %v", err)
+	}
+	for i := 2; i < 6; i++ {
+		if v, ok := q.Dequeue(); !ok || v != i {
+			t.Fatalf("Expected %d, got %d", i, v)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := FromChan(ctx, make(chan int), q); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,97 @@
+package ring
+
+import "fmt"
+
+// QueueSnapshot is a copy of the items in a queue, taken for debugging.
+type QueueSnapshot[T any] struct {
+	// Enqueued and Dequeued count the items that passed the queue's head and
+	// tail when the items were copied.
+	Enqueued uint64
+	Dequeued uint64
+	// Items are the items not dequeued yet, oldest first.
+	Items []T
+}
+
+// Snapshot copies the items between the tail and the head into a fresh slice.
+// It is advisory: producers and consumers keep running and the result is
+// stale by the time it returns. The tail is held like a consumer's claim while
+// the items are copied, so consumers wait briefly and the copies are never
+// torn. Items referencing shared memory show its current state.
+func (q *queue[T]) Snapshot() QueueSnapshot[T] {
+	var tail uint64
+	for attempt := uint64(0); ; attempt++ {
+		if tail = q.tail.Load(); tail&1 == 0 && q.tail.CompareAndSwap(tail, tail+1) {
+			break
+		}
+		readerYield(attempt)
+	}
+	defer q.tail.Store(tail)
+	// A claim in progress writes the slot at head, it is left out
+	head := q.head.Load() &^ 1
+	res := QueueSnapshot[T]{
+		Enqueued: head >> 1,
+		Dequeued: tail >> 1,
+		Items:    make([]T, 0, (head-tail)>>1),
+	}
+	for pos := tail; pos < head; pos += 2 {
+		res.Items = append(res.Items, *q.slot(pos))
+	}
+	return res
+}
+
+// String describes the queue's cursors for logs, e.g.
+// `queue: enqueued=10 dequeued=4 capacity=8`.
+func (q *queue[T]) String() string {
+	st := q.Stats()
+	return fmt.Sprintf("queue: enqueued=%d dequeued=%d capacity=%d", st.Enqueued, st.Dequeued, q.cap)
+}
+
+// Snapshot is Snapshot of the queue. Items claimed by a producer but not
+// committed yet are left out.
+func (q *slotQueue[T]) Snapshot() QueueSnapshot[T] {
+	var tail uint64
+	for attempt := uint64(0); ; attempt++ {
+		if tail = q.tail.Load(); tail&cursorLocked == 0 && q.tail.CompareAndSwap(tail, tail|cursorLocked) {
+			break
+		}
+		readerYield(attempt)
+	}
+	defer q.tail.Store(tail)
+	head := q.head.Load()
+	res := QueueSnapshot[T]{
+		Enqueued: head,
+		Dequeued: tail,
+		Items:    make([]T, 0, head-tail),
+	}
+	for pos := tail; pos < head; pos++ {
+		i := pos & q.capMask
+		if q.seqs[i].Load() == pos+1 {
+			res.Items = append(res.Items, q.buffer[i<<q.slotShift])
+		}
+	}
+	return res
+}
+
+func (q *slotQueue[T]) String() string {
+	st := q.Stats()
+	return fmt.Sprintf("queue: enqueued=%d dequeued=%d capacity=%d", st.Enqueued, st.Dequeued, q.cap)
+}
+
+// Snapshot appends the snapshots of the shards in shard order, shards replaced
+// by a resize first. Items keep their order within a shard only.
+func (s *shardedQueue[T]) Snapshot() QueueSnapshot[T] {
+	var res QueueSnapshot[T]
+	for _, q := range s.set.Load().all() {
+		snap := q.Snapshot()
+		res.Enqueued += snap.Enqueued
+		res.Dequeued += snap.Dequeued
+		res.Items = append(res.Items, snap.Items...)
+	}
+	return res
+}
+
+func (s *shardedQueue[T]) String() string {
+	st := s.Stats()
+	n := s.Shards()
+	return fmt.Sprintf("sharded queue: shards=%d enqueued=%d dequeued=%d capacity=%d", n, st.Enqueued, st.Dequeued, uint64(n)*s.capacity)
+}
@@ -0,0 +1,100 @@
+package ring
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestQueue_Snapshot(t *testing.T) {
+	for _, slots := range []bool{false, true} {
+		t.Run(fmt.Sprintf("SlotSequences: %v", slots), func(t *testing.T) {
+			var opts []QueueOption
+			if slots {
+				opts = append(opts, WithQueueSlotSequences())
+			}
+			q, err := Queue[int](8, opts...)
+			if err != nil {
+				t.Fatalf("Failed to create queue: %v", err)
+			}
+			for i := 0; i < 5; i++ {
+				q.Enqueue(i)
+			}
+			q.Dequeue()
+			snap := q.Snapshot()
+			if snap.Enqueued != 5 || snap.Dequeued != 1 || len(snap.Items) != 4 || snap.Items[0] != 1 {
+				t.Errorf("Unexpected snapshot %+v", snap)
+			}
+			if v, ok := q.Dequeue(); !ok || v != 1 {
+				t.Errorf("Expected the snapshot to leave the items queued, got %d (ok=%v)", v, ok)
+			}
+			if got := fmt.Sprint(q); got != "queue: enqueued=5 dequeued=2 capacity=8" {
+				t.Errorf("Unexpected String %q", got)
+			}
+		})
+	}
+}
+
+func TestQueue_SnapshotConcurrent(t *testing.T) {
+	for _, slots := range []bool{false, true} {
+		t.Run(fmt.Sprintf("SlotSequences: %v", slots), func(t *testing.T) {
+			var opts []QueueOption
+			if slots {
+				opts = append(opts, WithQueueSlotSequences())
+			}
+			q, err := Queue[int](16, opts...)
+			if err != nil {
+				t.Fatalf("Failed to create queue: %v", err)
+			}
+			const items = 20000
+			var wg sync.WaitGroup
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				for i := 0; i < items; i++ {
+					q.MustEnqueue(i)
+				}
+			}()
+			go func() {
+				defer wg.Done()
+				for n := 0; n < items; {
+					if _, ok := q.Dequeue(); ok {
+						n++
+					} else {
+						runtime.Gosched()
+					}
+				}
+			}()
+			for done := false; !done; time.Sleep(10 * time.Microsecond) {
+				snap := q.Snapshot()
+				for k, v := range snap.Items {
+					// Items being written are left out, the rest keep their positions
+					if k > 0 && v <= snap.Items[k-1] || uint64(v) < snap.Dequeued {
+						t.Fatalf("Unexpected items %v after %d dequeued", snap.Items, snap.Dequeued)
+					}
+				}
+				done = snap.Dequeued == items
+			}
+			wg.Wait()
+		})
+	}
+}
+
+func TestShardedQueue_Snapshot(t *testing.T) {
+	q, err := ShardedQueue[int](2, 4, RoundRobin[int]())
+	if err != nil {
+		t.Fatalf("Failed to create sharded queue: %v", err)
+	}
+	for i := 0; i < 4; i++ {
+		q.Enqueue(i)
+	}
+	snap := q.Snapshot()
+	if snap.Enqueued != 4 || len(snap.Items) != 4 || snap.Items[0] != 0 || snap.Items[1] != 2 {
+		t.Errorf("Expected the shards in order, got %+v", snap)
+	}
+	if got := fmt.Sprint(q); got != "sharded queue: shards=2 enqueued=4 dequeued=0 capacity=8" {
+		t.Errorf("Unexpected String %q", got)
+	}
+}
@@ -1,125 +1,327 @@
 package ring
 
 import (
+	"context"
 	"fmt"
+	"github.com/dk-open/ring/latency"
 	"github.com/dk-open/ring/pad"
+	"iter"
 	"runtime"
-	"time"
+	"sync/atomic"
+	"unsafe"
 )
 
 type IQueue[T any] interface {
 	MustEnqueue(item T) error
 	Enqueue(v T) bool
+	// TryEnqueue makes a single attempt like Enqueue and tells why it failed.
+	TryEnqueue(item T) Result
 	Dequeue() (res T, ok bool)
+	// TryDequeue makes a single attempt to take an item. Unlike Dequeue it
+	// does not wait for a writer between its claim and its commit.
+	TryDequeue() (res T, result Result)
+	Stats() QueueStats
+	// Drain yields the items as they are dequeued until ctx is done, so
+	// consumers can range over the queue.
+	Drain(ctx context.Context) iter.Seq[T]
+	// Snapshot copies the items not dequeued yet, for debugging.
+	Snapshot() QueueSnapshot[T]
+	// Reset empties the queue for reuse. The caller must make sure no other
+	// goroutine uses the queue meanwhile, detected misuse returns ErrBusy.
+	Reset() error
+	// Clear is Reset that also zeroes the slots.
+	Clear() error
+	// WaitEmpty blocks until the items enqueued before the call have been
+	// dequeued, or ctx is done.
+	WaitEmpty(ctx context.Context) error
 }
 
 var (
-	ErrCapacity = fmt.Errorf("capacity must be a power of two")
+	ErrCapacity = fmt.Errorf("capacity must be a power of two no larger than MaxCapacity")
+	ErrClosed   = fmt.Errorf("ring is closed")
+	ErrDropped  = fmt.Errorf("event dropped by the slow-consumer policy")
+
+	ErrRateLimited = fmt.Errorf("event rejected by the rate limit")
+
+	ErrSequenceExhausted = fmt.Errorf("disruptor sequences exhausted")
+
+	// ErrBusy is returned by Reset when another goroutine is using the queue.
+	ErrBusy = fmt.Errorf("queue is in use")
+
+	// ErrTooManyAttempts matches the error of a MustEnqueue that gave up once
+	// its retry limit was reached.
+	ErrTooManyAttempts = fmt.Errorf("enqueue failed after too many attempts")
+	// ErrFull additionally matches it if the ring was full on the last attempt.
+	ErrFull = fmt.Errorf("ring is full")
 )
 
 type queue[T any] struct {
 	buffer     []T
+	slotShift  uint
 	cap        uint64
 	capMask    uint64
 	capX2      uint64
 	head, tail pad.AtomicUint64
+	// cachedTail is a tail producers saw, it lags behind the real one
+	cachedTail pad.AtomicUint64
+
+	failedEnqueues atomic.Uint64
+	backoffSleeps  atomic.Uint64
+	contention     atomic.Uint64
+	watermarks     *watermarks
+	retry          retryPolicy
+	ctx            context.Context
+	dropHandler    func(item T)
+	// stamps and latency are nil unless WithQueueLatencyTracking
+	stamps  *stamps
+	latency *latency.Histogram
 }
 
-func Queue[T any](capacity uint64) (IQueue[T], error) {
-	if capacity <= 0 || capacity&(capacity-1) != 0 {
-		return nil, ErrCapacity
+func Queue[T any](capacity uint64, opts ...QueueOption) (IQueue[T], error) {
+	if err := validCapacity(capacity); err != nil {
+		return nil, err
+	}
+	var o queueOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	dropHandler, err := typedHandler[T](o.dropHandler)
+	if err != nil {
+		return nil, err
+	}
+	if o.slotSequences {
+		res, err := newSlotQueue[T](capacity, o)
+		if err != nil {
+			return nil, err
+		}
+		res.dropHandler = dropHandler
+		return res, nil
+	}
+	shift := slotShift[T](o.slotPadding)
+	buffer, err := newSlots[T](o.buffer, capacity, shift)
+	if err != nil {
+		return nil, err
+	}
+	res := &queue[T]{
+		buffer:      buffer,
+		slotShift:   shift,
+		capMask:     capacity - 1,
+		cap:         capacity,
+		capX2:       capacity*2 - 1,
+		retry:       o.retry,
+		ctx:         o.ctx,
+		dropHandler: dropHandler,
+	}
+	if w := o.watermarks; w != nil {
+		marks, err := newWatermarks(w.high, w.low, w.fn, capacity)
+		if err != nil {
+			return nil, err
+		}
+		res.watermarks = marks
+	}
+	if o.latency {
+		res.stamps, res.latency = newStamps(capacity), &latency.Histogram{}
+	}
+	return res, nil
+}
+
+// WithQueueDropHandler is WithDropHandler for queues.
+func WithQueueDropHandler[T any](f func(item T)) QueueOption {
+	return func(o *queueOptions) {
+		o.dropHandler = f
 	}
-	return &queue[T]{
-		buffer:  make([]T, capacity),
-		capMask: capacity - 1,
-		cap:     capacity,
-		capX2:   capacity*2 - 1,
-	}, nil
 }
 
 func (q *queue[T]) Enqueue(item T) bool {
+	return q.TryEnqueue(item) == Ok
+}
+
+func (q *queue[T]) TryEnqueue(item T) Result {
+	raceRead(unsafe.Pointer(q))
 	head := q.head.Load()
-	if head-q.tail.Load() >= q.capX2 {
-		return false
+	if q.full(head) {
+		q.failedEnqueues.Add(1)
+		if q.dropHandler != nil {
+			q.dropHandler(item)
+		}
+		return Full
 	}
 
+	// An odd head means another producer is between its CAS and its commit
 	nextHead := head + 1
-	if q.head.CompareAndSwap(head, nextHead) {
-		q.buffer[head>>1&q.capMask] = item
+	if head&1 == 0 && q.head.CompareAndSwap(head, nextHead) {
+		*q.slot(head) = item
+		if q.stamps != nil {
+			q.stamps.publish(head>>1, 1)
+		}
 		q.head.Store(nextHead + 1)
-		return true
+		q.occupancy()
+		return Ok
 	}
 
-	return false
+	q.contention.Add(1)
+	q.failedEnqueues.Add(1)
+	return Contended
 }
 
 func (q *queue[T]) MustEnqueue(item T) error {
-	attempt := 0
+	raceRead(unsafe.Pointer(q))
+	r := retry{policy: q.retry, ctx: q.ctx}
 	for {
 		head := q.head.Load()
-		if head-q.tail.Load() >= q.capX2 {
-			attempt++
-			if err := enqueueBackoff(attempt); err != nil {
-				return fmt.Errorf("enqueue failed after %d attempts: %w", attempt, err)
+		if q.full(head) {
+			if err := q.backoff(&r, true); err != nil {
+				q.failedEnqueues.Add(1)
+				return err
 			}
 			continue
 		}
 
 		nextHead := head + 1
-		if q.head.CompareAndSwap(head, nextHead) {
-			q.buffer[head>>1&q.capMask] = item
+		if head&1 == 0 && q.head.CompareAndSwap(head, nextHead) {
+			*q.slot(head) = item
+			if q.stamps != nil {
+				q.stamps.publish(head>>1, 1)
+			}
 			q.head.Store(nextHead + 1)
+			q.occupancy()
 			return nil
 		}
-		attempt++
-		if err := enqueueBackoff(attempt); err != nil {
-			return fmt.Errorf("enqueue failed after %d attempts: %w", attempt, err)
+		q.contention.Add(1)
+		if err := q.backoff(&r, false); err != nil {
+			q.failedEnqueues.Add(1)
+			return err
 		}
 		continue
 	}
 }
 
+// Dequeue takes the next item. It waits while the next item is still being
+// written, which lasts as long as its writer is descheduled; TryDequeue
+// returns instead.
 func (q *queue[T]) Dequeue() (res T, ok bool) {
-	//attempt := 0
 	for {
-		tail := q.tail.Load()
-		head := q.head.Load()
-		if tail == head {
-			return
-		}
-		if tail&1 == 1 || head-tail < 2 {
-			runtime.Gosched()
-			continue
-		}
-
-		nextTail := tail + 1
-		if q.tail.CompareAndSwap(tail, nextTail) {
-			res = q.buffer[tail>>1&q.capMask]
-			q.tail.Store(nextTail + 1)
+		res, result := q.TryDequeue()
+		switch result {
+		case Ok:
 			return res, true
+		case Empty:
+			return res, false
 		}
 		runtime.Gosched()
 	}
 }
 
-func enqueueBackoff(attempt int) error {
-	switch {
-	case attempt < 5:
-		// On modern CPUs, can hint with a PAUSE (Go does not expose directly)
-		// Just an empty loop does nothing, but you could do:
-		// runtime_procPin()... // not exposed
-		// For real, just do nothing
-	case attempt < 20:
-		runtime.Gosched() // Let Go scheduler run another goroutine
-	case attempt < 10000:
-		// Exponential backoff, up to a max
-		d := time.Microsecond << uint(attempt-20)
-		if d > 5*time.Millisecond {
-			d = 5 * time.Millisecond
+func (q *queue[T]) TryDequeue() (res T, result Result) {
+	raceRead(unsafe.Pointer(q))
+	tail := q.tail.Load()
+	head := q.head.Load()
+	if tail == head {
+		return res, Empty
+	}
+	if tail&1 == 1 || head-tail < 2 {
+		return res, Contended
+	}
+
+	nextTail := tail + 1
+	if !q.tail.CompareAndSwap(tail, nextTail) {
+		return res, Contended
+	}
+	res = *q.slot(tail)
+	if q.stamps == nil {
+		q.tail.Store(nextTail + 1)
+		q.occupancy()
+		return res, Ok
+	}
+	published := q.stamps.published(tail >> 1)
+	q.tail.Store(nextTail + 1)
+	q.occupancy()
+	q.stamps.record(q.latency, published)
+	return res, Ok
+}
+
+func (q *queue[T]) WaitEmpty(ctx context.Context) error {
+	// A claim in progress commits at the next even head
+	head := (q.head.Load() + 1) &^ 1
+	return pollUntil(ctx, func() bool {
+		return q.tail.Load() >= head
+	})
+}
+
+// full reports whether head would overwrite an item not taken yet. The tail
+// only moves forward, so producers check the cached tail first and load the
+// real one only once the cached tail says the queue is full.
+func (q *queue[T]) full(head uint64) bool {
+	if head-q.cachedTail.Load() < q.capX2 {
+		return false
+	}
+	// A claimed tail counts as not taken yet, so the cached tail never runs
+	// ahead of a tail a snapshot hands back
+	tail := q.tail.Load() &^ 1
+	q.cachedTail.Store(tail)
+	return head-tail >= q.capX2
+}
+
+// backoff waits before the next enqueue attempt and counts the sleeps.
+func (q *queue[T]) backoff(r *retry, full bool) error {
+	if r.attempt+1 >= backoffSleepAttempt {
+		q.backoffSleeps.Add(1)
+	}
+	return r.backoff(full)
+}
+
+// occupancy checks the watermarks against the committed queue length.
+func (q *queue[T]) occupancy() {
+	if q.watermarks == nil {
+		return
+	}
+	tail := q.tail.Load() &^ 1
+	if head := q.head.Load() &^ 1; tail < head {
+		q.watermarks.check((head - tail) >> 1)
+		return
+	}
+	q.watermarks.check(0)
+}
+
+// pollUntil polls done with the readers' idle backoff until it holds or ctx
+// is done.
+func pollUntil(ctx context.Context, done func() bool) error {
+	for attempt := uint64(0); !done(); attempt++ {
+		if err := ctx.Err(); err != nil {
+			return err
 		}
-		time.Sleep(d)
-	default:
-		return fmt.Errorf("enqueue failed after %d attempts", attempt)
+		readerYield(attempt)
 	}
 	return nil
 }
+
+// attemptsError is the error of a MustEnqueue that gave up. Its values are
+// predeclared, a producer failing under pressure does not allocate.
+type attemptsError struct {
+	full bool
+}
+
+var (
+	errGaveUp     = &attemptsError{}
+	errGaveUpFull = &attemptsError{full: true}
+)
+
+// gaveUp returns the error of a MustEnqueue that gave up, full if the ring
+// was full on its last attempt.
+func gaveUp(full bool) error {
+	if full {
+		return errGaveUpFull
+	}
+	return errGaveUp
+}
+
+func (e *attemptsError) Error() string {
+	if e.full {
+		return "enqueue failed after too many attempts: ring is full"
+	}
+	return ErrTooManyAttempts.Error()
+}
+
+func (e *attemptsError) Is(target error) bool {
+	return target == ErrTooManyAttempts || e.full && target == ErrFull
+}
@@ -0,0 +1,43 @@
+package ring
+
+import (
+	"context"
+	"iter"
+)
+
+func (q *queue[T]) Drain(ctx context.Context) iter.Seq[T] {
+	return drainSeq(ctx, q.Dequeue)
+}
+
+func (q *slotQueue[T]) Drain(ctx context.Context) iter.Seq[T] {
+	return drainSeq(ctx, q.Dequeue)
+}
+
+func (s *shardedQueue[T]) Drain(ctx context.Context) iter.Seq[T] {
+	return drainSeq(ctx, s.Dequeue)
+}
+
+func (p *priorityQueue[T]) Drain(ctx context.Context) iter.Seq[T] {
+	return drainSeq(ctx, p.Dequeue)
+}
+
+// drainSeq yields the items dequeue takes, waiting with the readers' idle
+// backoff while there are none, until ctx is done or the loop stops. Unlike
+// AsChan it needs no goroutine, an item is never held back when ctx is done.
+func drainSeq[T any](ctx context.Context, dequeue func() (T, bool)) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		var attempt uint64
+		for ctx.Err() == nil {
+			v, ok := dequeue()
+			if !ok {
+				defaultIdle.park(attempt, ctx.Done())
+				attempt++
+				continue
+			}
+			attempt = 0
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
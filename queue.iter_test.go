@@ -0,0 +1,65 @@
+package ring
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestQueue_Drain(t *testing.T) {
+	q, err := Queue[int](8)
+	if err != nil {
+		t.Fatalf("Failed to create queue: %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		q.Enqueue(i)
+	}
+	var got []int
+	for v := range q.Drain(context.Background()) {
+		got = append(got, v)
+		if v == 2 {
+			break
+		}
+	}
+	if len(got) != 3 {
+		t.Fatalf("Expected the loop to stop after 3 items, got %v", got)
+	}
+	if v, ok := q.Dequeue(); !ok || v != 3 {
+		t.Errorf("Expected break to leave the rest queued, got %d (ok=%v)", v, ok)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	got = got[:0]
+	for v := range q.Drain(ctx) {
+		got = append(got, v)
+	}
+	if len(got) != 1 || got[0] != 4 {
+		t.Errorf("Expected the remaining item before ctx is done, got %v", got)
+	}
+}
+
+func TestShardedQueue_Drain(t *testing.T) {
+	q, err := ShardedQueue[int](2, 4, RoundRobin[int](), WithQueueSlotSequences())
+	if err != nil {
+		t.Fatalf("Failed to create sharded queue: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		for i := 0; i < 100; i++ {
+			q.MustEnqueue(i)
+		}
+	}()
+	sum := 0
+	n := 0
+	for v := range q.Drain(ctx) {
+		sum += v
+		if n++; n == 100 {
+			cancel()
+		}
+	}
+	if sum != 4950 {
+		t.Errorf("Expected every item once, got a sum of %d", sum)
+	}
+}
@@ -0,0 +1,179 @@
+package ring
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"sync/atomic"
+)
+
+var ErrPriority = fmt.Errorf("priority lanes need one positive weight per lane")
+
+// lanePicker orders the lanes a consumer of a priority ring tries. Without
+// weights every turn tries the lanes strictly by priority. With weights a turn
+// starts at the lane whose share is due, spread by smooth weighted round
+// robin, and falls back to the other lanes by priority if it is empty.
+type lanePicker struct {
+	turns [][]int
+	next  atomic.Uint64
+}
+
+func newLanePicker(lanes int, weights []int) (*lanePicker, error) {
+	if lanes <= 0 || weights != nil && len(weights) != lanes {
+		return nil, ErrPriority
+	}
+	byPriority := make([]int, lanes)
+	for i := range byPriority {
+		byPriority[i] = i
+	}
+	if weights == nil {
+		return &lanePicker{turns: [][]int{byPriority}}, nil
+	}
+	total := 0
+	for _, w := range weights {
+		if w <= 0 {
+			return nil, ErrPriority
+		}
+		total += w
+	}
+	res := &lanePicker{turns: make([][]int, 0, total)}
+	current := make([]int, lanes)
+	for range total {
+		due := 0
+		for i, w := range weights {
+			if current[i] += w; current[i] > current[due] {
+				due = i
+			}
+		}
+		current[due] -= total
+		turn := append(make([]int, 0, lanes), due)
+		for _, lane := range byPriority {
+			if lane != due {
+				turn = append(turn, lane)
+			}
+		}
+		res.turns = append(res.turns, turn)
+	}
+	return res, nil
+}
+
+// turn returns the order in which the next consumer tries the lanes.
+func (p *lanePicker) turn() []int {
+	if len(p.turns) == 1 {
+		return p.turns[0]
+	}
+	return p.turns[(p.next.Add(1)-1)%uint64(len(p.turns))]
+}
+
+// lane maps prio to a lane, out of range priorities are clamped.
+func lane(prio, lanes int) int {
+	return min(max(prio, 0), lanes-1)
+}
+
+// IPriorityQueue is a queue of priority lanes, lane 0 is the most urgent.
+type IPriorityQueue[T any] interface {
+	Enqueue(item T, prio int) bool
+	TryEnqueue(item T, prio int) Result
+	MustEnqueue(item T, prio int) error
+	// Dequeue takes an item from the lane whose turn it is, see PriorityQueue.
+	Dequeue() (res T, ok bool)
+	TryDequeue() (res T, result Result)
+	// Lane returns the queue of priority prio.
+	Lane(prio int) IQueue[T]
+	// Lanes returns the number of lanes.
+	Lanes() int
+	Stats() QueueStats
+	WaitEmpty(ctx context.Context) error
+	// Drain yields the items in dequeue order until ctx is done.
+	Drain(ctx context.Context) iter.Seq[T]
+	// Reset and Clear empty every lane as IQueue does.
+	Reset() error
+	Clear() error
+}
+
+type priorityQueue[T any] struct {
+	lanes  []IQueue[T]
+	picker *lanePicker
+	cap    uint64
+}
+
+// PriorityQueue creates lanes queues of the given capacity, each configured by
+// opts, so urgent items overtake bulk ones. Items are enqueued to the lane of
+// their priority, 0 being the most urgent, priorities out of range are
+// clamped. With nil weights consumers take from a lane only while every more
+// urgent lane is empty. Otherwise lane i gets weights[i] of every sum(weights)
+// dequeues while it has items, so bulk lanes keep moving under a steady
+// stream of urgent items.
+func PriorityQueue[T any](lanes int, capacity uint64, weights []int, opts ...QueueOption) (IPriorityQueue[T], error) {
+	picker, err := newLanePicker(lanes, weights)
+	if err != nil {
+		return nil, err
+	}
+	res := &priorityQueue[T]{picker: picker, cap: uint64(lanes) * capacity}
+	for range lanes {
+		q, err := Queue[T](capacity, opts...)
+		if err != nil {
+			return nil, err
+		}
+		res.lanes = append(res.lanes, q)
+	}
+	return res, nil
+}
+
+func (p *priorityQueue[T]) Enqueue(item T, prio int) bool {
+	return p.lanes[lane(prio, len(p.lanes))].Enqueue(item)
+}
+
+func (p *priorityQueue[T]) TryEnqueue(item T, prio int) Result {
+	return p.lanes[lane(prio, len(p.lanes))].TryEnqueue(item)
+}
+
+func (p *priorityQueue[T]) MustEnqueue(item T, prio int) error {
+	return p.lanes[lane(prio, len(p.lanes))].MustEnqueue(item)
+}
+
+func (p *priorityQueue[T]) Dequeue() (res T, ok bool) {
+	for _, i := range p.picker.turn() {
+		if res, ok = p.lanes[i].Dequeue(); ok {
+			return res, true
+		}
+	}
+	return res, false
+}
+
+// TryDequeue makes one attempt on every lane in turn order. It reports
+// Contended if a lane was contended and none had an item ready.
+func (p *priorityQueue[T]) TryDequeue() (res T, result Result) {
+	result = Empty
+	for _, i := range p.picker.turn() {
+		v, r := p.lanes[i].TryDequeue()
+		switch r {
+		case Ok:
+			return v, Ok
+		case Contended:
+			result = Contended
+		}
+	}
+	return res, result
+}
+
+func (p *priorityQueue[T]) Lane(prio int) IQueue[T] {
+	return p.lanes[lane(prio, len(p.lanes))]
+}
+
+func (p *priorityQueue[T]) Lanes() int {
+	return len(p.lanes)
+}
+
+func (p *priorityQueue[T]) Stats() QueueStats {
+	return mergeQueueStats(p.lanes, p.cap)
+}
+
+func (p *priorityQueue[T]) WaitEmpty(ctx context.Context) error {
+	for _, q := range p.lanes {
+		if err := q.WaitEmpty(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
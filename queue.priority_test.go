@@ -0,0 +1,104 @@
+package ring
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPriorityQueue_Strict(t *testing.T) {
+	q, err := PriorityQueue[int](3, 8, nil)
+	if err != nil {
+		t.Fatalf("Failed to create priority queue: %v", err)
+	}
+	q.Enqueue(20, 2)
+	q.Enqueue(10, 1)
+	q.Enqueue(0, 0)
+	q.Enqueue(21, 7)
+	q.Enqueue(1, -1)
+	for _, want := range []int{0, 1, 10, 20, 21} {
+		if got, ok := q.Dequeue(); !ok || got != want {
+			t.Fatalf("Expected %d, got %d (ok=%v)", want, got, ok)
+		}
+	}
+	if _, r := q.TryDequeue(); r != Empty {
+		t.Errorf("Expected Empty, got %v", r)
+	}
+	if got := q.Lanes(); got != 3 {
+		t.Errorf("Expected 3 lanes, got %d", got)
+	}
+}
+
+func TestPriorityQueue_Weighted(t *testing.T) {
+	q, err := PriorityQueue[int](2, 64, []int{3, 1})
+	if err != nil {
+		t.Fatalf("Failed to create priority queue: %v", err)
+	}
+	for i := 0; i < 40; i++ {
+		q.Enqueue(0, 0)
+		q.Enqueue(1, 1)
+	}
+	var count [2]int
+	for i := 0; i < 40; i++ {
+		v, ok := q.Dequeue()
+		if !ok {
+			t.Fatalf("Expected an item at %d", i)
+		}
+		count[v]++
+	}
+	if count != [2]int{30, 10} {
+		t.Errorf("Expected a 3:1 split, got %v", count)
+	}
+	// Once the urgent lane is empty the bulk lane gets every turn
+	for i := 0; i < 40; i++ {
+		if _, ok := q.Dequeue(); !ok {
+			t.Fatalf("Expected the remaining items to be dequeued, stopped at %d", i)
+		}
+	}
+	st := q.Stats()
+	if st.Enqueued != 80 || st.Dequeued != 80 || st.Len != 0 {
+		t.Errorf("Unexpected stats %+v", st)
+	}
+}
+
+func TestPriorityQueue_Full(t *testing.T) {
+	q, err := PriorityQueue[int](2, 2, nil, WithQueueMaxAttempts(3))
+	if err != nil {
+		t.Fatalf("Failed to create priority queue: %v", err)
+	}
+	q.Enqueue(1, 1)
+	q.Enqueue(2, 1)
+	if r := q.TryEnqueue(3, 1); r != Full {
+		t.Errorf("Expected the bulk lane to be full, got %v", r)
+	}
+	if err := q.MustEnqueue(0, 0); err != nil {
+		t.Errorf("Expected the urgent lane to accept, got %v", err)
+	}
+	if got := q.Lane(1).Stats().Len; got != 2 {
+		t.Errorf("Expected 2 items in the bulk lane, got %d", got)
+	}
+	for range 3 {
+		q.Dequeue()
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := q.WaitEmpty(ctx); err != nil {
+		t.Errorf("WaitEmpty failed: %v", err)
+	}
+}
+
+func TestPriorityQueue_InvalidWeights(t *testing.T) {
+	for _, tc := range []struct {
+		lanes   int
+		weights []int
+	}{
+		{0, nil},
+		{2, []int{1}},
+		{2, []int{1, 0}},
+	} {
+		if _, err := PriorityQueue[int](tc.lanes, 8, tc.weights); !errors.Is(err, ErrPriority) {
+			t.Errorf("Expected ErrPriority for %d lanes and weights %v, got %v", tc.lanes, tc.weights, err)
+		}
+	}
+}
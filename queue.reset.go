@@ -0,0 +1,141 @@
+package ring
+
+import "unsafe"
+
+// Reset empties the queue for reuse without allocating, the slots keep their
+// stale items. It claims both cursors the way producers and consumers do and
+// reports ErrBusy if one of them is mid-claim. That only catches some misuse:
+// the caller must make sure no other goroutine uses the queue until Reset
+// returns. Under the race detector every enqueue and dequeue counts as a read
+// of the queue and Reset as a write, so concurrent use is reported as a race.
+func (q *queue[T]) Reset() error {
+	return q.reset(false)
+}
+
+// Clear is Reset that also zeroes the slots, so the queue no longer keeps
+// the items it held reachable.
+func (q *queue[T]) Clear() error {
+	return q.reset(true)
+}
+
+func (q *queue[T]) reset(zero bool) error {
+	raceWrite(unsafe.Pointer(q))
+	tail := q.tail.Load()
+	if tail&1 == 1 || !q.tail.CompareAndSwap(tail, tail+1) {
+		return ErrBusy
+	}
+	head := q.head.Load()
+	if head&1 == 1 || !q.head.CompareAndSwap(head, head+1) {
+		q.tail.Store(tail)
+		return ErrBusy
+	}
+	if zero {
+		clear(q.buffer)
+	}
+	q.failedEnqueues.Store(0)
+	q.backoffSleeps.Store(0)
+	q.contention.Store(0)
+	if q.latency != nil {
+		q.latency.Reset()
+	}
+	q.cachedTail.Store(0)
+	q.head.Store(0)
+	q.tail.Store(0)
+	// A saturated queue reports that it drained
+	q.occupancy()
+	return nil
+}
+
+// Reset is Reset of the queue. A slot still being written or copied out also
+// makes it report ErrBusy.
+func (q *slotQueue[T]) Reset() error {
+	return q.reset(false)
+}
+
+func (q *slotQueue[T]) Clear() error {
+	return q.reset(true)
+}
+
+func (q *slotQueue[T]) reset(zero bool) error {
+	raceWrite(unsafe.Pointer(q))
+	tail := q.tail.Load()
+	if tail&cursorLocked != 0 || !q.tail.CompareAndSwap(tail, tail|cursorLocked) {
+		return ErrBusy
+	}
+	head := q.head.Load()
+	if head&cursorLocked != 0 || !q.head.CompareAndSwap(head, head|cursorLocked) {
+		q.tail.Store(tail)
+		return ErrBusy
+	}
+	// Committed items sit below the head, free slots wait for their next lap
+	for pos := tail; pos < tail+q.cap; pos++ {
+		want := pos
+		if pos < head {
+			want = pos + 1
+		}
+		if q.seqs[pos&q.capMask].Load() != want {
+			q.head.Store(head)
+			q.tail.Store(tail)
+			return ErrBusy
+		}
+	}
+	if zero {
+		clear(q.buffer)
+	}
+	for i := range q.seqs {
+		q.seqs[i].Store(uint64(i))
+	}
+	q.failedEnqueues.Store(0)
+	q.backoffSleeps.Store(0)
+	q.contention.Store(0)
+	if q.latency != nil {
+		q.latency.Reset()
+	}
+	q.head.Store(0)
+	q.tail.Store(0)
+	q.occupancy()
+	return nil
+}
+
+// Reset resets the shards in order and stops at the first that is busy,
+// leaving the shards before it reset. Shards replaced by a resize are reset
+// first and dropped once all shards are reset.
+func (s *shardedQueue[T]) Reset() error {
+	return s.reset(IQueue[T].Reset)
+}
+
+func (s *shardedQueue[T]) Clear() error {
+	return s.reset(IQueue[T].Clear)
+}
+
+func (s *shardedQueue[T]) reset(reset func(IQueue[T]) error) error {
+	s.next.Store(0)
+	set := s.set.Load()
+	if err := resetAll(set.all(), reset); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	set.prev.Store(nil)
+	s.retired = QueueStats{}
+	s.enqueued, s.contention = 0, 0
+	return nil
+}
+
+// Reset resets the lanes like the shards of a sharded queue.
+func (p *priorityQueue[T]) Reset() error {
+	return resetAll(p.lanes, IQueue[T].Reset)
+}
+
+func (p *priorityQueue[T]) Clear() error {
+	return resetAll(p.lanes, IQueue[T].Clear)
+}
+
+func resetAll[T any](queues []IQueue[T], reset func(IQueue[T]) error) error {
+	for _, q := range queues {
+		if err := reset(q); err != nil {
+			return err
+		}
+	}
+	return nil
+}
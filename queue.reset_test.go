@@ -0,0 +1,143 @@
+package ring
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestQueue_Reset(t *testing.T) {
+	for _, slots := range []bool{false, true} {
+		t.Run(fmt.Sprintf("SlotSequences: %v", slots), func(t *testing.T) {
+			var pressure []Pressure
+			opts := []QueueOption{WithQueueWatermarks(0.75, 0.25, func(p Pressure) { pressure = append(pressure, p) })}
+			if slots {
+				opts = append(opts, WithQueueSlotSequences())
+			}
+			q, err := Queue[int](4, opts...)
+			if err != nil {
+				t.Fatalf("Failed to create queue: %v", err)
+			}
+			for i := 0; i < 5; i++ {
+				q.Enqueue(i)
+			}
+			q.Dequeue()
+			if err := q.Reset(); err != nil {
+				t.Fatalf("Reset failed: %v", err)
+			}
+			if st := q.Stats(); st.Enqueued != 0 || st.Dequeued != 0 || st.Len != 0 || st.FailedEnqueues != 0 {
+				t.Errorf("Expected reset stats, got %+v", st)
+			}
+			if _, ok := q.Dequeue(); ok {
+				t.Error("Expected an empty queue after Reset")
+			}
+			if len(pressure) != 2 || pressure[1] != Drained {
+				t.Errorf("Expected Reset to report the drained queue, got %v", pressure)
+			}
+			for i := 10; i < 14; i++ {
+				if !q.Enqueue(i) {
+					t.Fatalf("Failed to enqueue %d after Reset", i)
+				}
+			}
+			if v, ok := q.Dequeue(); !ok || v != 10 {
+				t.Errorf("Expected 10 after Reset, got %d (ok=%v)", v, ok)
+			}
+		})
+	}
+}
+
+func TestQueue_Clear(t *testing.T) {
+	q, err := Queue[*int](4)
+	if err != nil {
+		t.Fatalf("Failed to create queue: %v", err)
+	}
+	v := 1
+	q.Enqueue(&v)
+	q.Enqueue(&v)
+	if err := q.Clear(); err != nil {
+		t.Fatalf("Clear failed: %v", err)
+	}
+	for i, p := range q.(*queue[*int]).buffer {
+		if p != nil {
+			t.Fatalf("Expected slot %d to be zeroed", i)
+		}
+	}
+
+	slots, err := Queue[*int](4, WithQueueSlotSequences())
+	if err != nil {
+		t.Fatalf("Failed to create queue: %v", err)
+	}
+	slots.Enqueue(&v)
+	if err := slots.Clear(); err != nil {
+		t.Fatalf("Clear failed: %v", err)
+	}
+	for i, p := range slots.(*slotQueue[*int]).buffer {
+		if p != nil {
+			t.Fatalf("Expected slot %d to be zeroed", i)
+		}
+	}
+}
+
+func TestQueue_ResetBusy(t *testing.T) {
+	q, err := Queue[int](4)
+	if err != nil {
+		t.Fatalf("Failed to create queue: %v", err)
+	}
+	q.Enqueue(1)
+	// Simulate a producer between its claim and its commit
+	raw := q.(*queue[int])
+	raw.head.Add(1)
+	if err := q.Reset(); !errors.Is(err, ErrBusy) {
+		t.Errorf("Expected ErrBusy, got %v", err)
+	}
+	if got := raw.tail.Load(); got != 0 {
+		t.Errorf("Expected the tail to be handed back, got %d", got)
+	}
+	raw.head.Add(1)
+	if err := q.Reset(); err != nil {
+		t.Errorf("Expected Reset to succeed once the claim committed, got %v", err)
+	}
+
+	slots, err := Queue[int](4, WithQueueSlotSequences())
+	if err != nil {
+		t.Fatalf("Failed to create queue: %v", err)
+	}
+	slots.Enqueue(1)
+	// A claimed position whose slot is not committed yet
+	rawSlots := slots.(*slotQueue[int])
+	rawSlots.head.Add(1)
+	if err := slots.Reset(); !errors.Is(err, ErrBusy) {
+		t.Errorf("Expected ErrBusy, got %v", err)
+	}
+	if head, tail := rawSlots.head.Load(), rawSlots.tail.Load(); head != 2 || tail != 0 {
+		t.Errorf("Expected the cursors to be handed back, got head %d tail %d", head, tail)
+	}
+}
+
+func TestShardedQueue_Reset(t *testing.T) {
+	q, err := ShardedQueue[int](2, 4, RoundRobin[int]())
+	if err != nil {
+		t.Fatalf("Failed to create sharded queue: %v", err)
+	}
+	for i := 0; i < 6; i++ {
+		q.Enqueue(i)
+	}
+	if err := q.Reset(); err != nil {
+		t.Fatalf("Reset failed: %v", err)
+	}
+	if st := q.Stats(); st.Len != 0 || st.Enqueued != 0 {
+		t.Errorf("Expected every shard to be reset, got %+v", st)
+	}
+
+	p, err := PriorityQueue[int](2, 4, nil)
+	if err != nil {
+		t.Fatalf("Failed to create priority queue: %v", err)
+	}
+	p.Enqueue(1, 1)
+	if err := p.Clear(); err != nil {
+		t.Fatalf("Clear failed: %v", err)
+	}
+	if _, ok := p.Dequeue(); ok {
+		t.Error("Expected an empty priority queue after Clear")
+	}
+}
@@ -0,0 +1,32 @@
+package ring
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+)
+
+// Select waits until one of qs has an item and dequeues it, returning the
+// index of its queue. Like a select statement it starts at a random queue, so
+// no queue is starved. Waiting backs off like an idle reader, without a
+// goroutine per queue. It returns ctx.Err() once ctx is done.
+func Select[T any](ctx context.Context, qs ...IQueue[T]) (index int, value T, err error) {
+	if len(qs) == 0 {
+		return -1, value, fmt.Errorf("select needs at least one queue")
+	}
+	var attempt uint64
+	for {
+		start := rand.IntN(len(qs))
+		for i := range qs {
+			index = (start + i) % len(qs)
+			if v, ok := qs[index].Dequeue(); ok {
+				return index, v, nil
+			}
+		}
+		if err = ctx.Err(); err != nil {
+			return -1, value, err
+		}
+		defaultIdle.park(attempt, ctx.Done())
+		attempt++
+	}
+}
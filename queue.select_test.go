@@ -0,0 +1,50 @@
+package ring
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSelect(t *testing.T) {
+	qs := make([]IQueue[int], 3)
+	for i := range qs {
+		q, err := Queue[int](4)
+		if err != nil {
+			t.Fatalf("Failed to create queue: %v", err)
+		}
+		qs[i] = q
+	}
+
+	go func() {
+		time.Sleep(5 * time.Millisecond)
+		qs[2].Enqueue(42)
+	}()
+	index, v, err := Select(context.Background(), qs...)
+	if err != nil || index != 2 || v != 42 {
+		t.Fatalf("Expected 42 from queue 2, got %d from %d: %v", v, index, err)
+	}
+
+	// Every ready queue gets picked
+	seen := map[int]int{}
+	for i := 0; i < 300; i++ {
+		qs[0].Enqueue(0)
+		qs[1].Enqueue(1)
+		index, _, _ := Select(context.Background(), qs...)
+		seen[index]++
+		for _, q := range qs {
+			for _, ok := q.Dequeue(); ok; _, ok = q.Dequeue() {
+			}
+		}
+	}
+	if seen[0] == 0 || seen[1] == 0 {
+		t.Errorf("Expected both ready queues selected, got %v", seen)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, _, err := Select(ctx, qs...); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got %v", err)
+	}
+}
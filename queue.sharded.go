@@ -0,0 +1,382 @@
+package ring
+
+import (
+	"context"
+	"fmt"
+	"github.com/dk-open/ring/latency"
+	"github.com/dk-open/ring/pad"
+	"hash/maphash"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Routing picks the shard of n an item is enqueued to.
+type Routing[T any] func(item T, n int) int
+
+// RoundRobin spreads items evenly over the shards.
+func RoundRobin[T any]() Routing[T] {
+	var next atomic.Uint64
+	return func(item T, n int) int {
+		return int((next.Add(1) - 1) % uint64(n))
+	}
+}
+
+// ByKey routes items with equal keys to the same shard, where they keep their
+// enqueue order.
+func ByKey[T any, K comparable](key func(item T) K) Routing[T] {
+	seed := maphash.MakeSeed()
+	return func(item T, n int) int {
+		return int(maphash.Comparable(seed, key(item)) % uint64(n))
+	}
+}
+
+// IShardedQueue is a queue spread over independent shards. Dequeue services
+// the shards in turn, Shard gives a consumer a shard of its own.
+type IShardedQueue[T any] interface {
+	IQueue[T]
+	// Shard returns the queue of shard i.
+	Shard(i int) IQueue[T]
+	// Shards returns the number of shards.
+	Shards() int
+}
+
+// Contention is measured over windows of shardScaleInterval. A window in
+// which more than 1/shardGrowRatio of the enqueues lost a CAS doubles the
+// shards, one below 1/shardShrinkRatio halves them.
+const (
+	shardScaleInterval = 100 * time.Millisecond
+	shardScaleEvery    = 256
+	shardGrowRatio     = 8
+	shardShrinkRatio   = 128
+)
+
+type shardScaling struct {
+	min, max int
+}
+
+// WithAdaptiveShards lets a sharded queue grow and shrink its shards between
+// min and max with the contention its producers measure, see
+// QueueStats.Contention. Other queues ignore it.
+//
+// A resize replaces the shard set: producers move to the new shards at once,
+// Dequeue takes the items left in the old shards first, so items of a key
+// keep their order, and serves the new shards once the old ones are empty
+// and no producer is still enqueuing to them. Resizes are decided by Dequeue, a
+// consumer that only uses Shard sees a shard of the set current at the call.
+func WithAdaptiveShards(min, max int) QueueOption {
+	return func(o *queueOptions) {
+		o.shardScaling = &shardScaling{min: min, max: max}
+	}
+}
+
+// shardSet is the shards of a sharded queue. With adaptive shards producers
+// count themselves in the shard they enqueue to, so a replaced set is only
+// dropped once no producer that saw it is left.
+type shardSet[T any] struct {
+	shards    []IQueue[T]
+	producers []pad.AtomicInt64
+	// prev is the set this one replaced while it still holds items
+	prev atomic.Pointer[shardSet[T]]
+}
+
+// idle reports whether no producer is enqueuing to the set.
+func (s *shardSet[T]) idle() bool {
+	for i := range s.producers {
+		if s.producers[i].Load() != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// all returns the shards of the set after those of the set it replaced.
+func (s *shardSet[T]) all() []IQueue[T] {
+	if prev := s.prev.Load(); prev != nil {
+		return append(prev.shards[:len(prev.shards):len(prev.shards)], s.shards...)
+	}
+	return s.shards
+}
+
+type shardedQueue[T any] struct {
+	set      atomic.Pointer[shardSet[T]]
+	routing  Routing[T]
+	next     atomic.Uint64
+	capacity uint64
+	opts     []QueueOption
+	scaling  *shardScaling
+
+	// scaled is when contention was last evaluated, in nanoseconds since
+	// start. mu serializes resizes and guards the fields below it.
+	start  time.Time
+	scaled atomic.Int64
+	mu     sync.Mutex
+	// stats of the sets dropped after a resize
+	retired QueueStats
+	// enqueues and contention of the set at the last evaluation
+	enqueued, contention uint64
+}
+
+// ShardedQueue creates shards queues of the given capacity, each configured by
+// opts. Producers contend only within a shard. An item whose shard is full is
+// not moved to another shard.
+func ShardedQueue[T any](shards int, capacity uint64, routing Routing[T], opts ...QueueOption) (IShardedQueue[T], error) {
+	if shards <= 0 {
+		return nil, fmt.Errorf("shards must be positive, got %d", shards)
+	}
+	var o queueOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if c := o.shardScaling; c != nil && !(0 < c.min && c.min <= shards && shards <= c.max) {
+		return nil, fmt.Errorf("adaptive shards must satisfy 0 < min <= shards <= max, got %d <= %d <= %d", c.min, shards, c.max)
+	}
+	res := &shardedQueue[T]{routing: routing, capacity: capacity, opts: opts, scaling: o.shardScaling, start: time.Now()}
+	set, err := res.newSet(shards)
+	if err != nil {
+		return nil, err
+	}
+	res.set.Store(set)
+	return res, nil
+}
+
+func (s *shardedQueue[T]) newSet(shards int) (*shardSet[T], error) {
+	res := &shardSet[T]{shards: make([]IQueue[T], 0, shards)}
+	for i := 0; i < shards; i++ {
+		q, err := Queue[T](s.capacity, s.opts...)
+		if err != nil {
+			return nil, err
+		}
+		res.shards = append(res.shards, q)
+	}
+	if s.scaling != nil {
+		res.producers = make([]pad.AtomicInt64, shards)
+	}
+	return res, nil
+}
+
+// enter routes item to a shard of the current set and, with adaptive shards,
+// counts the producer in until exit.
+func (s *shardedQueue[T]) enter(item T) (*shardSet[T], int) {
+	for {
+		set := s.set.Load()
+		i := s.routing(item, len(set.shards))
+		if set.producers == nil {
+			return set, i
+		}
+		set.producers[i].Add(1)
+		if s.set.Load() == set {
+			return set, i
+		}
+		// Resized meanwhile, the set may already be checked for producers
+		set.producers[i].Add(-1)
+	}
+}
+
+func (s *shardedQueue[T]) exit(set *shardSet[T], i int) {
+	if set.producers != nil {
+		set.producers[i].Add(-1)
+	}
+}
+
+func (s *shardedQueue[T]) Enqueue(item T) bool {
+	set, i := s.enter(item)
+	defer s.exit(set, i)
+	return set.shards[i].Enqueue(item)
+}
+
+func (s *shardedQueue[T]) TryEnqueue(item T) Result {
+	set, i := s.enter(item)
+	defer s.exit(set, i)
+	return set.shards[i].TryEnqueue(item)
+}
+
+func (s *shardedQueue[T]) MustEnqueue(item T) error {
+	set, i := s.enter(item)
+	defer s.exit(set, i)
+	return set.shards[i].MustEnqueue(item)
+}
+
+// Dequeue takes an item from the first non-empty shard, starting one shard
+// further on every call so no shard is starved. Items left in shards replaced
+// by a resize are taken first; while a producer is still enqueuing to them
+// the new shards are not served.
+func (s *shardedQueue[T]) Dequeue() (res T, ok bool) {
+	start := s.next.Add(1) - 1
+	set := s.set.Load()
+	if prev := set.prev.Load(); prev != nil {
+		idle := prev.idle()
+		if res, ok = dequeueShards(prev.shards, start); ok {
+			return res, true
+		}
+		if !idle {
+			// A producer may still add an item older than those of set
+			return res, false
+		}
+		s.retire(set, prev)
+	}
+	res, ok = dequeueShards(set.shards, start)
+	if s.scaling != nil && (!ok || start%shardScaleEvery == 0) {
+		s.scale()
+	}
+	return res, ok
+}
+
+func dequeueShards[T any](shards []IQueue[T], start uint64) (res T, ok bool) {
+	n := uint64(len(shards))
+	for i := uint64(0); i < n; i++ {
+		if res, ok = shards[(start+i)%n].Dequeue(); ok {
+			return res, true
+		}
+	}
+	return res, false
+}
+
+// TryDequeue makes one attempt on every shard, starting one shard further on
+// every call. It reports Contended if a shard was contended and none had an
+// item ready.
+func (s *shardedQueue[T]) TryDequeue() (res T, result Result) {
+	start := s.next.Add(1) - 1
+	set := s.set.Load()
+	if prev := set.prev.Load(); prev != nil {
+		idle := prev.idle()
+		if res, result = tryDequeueShards(prev.shards, start); result != Empty {
+			return res, result
+		}
+		if !idle {
+			return res, Contended
+		}
+		s.retire(set, prev)
+	}
+	res, result = tryDequeueShards(set.shards, start)
+	if s.scaling != nil && (result != Ok || start%shardScaleEvery == 0) {
+		s.scale()
+	}
+	return res, result
+}
+
+func tryDequeueShards[T any](shards []IQueue[T], start uint64) (res T, result Result) {
+	n := uint64(len(shards))
+	result = Empty
+	for i := uint64(0); i < n; i++ {
+		v, r := shards[(start+i)%n].TryDequeue()
+		switch r {
+		case Ok:
+			return v, Ok
+		case Contended:
+			result = Contended
+		}
+	}
+	return res, result
+}
+
+// retire drops the replaced set prev of set. The caller found prev empty
+// after it saw no producer in it, producers that came later moved to set.
+func (s *shardedQueue[T]) retire(set, prev *shardSet[T]) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !set.prev.CompareAndSwap(prev, nil) {
+		return
+	}
+	st := mergeQueueStats(prev.shards, 1)
+	s.retired.Enqueued += st.Enqueued
+	s.retired.Dequeued += st.Dequeued
+	s.retired.FailedEnqueues += st.FailedEnqueues
+	s.retired.BackoffSleeps += st.BackoffSleeps
+	s.retired.Contention += st.Contention
+}
+
+// scale evaluates the contention of the current set once per
+// shardScaleInterval and resizes it. A set is not resized while the set it
+// replaced still holds items.
+func (s *shardedQueue[T]) scale() {
+	now := int64(time.Since(s.start))
+	last := s.scaled.Load()
+	if now-last < int64(shardScaleInterval) || !s.scaled.CompareAndSwap(last, now) || !s.mu.TryLock() {
+		return
+	}
+	defer s.mu.Unlock()
+	set := s.set.Load()
+	st := mergeQueueStats(set.shards, 1)
+	enqueued, contention := st.Enqueued-s.enqueued, st.Contention-s.contention
+	s.enqueued, s.contention = st.Enqueued, st.Contention
+	if set.prev.Load() != nil {
+		return
+	}
+	n := len(set.shards)
+	switch {
+	case contention*shardGrowRatio > enqueued && n < s.scaling.max:
+		n = min(2*n, s.scaling.max)
+	case contention*shardShrinkRatio <= enqueued && n > s.scaling.min:
+		n = max(n/2, s.scaling.min)
+	default:
+		return
+	}
+	next, err := s.newSet(n)
+	if err != nil {
+		return
+	}
+	next.prev.Store(set)
+	// The new set starts its own counts
+	s.enqueued, s.contention = 0, 0
+	s.set.Store(next)
+}
+
+// Shard returns shard i of the current shard set.
+func (s *shardedQueue[T]) Shard(i int) IQueue[T] {
+	return s.set.Load().shards[i]
+}
+
+func (s *shardedQueue[T]) Shards() int {
+	return len(s.set.Load().shards)
+}
+
+// Stats sums the stats of the shards, including shards dropped by a resize.
+func (s *shardedQueue[T]) Stats() QueueStats {
+	set := s.set.Load()
+	res := mergeQueueStats(set.all(), uint64(len(set.shards))*s.capacity)
+	if s.scaling != nil {
+		s.mu.Lock()
+		res.Enqueued += s.retired.Enqueued
+		res.Dequeued += s.retired.Dequeued
+		res.FailedEnqueues += s.retired.FailedEnqueues
+		res.BackoffSleeps += s.retired.BackoffSleeps
+		res.Contention += s.retired.Contention
+		s.mu.Unlock()
+	}
+	return res
+}
+
+// WaitEmpty blocks until the items enqueued before the call have been
+// dequeued from every shard, or ctx is done.
+func (s *shardedQueue[T]) WaitEmpty(ctx context.Context) error {
+	for _, q := range s.set.Load().all() {
+		if err := q.WaitEmpty(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeQueueStats sums the stats of queues with a total capacity of capacity.
+func mergeQueueStats[T any](queues []IQueue[T], capacity uint64) QueueStats {
+	var res QueueStats
+	for _, q := range queues {
+		st := q.Stats()
+		res.Enqueued += st.Enqueued
+		res.Dequeued += st.Dequeued
+		res.Len += st.Len
+		res.FailedEnqueues += st.FailedEnqueues
+		res.BackoffSleeps += st.BackoffSleeps
+		res.Contention += st.Contention
+		if st.Latency != nil {
+			// Merged into a snapshot, the queues keep their own histograms
+			if res.Latency == nil {
+				res.Latency = &latency.Histogram{}
+			}
+			res.Latency.Merge(st.Latency)
+		}
+	}
+	res.Utilization = float64(res.Len) / float64(capacity)
+	return res
+}
@@ -0,0 +1,212 @@
+package ring
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestShardedQueue_RoundRobin(t *testing.T) {
+	q, err := ShardedQueue[int](4, 8, RoundRobin[int]())
+	if err != nil {
+		t.Fatalf("Failed to create queue: %v", err)
+	}
+	for i := 0; i < 8; i++ {
+		if !q.Enqueue(i) {
+			t.Fatalf("Enqueue %d failed", i)
+		}
+	}
+	for i := 0; i < q.Shards(); i++ {
+		if st := q.Shard(i).Stats(); st.Len != 2 {
+			t.Errorf("Expected 2 items in shard %d, got %d", i, st.Len)
+		}
+	}
+	if st := q.Stats(); st.Len != 8 || st.Utilization != 0.25 {
+		t.Errorf("Unexpected stats %+v", st)
+	}
+	seen := map[int]bool{}
+	for v, ok := q.Dequeue(); ok; v, ok = q.Dequeue() {
+		seen[v] = true
+	}
+	if len(seen) != 8 {
+		t.Errorf("Expected 8 distinct items, got %v", seen)
+	}
+}
+
+func TestShardedQueue_ByKey(t *testing.T) {
+	const producers, n = 4, 500
+	q, err := ShardedQueue[[2]int](4, 64, ByKey(func(v [2]int) int { return v[0] }))
+	if err != nil {
+		t.Fatalf("Failed to create queue: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			for i := 0; i < n; i++ {
+				if err := q.MustEnqueue([2]int{p, i}); err != nil {
+					t.Errorf("MustEnqueue failed: %v", err)
+					return
+				}
+			}
+		}(p)
+	}
+
+	// One consumer per shard sees the items of its keys in order
+	var consumers sync.WaitGroup
+	var mu sync.Mutex
+	total := 0
+	last := map[int]int{}
+	for i := 0; i < q.Shards(); i++ {
+		consumers.Add(1)
+		go func(shard IQueue[[2]int]) {
+			defer consumers.Done()
+			for {
+				v, ok := shard.Dequeue()
+				mu.Lock()
+				if ok {
+					if prev, seen := last[v[0]]; seen && v[1] != prev+1 {
+						t.Errorf("Key %d: expected %d, got %d", v[0], prev+1, v[1])
+					}
+					last[v[0]] = v[1]
+					total++
+				}
+				done := total == producers*n
+				mu.Unlock()
+				if done {
+					return
+				}
+			}
+		}(q.Shard(i))
+	}
+	wg.Wait()
+	consumers.Wait()
+
+	if _, err := ShardedQueue[int](0, 8, RoundRobin[int]()); err == nil {
+		t.Error("Expected an error for zero shards")
+	}
+}
+
+// forceScale evaluates the contention of q now, with extra lost CASes
+// recorded in its first shard.
+func forceScale[T any](q IShardedQueue[T], contention uint64) {
+	s := q.(*shardedQueue[T])
+	s.set.Load().shards[0].(*queue[T]).contention.Add(contention)
+	s.scaled.Store(-int64(time.Hour))
+	s.scale()
+}
+
+func TestShardedQueue_Adaptive(t *testing.T) {
+	q, err := ShardedQueue[[2]int](1, 64, ByKey(func(v [2]int) int { return v[0] }), WithAdaptiveShards(1, 4))
+	if err != nil {
+		t.Fatalf("Failed to create queue: %v", err)
+	}
+	for i := 0; i < 10; i++ {
+		for key := 0; key < 4; key++ {
+			q.Enqueue([2]int{key, i})
+		}
+	}
+	forceScale(q, 40)
+	if n := q.Shards(); n != 2 {
+		t.Fatalf("Expected 2 shards under contention, got %d", n)
+	}
+	for i := 10; i < 20; i++ {
+		for key := 0; key < 4; key++ {
+			q.Enqueue([2]int{key, i})
+		}
+	}
+	// Items of the replaced shard come first, so keys keep their order
+	last := map[int]int{}
+	for v, ok := q.Dequeue(); ok; v, ok = q.Dequeue() {
+		if prev, seen := last[v[0]]; seen && v[1] != prev+1 || !seen && v[1] != 0 {
+			t.Fatalf("Key %d: got %d after %d", v[0], v[1], prev)
+		}
+		last[v[0]] = v[1]
+	}
+	if st := q.Stats(); st.Enqueued != 80 || st.Dequeued != 80 || st.Len != 0 || st.Contention != 40 {
+		t.Errorf("Unexpected stats %+v", st)
+	}
+	if q.(*shardedQueue[[2]int]).set.Load().prev.Load() != nil {
+		t.Error("Expected the replaced shards to be dropped once empty")
+	}
+
+	forceScale(q, 0)
+	if n := q.Shards(); n != 1 {
+		t.Errorf("Expected 1 shard without contention, got %d", n)
+	}
+	if _, err := ShardedQueue[int](8, 8, RoundRobin[int](), WithAdaptiveShards(1, 4)); err == nil {
+		t.Error("Expected an error for shards above the maximum")
+	}
+}
+
+func TestShardedQueue_AdaptiveConcurrent(t *testing.T) {
+	const producers, n = 4, 2000
+	q, err := ShardedQueue[[2]int](1, 16, ByKey(func(v [2]int) int { return v[0] }), WithAdaptiveShards(1, 8))
+	if err != nil {
+		t.Fatalf("Failed to create queue: %v", err)
+	}
+	var wg sync.WaitGroup
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			for i := 0; i < n; i++ {
+				if err := q.MustEnqueue([2]int{p, i}); err != nil {
+					t.Errorf("MustEnqueue failed: %v", err)
+					return
+				}
+			}
+		}(p)
+	}
+	// The shards grow and shrink while producers enqueue
+	stop := make(chan struct{})
+	var resizes sync.WaitGroup
+	var sets int
+	resizes.Add(1)
+	go func() {
+		defer resizes.Done()
+		s := q.(*shardedQueue[[2]int])
+		for i := 0; ; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			set := s.set.Load()
+			forceScale(q, uint64(i%2)*1e6)
+			if s.set.Load() != set {
+				sets++
+			}
+			runtime.Gosched()
+		}
+	}()
+
+	last := make([]int, producers)
+	for i := range last {
+		last[i] = -1
+	}
+	for total := 0; total < producers*n; {
+		v, ok := q.Dequeue()
+		if !ok {
+			runtime.Gosched()
+			continue
+		}
+		if v[1] != last[v[0]]+1 {
+			t.Fatalf("Producer %d: expected %d, got %d", v[0], last[v[0]]+1, v[1])
+		}
+		last[v[0]] = v[1]
+		total++
+	}
+	close(stop)
+	resizes.Wait()
+	wg.Wait()
+	if sets < 2 {
+		t.Errorf("Expected the shards to be resized while in use, got %d resizes", sets)
+	}
+	if st := q.Stats(); st.Enqueued != producers*n || st.Dequeued != producers*n {
+		t.Errorf("Unexpected stats %+v", st)
+	}
+}
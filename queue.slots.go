@@ -0,0 +1,223 @@
+package ring
+
+import (
+	"context"
+	"github.com/dk-open/ring/latency"
+	"github.com/dk-open/ring/pad"
+	"runtime"
+	"sync/atomic"
+	"unsafe"
+)
+
+// WithQueueSlotSequences gives every slot of a queue its own sequence instead
+// of marking a claim with an odd head. A producer claims a position with one
+// CAS on the head and commits with a single store to the slot's sequence, and
+// consumers do the same on the tail. Producers then no longer wait for each
+// other between claim and commit, at the cost of a sequence word per slot.
+func WithQueueSlotSequences() QueueOption {
+	return func(o *queueOptions) {
+		o.slotSequences = true
+	}
+}
+
+// cursorLocked marks a cursor of a slot queue held by Snapshot or Reset.
+// Producers and consumers see no slot ready at a locked position and retry.
+const cursorLocked = 1 << 63
+
+// slotQueue is a bounded MPMC queue after Dmitry Vyukov. Position p may be
+// written once its slot's sequence is p and read once it is p+1, the reader
+// frees the slot for the next lap by storing p+capacity.
+type slotQueue[T any] struct {
+	buffer     []T
+	seqs       []atomic.Uint64
+	slotShift  uint
+	cap        uint64
+	capMask    uint64
+	head, tail pad.AtomicUint64
+
+	failedEnqueues atomic.Uint64
+	backoffSleeps  atomic.Uint64
+	contention     atomic.Uint64
+	watermarks     *watermarks
+	retry          retryPolicy
+	ctx            context.Context
+	dropHandler    func(item T)
+	// stamps and latency are nil unless WithQueueLatencyTracking
+	stamps  *stamps
+	latency *latency.Histogram
+}
+
+func newSlotQueue[T any](capacity uint64, o queueOptions) (*slotQueue[T], error) {
+	shift := slotShift[T](o.slotPadding)
+	buffer, err := newSlots[T](o.buffer, capacity, shift)
+	if err != nil {
+		return nil, err
+	}
+	res := &slotQueue[T]{
+		buffer:    buffer,
+		seqs:      make([]atomic.Uint64, capacity),
+		slotShift: shift,
+		cap:       capacity,
+		capMask:   capacity - 1,
+		retry:     o.retry,
+		ctx:       o.ctx,
+	}
+	for i := range res.seqs {
+		res.seqs[i].Store(uint64(i))
+	}
+	if o.latency {
+		res.stamps, res.latency = newStamps(capacity), &latency.Histogram{}
+	}
+	if w := o.watermarks; w != nil {
+		if res.watermarks, err = newWatermarks(w.high, w.low, w.fn, capacity); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+func (q *slotQueue[T]) Enqueue(item T) bool {
+	return q.TryEnqueue(item) == Ok
+}
+
+func (q *slotQueue[T]) TryEnqueue(item T) Result {
+	res := q.tryEnqueue(item)
+	if res != Ok {
+		q.failedEnqueues.Add(1)
+	}
+	if res == Full && q.dropHandler != nil {
+		q.dropHandler(item)
+	}
+	return res
+}
+
+func (q *slotQueue[T]) tryEnqueue(item T) Result {
+	raceRead(unsafe.Pointer(q))
+	pos := q.head.Load()
+	i := pos & q.capMask
+	seq := q.seqs[i].Load()
+	switch {
+	case seq == pos:
+		if !q.head.CompareAndSwap(pos, pos+1) {
+			q.contention.Add(1)
+			return Contended
+		}
+		q.buffer[i<<q.slotShift] = item
+		if q.stamps != nil {
+			q.stamps.publish(pos, 1)
+		}
+		q.seqs[i].Store(pos + 1)
+		q.occupancy()
+		return Ok
+	case seq < pos && q.tail.Load()&^cursorLocked+q.cap <= pos&^cursorLocked:
+		return Full
+	}
+	// Another producer took pos, or a consumer is still copying the item of
+	// the previous lap out
+	q.contention.Add(1)
+	return Contended
+}
+
+func (q *slotQueue[T]) MustEnqueue(item T) error {
+	r := retry{policy: q.retry, ctx: q.ctx}
+	for {
+		res := q.tryEnqueue(item)
+		if res == Ok {
+			return nil
+		}
+		if r.attempt+1 >= backoffSleepAttempt {
+			q.backoffSleeps.Add(1)
+		}
+		if err := r.backoff(res == Full); err != nil {
+			q.failedEnqueues.Add(1)
+			return err
+		}
+	}
+}
+
+// Dequeue takes the next item. It waits while the next item is still being
+// written, TryDequeue returns instead.
+func (q *slotQueue[T]) Dequeue() (res T, ok bool) {
+	for {
+		res, result := q.TryDequeue()
+		switch result {
+		case Ok:
+			return res, true
+		case Empty:
+			return res, false
+		}
+		runtime.Gosched()
+	}
+}
+
+func (q *slotQueue[T]) TryDequeue() (res T, result Result) {
+	raceRead(unsafe.Pointer(q))
+	pos := q.tail.Load()
+	i := pos & q.capMask
+	seq := q.seqs[i].Load()
+	switch {
+	case seq == pos+1:
+		if !q.tail.CompareAndSwap(pos, pos+1) {
+			return res, Contended
+		}
+		res = q.buffer[i<<q.slotShift]
+		if q.stamps == nil {
+			q.seqs[i].Store(pos + q.cap)
+			q.occupancy()
+			return res, Ok
+		}
+		published := q.stamps.published(pos)
+		q.seqs[i].Store(pos + q.cap)
+		q.occupancy()
+		q.stamps.record(q.latency, published)
+		return res, Ok
+	case seq == pos && q.head.Load() == pos:
+		return res, Empty
+	}
+	// Claimed by a producer that has not committed yet, or taken by another
+	// consumer
+	return res, Contended
+}
+
+func (q *slotQueue[T]) WaitEmpty(ctx context.Context) error {
+	head := q.head.Load()
+	if head == 0 {
+		return nil
+	}
+	// The consumer of the last item frees its slot once it copied the item out
+	last := &q.seqs[(head-1)&q.capMask]
+	return pollUntil(ctx, func() bool {
+		return q.tail.Load()&^cursorLocked >= head && last.Load() >= head-1+q.cap
+	})
+}
+
+func (q *slotQueue[T]) Stats() QueueStats {
+	tail := q.tail.Load() &^ cursorLocked
+	head := q.head.Load()
+	res := QueueStats{
+		Enqueued:       head,
+		Dequeued:       tail,
+		FailedEnqueues: q.failedEnqueues.Load(),
+		BackoffSleeps:  q.backoffSleeps.Load(),
+		Contention:     q.contention.Load(),
+		Latency:        q.latency,
+	}
+	if tail < head {
+		res.Len = head - tail
+	}
+	res.Utilization = float64(res.Len) / float64(q.cap)
+	return res
+}
+
+// occupancy checks the watermarks against the claimed queue length.
+func (q *slotQueue[T]) occupancy() {
+	if q.watermarks == nil {
+		return
+	}
+	tail := q.tail.Load() &^ cursorLocked
+	if head := q.head.Load(); tail < head {
+		q.watermarks.check(head - tail)
+		return
+	}
+	q.watermarks.check(0)
+}
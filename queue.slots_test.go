@@ -0,0 +1,116 @@
+package ring
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+)
+
+func TestSlotQueue_FullAndEmpty(t *testing.T) {
+	q, err := Queue[int](4, WithQueueSlotSequences())
+	if err != nil {
+		t.Fatalf("Failed to create queue: %v", err)
+	}
+	if _, res := q.TryDequeue(); res != Empty {
+		t.Fatalf("Expected Empty, got %v", res)
+	}
+	for round := 0; round < 3; round++ {
+		for i := 0; i < 4; i++ {
+			if res := q.TryEnqueue(i); res != Ok {
+				t.Fatalf("Round %d: expected Ok for %d, got %v", round, i, res)
+			}
+		}
+		if res := q.TryEnqueue(4); res != Full {
+			t.Fatalf("Round %d: expected Full, got %v", round, res)
+		}
+		for i := 0; i < 4; i++ {
+			if v, ok := q.Dequeue(); !ok || v != i {
+				t.Fatalf("Round %d: expected %d, got %d %v", round, i, v, ok)
+			}
+		}
+	}
+	stats := q.Stats()
+	if stats.Enqueued != 12 || stats.Dequeued != 12 || stats.Len != 0 || stats.FailedEnqueues != 3 {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+}
+
+func TestSlotQueue_StalledWriter(t *testing.T) {
+	q, err := Queue[int](4, WithQueueSlotSequences())
+	if err != nil {
+		t.Fatalf("Failed to create queue: %v", err)
+	}
+	inner := q.(*slotQueue[int])
+	// A producer that claimed position 0 and has not committed
+	inner.head.Store(1)
+	if _, res := q.TryDequeue(); res != Contended {
+		t.Fatalf("Expected Contended behind the stalled writer, got %v", res)
+	}
+	if res := q.TryEnqueue(1); res != Ok {
+		t.Fatalf("Expected other producers to proceed, got %v", res)
+	}
+	inner.seqs[0].Store(1)
+	for want := 0; want < 2; want++ {
+		if v, res := q.TryDequeue(); res != Ok || v != want {
+			t.Fatalf("Expected %d, got %d %v", want, v, res)
+		}
+	}
+}
+
+func TestSlotQueue_MultipleProducersAndConsumers(t *testing.T) {
+	const producers, consumers, perProducer = 4, 4, 1000
+	q, err := Queue[int](64, WithQueueSlotSequences(), WithQueueSlotPadding())
+	if err != nil {
+		t.Fatalf("Failed to create queue: %v", err)
+	}
+	var wg sync.WaitGroup
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			for i := 0; i < perProducer; i++ {
+				if err := q.MustEnqueue(p*perProducer + i); err != nil {
+					t.Errorf("MustEnqueue failed: %v", err)
+					return
+				}
+			}
+		}(p)
+	}
+	seen := make([][]bool, consumers)
+	var remaining sync.WaitGroup
+	remaining.Add(producers * perProducer)
+	done := make(chan struct{})
+	for c := 0; c < consumers; c++ {
+		seen[c] = make([]bool, producers*perProducer)
+		go func(c int) {
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				v, ok := q.Dequeue()
+				if !ok {
+					runtime.Gosched()
+					continue
+				}
+				seen[c][v] = true
+				remaining.Done()
+			}
+		}(c)
+	}
+	remaining.Wait()
+	close(done)
+	wg.Wait()
+	for v := 0; v < producers*perProducer; v++ {
+		n := 0
+		for c := range seen {
+			if seen[c][v] {
+				n++
+			}
+		}
+		if n != 1 {
+			t.Fatalf("Expected %d to be taken once, got %d", v, n)
+		}
+	}
+}
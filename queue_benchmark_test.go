@@ -161,3 +161,88 @@ func benchmarkGoChannels(b *testing.B, capacity int, numProducers, numConsumers
 
 	wg.Wait()
 }
+
+// BenchmarkQueue_EnqueueDequeue measures the producer and consumer paths
+// without goroutine handoffs, the items of a half-full queue at a time.
+func BenchmarkQueue_EnqueueDequeue(b *testing.B) {
+	for _, capacity := range []uint64{256, 4096} {
+		b.Run(fmt.Sprintf("Capacity-%d", capacity), func(b *testing.B) {
+			q, err := Queue[int](capacity)
+			if err != nil {
+				b.Fatalf("Failed to create queue: %v", err)
+			}
+			half := int(capacity / 2)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i += half {
+				for j := 0; j < half; j++ {
+					q.Enqueue(j)
+				}
+				for j := 0; j < half; j++ {
+					q.Dequeue()
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkQueue_Protocol compares the odd-head protocol, which takes a CAS
+// and two stores per item and serializes producers between claim and commit,
+// with per-slot sequences committing with a single store.
+func BenchmarkQueue_Protocol(b *testing.B) {
+	protocols := []struct {
+		name string
+		opts []QueueOption
+	}{
+		{"OddHead", nil},
+		{"SlotSequences", []QueueOption{WithQueueSlotSequences()}},
+	}
+	for _, p := range protocols {
+		b.Run(p.name+"/Uncontended", func(b *testing.B) {
+			q, err := Queue[int](1024, p.opts...)
+			if err != nil {
+				b.Fatalf("Failed to create queue: %v", err)
+			}
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				q.Enqueue(i)
+				q.Dequeue()
+			}
+		})
+		b.Run(p.name+"/Producers", func(b *testing.B) {
+			q, err := Queue[int](1024, p.opts...)
+			if err != nil {
+				b.Fatalf("Failed to create queue: %v", err)
+			}
+			done := make(chan struct{})
+			var consumed atomic.Int64
+			go func() {
+				for {
+					if _, ok := q.Dequeue(); ok {
+						consumed.Add(1)
+						continue
+					}
+					select {
+					case <-done:
+						return
+					default:
+						runtime.Gosched()
+					}
+				}
+			}()
+			b.ReportAllocs()
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					_ = q.MustEnqueue(1)
+				}
+			})
+			for consumed.Load() < int64(b.N) {
+				runtime.Gosched()
+			}
+			b.StopTimer()
+			close(done)
+		})
+	}
+}
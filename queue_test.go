@@ -1,6 +1,8 @@
 package ring
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"runtime"
 	"sync"
@@ -76,6 +78,30 @@ func TestQueue_CapacityLimits(t *testing.T) {
 	}
 }
 
+func TestQueue_CachedTail(t *testing.T) {
+	q, err := Queue[int](4)
+	if err != nil {
+		t.Fatalf("Failed to create queue: %v", err)
+	}
+	inner := q.(*queue[int])
+	for round := 0; round < 3; round++ {
+		for i := 0; i < 4; i++ {
+			if !q.Enqueue(i) {
+				t.Fatalf("Round %d: failed to enqueue item %d", round, i)
+			}
+		}
+		for i := 0; i < 4; i++ {
+			if _, ok := q.Dequeue(); !ok {
+				t.Fatalf("Round %d: failed to dequeue item %d", round, i)
+			}
+		}
+	}
+	// Every round only reloads the tail once the cached one shows a full ring
+	if got, want := inner.cachedTail.Load(), inner.tail.Load()-8; got != want {
+		t.Errorf("Expected the cached tail to lag at %d, got %d", want, got)
+	}
+}
+
 func TestQueue_FIFO(t *testing.T) {
 	q, err := Queue[int](16)
 	if err != nil {
@@ -241,3 +267,69 @@ func TestMustEnqueue_SuccessPath(t *testing.T) {
 		}
 	}
 }
+
+func TestQueue_WaitEmpty(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	for name, newQueue := range map[string]func() (IQueue[int], error){
+		"Cursor":  func() (IQueue[int], error) { return Queue[int](16) },
+		"Slots":   func() (IQueue[int], error) { return Queue[int](16, WithQueueSlotSequences()) },
+		"Sharded": func() (IQueue[int], error) { return ShardedQueue[int](2, 8, RoundRobin[int]()) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			q, err := newQueue()
+			if err != nil {
+				t.Fatalf("Failed to create queue: %v", err)
+			}
+			if err := q.WaitEmpty(ctx); err != nil {
+				t.Fatalf("Expected an empty queue not to wait, got %v", err)
+			}
+			for i := 0; i < 8; i++ {
+				q.Enqueue(i)
+			}
+			short, cancelShort := context.WithTimeout(ctx, 10*time.Millisecond)
+			defer cancelShort()
+			if err := q.WaitEmpty(short); !errors.Is(err, context.DeadlineExceeded) {
+				t.Fatalf("Expected to time out while items are queued, got %v", err)
+			}
+			go func() {
+				for i := 0; i < 8; {
+					if _, ok := q.Dequeue(); ok {
+						i++
+					}
+					runtime.Gosched()
+				}
+			}()
+			if err := q.WaitEmpty(ctx); err != nil {
+				t.Fatalf("WaitEmpty failed: %v", err)
+			}
+			if got := q.Stats().Dequeued; got != 8 {
+				t.Errorf("Expected 8 dequeued items, got %d", got)
+			}
+		})
+	}
+}
+
+func TestQueue_DropHandler(t *testing.T) {
+	for _, slots := range []bool{false, true} {
+		var rejected []int
+		opts := []QueueOption{WithQueueDropHandler(func(item int) { rejected = append(rejected, item) })}
+		if slots {
+			opts = append(opts, WithQueueSlotSequences())
+		}
+		q, err := Queue[int](2, opts...)
+		if err != nil {
+			t.Fatalf("Failed to create queue: %v", err)
+		}
+		for i := 0; i < 4; i++ {
+			q.Enqueue(i)
+		}
+		if len(rejected) != 2 || rejected[0] != 2 || rejected[1] != 3 {
+			t.Errorf("Expected items 2 and 3 rejected, got %v", rejected)
+		}
+	}
+	if _, err := Queue[int](2, WithQueueDropHandler(func(string) {})); !errors.Is(err, ErrReaderType) {
+		t.Errorf("Expected ErrReaderType for a mismatched handler, got %v", err)
+	}
+}
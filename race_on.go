@@ -0,0 +1,18 @@
+//go:build race
+
+package ring
+
+import (
+	"runtime"
+	"unsafe"
+)
+
+// raceRead and raceWrite report accesses to the race detector that the
+// rings' atomics would otherwise order, so misuse shows up as a data race.
+func raceRead(addr unsafe.Pointer) {
+	runtime.RaceRead(addr)
+}
+
+func raceWrite(addr unsafe.Pointer) {
+	runtime.RaceWrite(addr)
+}
@@ -0,0 +1,40 @@
+package ring
+
+// Result tells why a TryEnqueue or TryDequeue attempt failed. Retrying on
+// Contended is cheap and likely to succeed, on Full and Empty it only succeeds
+// once the other side catches up and on Closed it never does.
+type Result int
+
+const (
+	// Ok reports that the item was published or taken.
+	Ok Result = iota
+	// Full reports that the ring has no free slot. A disruptor also reports
+	// Full while producers wait for admission, over its rate limit and after
+	// the DropNewest policy dropped the item.
+	Full
+	// Empty reports that there is nothing to take.
+	Empty
+	// Contended reports that another producer or consumer claimed the slot
+	// first, or that the next item is still being written. A writer that is
+	// descheduled before its commit keeps the slot contended until it runs.
+	Contended
+	// Closed reports that the ring no longer accepts items, including a
+	// disruptor whose sequences are exhausted.
+	Closed
+)
+
+func (r Result) String() string {
+	switch r {
+	case Ok:
+		return "ok"
+	case Full:
+		return "full"
+	case Empty:
+		return "empty"
+	case Contended:
+		return "contended"
+	case Closed:
+		return "closed"
+	}
+	return "unknown"
+}
@@ -0,0 +1,123 @@
+package ring
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestQueue_TryEnqueue(t *testing.T) {
+	q, err := Queue[int](4)
+	if err != nil {
+		t.Fatalf("Failed to create queue: %v", err)
+	}
+	for i := 0; i < 4; i++ {
+		if res := q.TryEnqueue(i); res != Ok {
+			t.Fatalf("Expected Ok, got %v", res)
+		}
+	}
+	if res := q.TryEnqueue(2); res != Full {
+		t.Fatalf("Expected Full, got %v", res)
+	}
+	// A producer between its claim and its commit
+	inner := q.(*queue[int])
+	_, _ = q.Dequeue()
+	_, _ = q.Dequeue()
+	inner.head.Add(1)
+	if res := q.TryEnqueue(3); res != Contended {
+		t.Fatalf("Expected Contended, got %v", res)
+	}
+	inner.head.Add(1)
+	if got := q.Stats().FailedEnqueues; got != 2 {
+		t.Errorf("Expected 2 failed enqueues, got %d", got)
+	}
+}
+
+func TestDisruptor_TryEnqueue(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	d, err := NewDisruptor[int](ctx, WithCapacity(2))
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	release := make(chan struct{})
+	d.HandleWith(func(int) { <-release })
+	for i := 0; i < 2; i++ {
+		if res := d.TryEnqueue(i); res != Ok {
+			t.Fatalf("Expected Ok for %d, got %v", i, res)
+		}
+	}
+	if res := d.TryEnqueue(4); res != Full {
+		t.Fatalf("Expected Full, got %v", res)
+	}
+	inner := d.(*disruptor[int])
+	head := inner.writerCursor.Load()
+	inner.writerCursor.Store(head + 1)
+	if res := d.TryEnqueue(5); res != Contended {
+		t.Fatalf("Expected Contended, got %v", res)
+	}
+	inner.writerCursor.Store(head)
+	close(release)
+	if err := d.Close(ctx); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if res := d.TryEnqueue(6); res != Closed {
+		t.Fatalf("Expected Closed, got %v", res)
+	}
+}
+
+func TestQueue_TryDequeue(t *testing.T) {
+	q, err := Queue[int](4)
+	if err != nil {
+		t.Fatalf("Failed to create queue: %v", err)
+	}
+	if _, res := q.TryDequeue(); res != Empty {
+		t.Fatalf("Expected Empty, got %v", res)
+	}
+	_ = q.MustEnqueue(1)
+	// A writer stalled between its claim and its commit
+	inner := q.(*queue[int])
+	inner.head.Add(1)
+	if v, res := q.TryDequeue(); res != Ok || v != 1 {
+		t.Fatalf("Expected Ok with 1, got %v with %d", res, v)
+	}
+	if _, res := q.TryDequeue(); res != Contended {
+		t.Fatalf("Expected Contended behind the stalled writer, got %v", res)
+	}
+	inner.head.Add(1)
+	if _, res := q.TryDequeue(); res != Ok {
+		t.Fatalf("Expected Ok once the writer committed, got %v", res)
+	}
+}
+
+func TestShardedQueue_TryDequeue(t *testing.T) {
+	q, err := ShardedQueue[int](2, 4, RoundRobin[int]())
+	if err != nil {
+		t.Fatalf("Failed to create queue: %v", err)
+	}
+	if _, res := q.TryDequeue(); res != Empty {
+		t.Fatalf("Expected Empty, got %v", res)
+	}
+	_ = q.MustEnqueue(7)
+	if v, res := q.TryDequeue(); res != Ok || v != 7 {
+		t.Fatalf("Expected Ok with 7, got %v with %d", res, v)
+	}
+}
+
+func TestGaveUp_Errors(t *testing.T) {
+	full, contended := gaveUp(true), gaveUp(false)
+	if !errors.Is(full, ErrTooManyAttempts) || !errors.Is(full, ErrFull) {
+		t.Errorf("Expected a full give-up to match ErrTooManyAttempts and ErrFull, got %v", full)
+	}
+	if !errors.Is(contended, ErrTooManyAttempts) || errors.Is(contended, ErrFull) {
+		t.Errorf("Expected a contended give-up to match only ErrTooManyAttempts, got %v", contended)
+	}
+	if allocs := testing.AllocsPerRun(100, func() {
+		_ = gaveUp(true)
+		r := retry{attempt: defaultMaxAttempts}
+		_ = r.backoff(true)
+	}); allocs != 0 {
+		t.Errorf("Expected no allocations on the error path, got %v", allocs)
+	}
+}
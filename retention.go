@@ -0,0 +1,81 @@
+package ring
+
+import (
+	"context"
+	"runtime/pprof"
+	"sync"
+	"time"
+)
+
+// DefaultReclaimInterval is the reclaim cadence used when WithReclaimInterval
+// is not given.
+const DefaultReclaimInterval = 100 * time.Millisecond
+
+// Retainer holds side metadata keyed by event sequence, e.g. a dedup window
+// or a conflation map, that is reclaimed by the disruptor it is attached to.
+type Retainer interface {
+	// Release drops the entries of events before seq, which every gating
+	// reader has passed, and returns the number of entries dropped.
+	Release(seq uint64) int
+}
+
+// retention calls the registered retainers from a single maintenance
+// goroutine, started with the first retainer.
+type retention struct {
+	mu        sync.Mutex
+	retainers []*Retainer
+	started   bool
+	interval  time.Duration
+}
+
+// Retain attaches r to the disruptor's maintenance goroutine, which releases
+// the entries of passed events every reclaim interval until the disruptor is
+// closed or the returned function is called.
+func (d *disruptor[T]) Retain(r Retainer) (remove func()) {
+	entry := &r
+	d.retention.mu.Lock()
+	defer d.retention.mu.Unlock()
+	d.retention.retainers = append(d.retention.retainers, entry)
+	if !d.retention.started {
+		d.retention.started = true
+		pprof.Do(d.ctx, pprof.Labels("ring", d.name, "kind", "reclaim"), func(context.Context) {
+			go d.reclaimLoop()
+		})
+	}
+	return func() {
+		d.retention.mu.Lock()
+		defer d.retention.mu.Unlock()
+		for i, v := range d.retention.retainers {
+			if v == entry {
+				d.retention.retainers = append(d.retention.retainers[:i], d.retention.retainers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+func (d *disruptor[T]) reclaimLoop() {
+	t := time.NewTicker(d.retention.interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-d.ctx.Done():
+			return
+		case <-t.C:
+			d.reclaim()
+		}
+	}
+}
+
+// reclaim releases the passed events from every retainer. The lock is held
+// while retainers run, so a removed retainer is not called afterwards.
+func (d *disruptor[T]) reclaim() {
+	released := d.Released()
+	d.retention.mu.Lock()
+	defer d.retention.mu.Unlock()
+	for _, r := range d.retention.retainers {
+		if n := (*r).Release(released); n > 0 {
+			d.reclaimed.Add(uint64(n))
+		}
+	}
+}
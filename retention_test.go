@@ -0,0 +1,91 @@
+package ring
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// seqWindow retains a value per sequence, as a dedup window would.
+type seqWindow struct {
+	mu      sync.Mutex
+	entries map[uint64]int
+}
+
+func (w *seqWindow) Release(seq uint64) int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	n := 0
+	for s := range w.entries {
+		if s < seq {
+			delete(w.entries, s)
+			n++
+		}
+	}
+	return n
+}
+
+func (w *seqWindow) len() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.entries)
+}
+
+func TestDisruptor_Retain(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	release := make(chan struct{})
+	d, err := NewDisruptor[int](ctx,
+		WithCapacity(16),
+		WithReclaimInterval(time.Millisecond),
+		WithMaxBatch(1),
+		WithReaders(func(value int) {
+			if value == 5 {
+				<-release
+			}
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	w := &seqWindow{entries: map[uint64]int{}}
+	remove := d.Retain(w)
+	for i := 0; i < 10; i++ {
+		w.mu.Lock()
+		w.entries[uint64(i)] = i
+		w.mu.Unlock()
+		if err := d.MustEnqueue(i); err != nil {
+			t.Fatalf("MustEnqueue failed: %v", err)
+		}
+	}
+
+	// The reader holds event 5, its entry and the later ones are retained
+	waitFor(t, func() bool { return w.len() == 5 })
+	close(release)
+	waitFor(t, func() bool { return w.len() == 0 })
+	if n := d.Stats().Reclaimed; n != 10 {
+		t.Errorf("Expected 10 reclaimed entries, got %d", n)
+	}
+
+	remove()
+	w.mu.Lock()
+	w.entries[3] = 3
+	w.mu.Unlock()
+	time.Sleep(10 * time.Millisecond)
+	if w.len() != 1 {
+		t.Error("Expected a removed retainer not to be released")
+	}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for condition")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,144 @@
+package ring
+
+import (
+	"context"
+	"github.com/dk-open/ring/pad"
+	"runtime"
+	"time"
+)
+
+// defaultMaxAttempts is the number of attempts after which MustEnqueue gives
+// up unless a retry option says otherwise.
+const defaultMaxAttempts = 10000
+
+// maxBackoffSleep caps the exponential sleeps between enqueue attempts.
+const maxBackoffSleep = 5 * time.Millisecond
+
+// WithMaxAttempts makes MustEnqueue give up after n attempts. A non-positive
+// n keeps the default of 10000.
+func WithMaxAttempts(n int) Option {
+	return func(o *options) {
+		o.retry = retryPolicy{maxAttempts: n}
+	}
+}
+
+// WithMaxWait makes MustEnqueue give up once it has waited d for the ring,
+// independent of how many attempts fit in that time.
+func WithMaxWait(d time.Duration) Option {
+	return func(o *options) {
+		o.retry = retryPolicy{maxWait: d}
+	}
+}
+
+// WithBlockForever makes MustEnqueue wait for the ring until it succeeds, the
+// disruptor is closed or its context is done.
+func WithBlockForever() Option {
+	return func(o *options) {
+		o.retry = retryPolicy{forever: true}
+	}
+}
+
+// WithQueueMaxAttempts is WithMaxAttempts for queues.
+func WithQueueMaxAttempts(n int) QueueOption {
+	return func(o *queueOptions) {
+		o.retry = retryPolicy{maxAttempts: n}
+	}
+}
+
+// WithQueueMaxWait is WithMaxWait for queues.
+func WithQueueMaxWait(d time.Duration) QueueOption {
+	return func(o *queueOptions) {
+		o.retry = retryPolicy{maxWait: d}
+	}
+}
+
+// WithQueueBlockForever makes MustEnqueue wait for the queue until it
+// succeeds, or until the context of WithQueueContext is done.
+func WithQueueBlockForever() QueueOption {
+	return func(o *queueOptions) {
+		o.retry = retryPolicy{forever: true}
+	}
+}
+
+// WithQueueContext stops a waiting MustEnqueue once ctx is done, it then
+// returns ctx.Err().
+func WithQueueContext(ctx context.Context) QueueOption {
+	return func(o *queueOptions) {
+		o.ctx = ctx
+	}
+}
+
+// retryPolicy bounds how long MustEnqueue retries a full or contended ring.
+// The zero value gives up after defaultMaxAttempts attempts.
+type retryPolicy struct {
+	maxAttempts int
+	maxWait     time.Duration
+	forever     bool
+}
+
+// retry is the state of a single MustEnqueue call.
+type retry struct {
+	policy  retryPolicy
+	ctx     context.Context
+	attempt int
+	// since is when the call first waited, only tracked under maxWait
+	since time.Time
+}
+
+// backoff waits before the next attempt. It returns the context's error once
+// the context is done and gaveUp(full) once the policy is exhausted.
+func (r *retry) backoff(full bool) error {
+	r.attempt++
+	if r.ctx != nil {
+		if err := r.ctx.Err(); err != nil {
+			return err
+		}
+	}
+	if r.exhausted() {
+		return gaveUp(full)
+	}
+	switch {
+	case r.attempt < spinAttempts:
+		pad.SpinHint()
+	case r.attempt < backoffSleepAttempt:
+		runtime.Gosched() // Let Go scheduler run another goroutine
+	default:
+		time.Sleep(r.sleep())
+	}
+	return nil
+}
+
+// reset starts over after the call made progress.
+func (r *retry) reset() {
+	r.attempt = 0
+	r.since = time.Time{}
+}
+
+func (r *retry) exhausted() bool {
+	switch p := r.policy; {
+	case p.forever:
+		return false
+	case p.maxWait > 0:
+		if r.since.IsZero() {
+			r.since = time.Now()
+			return false
+		}
+		return time.Since(r.since) >= p.maxWait
+	case p.maxAttempts > 0:
+		return r.attempt >= p.maxAttempts
+	}
+	return r.attempt >= defaultMaxAttempts
+}
+
+// sleep doubles from a microsecond up to maxBackoffSleep, it does not
+// oversleep the deadline of maxWait.
+func (r *retry) sleep() time.Duration {
+	d := maxBackoffSleep
+	if shift := r.attempt - backoffSleepAttempt; shift < 13 {
+		d = min(time.Microsecond<<uint(shift), d)
+	}
+	if r.policy.maxWait > 0 {
+		d = min(r.policy.maxWait-time.Since(r.since), d)
+	}
+	return d
+}
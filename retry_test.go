@@ -0,0 +1,129 @@
+package ring
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func fullQueue(t *testing.T, opts ...QueueOption) IQueue[int] {
+	t.Helper()
+	q, err := Queue[int](2, opts...)
+	if err != nil {
+		t.Fatalf("Failed to create queue: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := q.MustEnqueue(i); err != nil {
+			t.Fatalf("Failed to enqueue %d: %v", i, err)
+		}
+	}
+	return q
+}
+
+func TestQueue_MaxAttempts(t *testing.T) {
+	for _, slots := range []bool{false, true} {
+		opts := []QueueOption{WithQueueMaxAttempts(3)}
+		if slots {
+			opts = append(opts, WithQueueSlotSequences())
+		}
+		q := fullQueue(t, opts...)
+		start := time.Now()
+		if err := q.MustEnqueue(2); !errors.Is(err, ErrFull) {
+			t.Fatalf("Expected ErrFull, got %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+			t.Errorf("Expected 3 attempts to give up at once, took %v", elapsed)
+		}
+	}
+}
+
+func TestQueue_MaxWait(t *testing.T) {
+	q := fullQueue(t, WithQueueMaxWait(20*time.Millisecond))
+	start := time.Now()
+	if err := q.MustEnqueue(2); !errors.Is(err, ErrTooManyAttempts) {
+		t.Fatalf("Expected ErrTooManyAttempts, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond || elapsed > time.Second {
+		t.Errorf("Expected to give up after about 20ms, took %v", elapsed)
+	}
+}
+
+func TestQueue_BlockForever(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	q := fullQueue(t, WithQueueBlockForever(), WithQueueContext(ctx))
+	done := make(chan error, 1)
+	go func() { done <- q.MustEnqueue(2) }()
+	time.Sleep(20 * time.Millisecond)
+	if _, ok := q.Dequeue(); !ok {
+		t.Fatal("Expected an item")
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("Expected the blocked enqueue to succeed, got %v", err)
+	}
+
+	go func() { done <- q.MustEnqueue(3) }()
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("Expected context.Canceled, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("MustEnqueue did not return after the context was cancelled")
+	}
+}
+
+func TestDisruptor_RetryPolicy(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	release := make(chan struct{})
+	defer close(release)
+	d, err := NewDisruptor[int](ctx, WithCapacity(2), WithMaxAttempts(3),
+		WithReaders(func(int) { <-release }))
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := d.MustEnqueue(i); err != nil {
+			t.Fatalf("Failed to enqueue %d: %v", i, err)
+		}
+	}
+	if err := d.MustEnqueue(2); !errors.Is(err, ErrFull) {
+		t.Fatalf("Expected ErrFull, got %v", err)
+	}
+
+	inner := d.(*disruptor[int])
+	inner.retry = retryPolicy{forever: true}
+	done := make(chan error, 1)
+	go func() { done <- d.MustEnqueue(3) }()
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("Expected context.Canceled, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("MustEnqueue did not return after the context was cancelled")
+	}
+}
+
+func TestRetry_Sleep(t *testing.T) {
+	r := retry{attempt: backoffSleepAttempt}
+	if got := r.sleep(); got != time.Microsecond {
+		t.Errorf("Expected the first sleep to be 1µs, got %v", got)
+	}
+	r.attempt = 1 << 20
+	if got := r.sleep(); got != maxBackoffSleep {
+		t.Errorf("Expected late sleeps to be capped at %v, got %v", maxBackoffSleep, got)
+	}
+	r = retry{policy: retryPolicy{maxWait: time.Millisecond}, attempt: 1 << 20, since: time.Now()}
+	if got := r.sleep(); got > time.Millisecond {
+		t.Errorf("Expected the sleep not to pass the deadline, got %v", got)
+	}
+}
@@ -0,0 +1,197 @@
+// Package ringmetrics exports the Stats of disruptors and queues as expvar
+// variables and in the Prometheus text exposition format. It has no
+// dependency on a Prometheus client, a Registry is scraped as an http.Handler.
+package ringmetrics
+
+import (
+	"expvar"
+	"fmt"
+	"github.com/dk-open/ring"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DisruptorSource is implemented by ring.IDisruptor.
+type DisruptorSource interface {
+	Stats() ring.Stats
+}
+
+// QueueSource is implemented by ring.IQueue.
+type QueueSource interface {
+	Stats() ring.QueueStats
+}
+
+// Registry collects the stats of named disruptors and queues.
+type Registry struct {
+	mu         sync.Mutex
+	disruptors map[string]DisruptorSource
+	queues     map[string]QueueSource
+}
+
+// New creates an empty registry.
+func New() *Registry {
+	return &Registry{
+		disruptors: make(map[string]DisruptorSource),
+		queues:     make(map[string]QueueSource),
+	}
+}
+
+// Disruptor registers a disruptor under name, replacing a previous one.
+func (r *Registry) Disruptor(name string, d DisruptorSource) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.disruptors[name] = d
+}
+
+// Queue registers a queue under name, replacing a previous one.
+func (r *Registry) Queue(name string, q QueueSource) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.queues[name] = q
+}
+
+// Unregister removes the disruptor or queue registered under name.
+func (r *Registry) Unregister(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.disruptors, name)
+	delete(r.queues, name)
+}
+
+// Snapshot is the expvar representation of a registry.
+type Snapshot struct {
+	Disruptors map[string]ring.Stats      `json:"disruptors"`
+	Queues     map[string]ring.QueueStats `json:"queues"`
+}
+
+// Snapshot reads the stats of every registered disruptor and queue.
+func (r *Registry) Snapshot() Snapshot {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	res := Snapshot{
+		Disruptors: make(map[string]ring.Stats, len(r.disruptors)),
+		Queues:     make(map[string]ring.QueueStats, len(r.queues)),
+	}
+	for name, d := range r.disruptors {
+		res.Disruptors[name] = d.Stats()
+	}
+	for name, q := range r.queues {
+		res.Queues[name] = q.Stats()
+	}
+	return res
+}
+
+// Publish exposes the registry as an expvar variable. Like expvar.Publish it
+// panics if name is already in use.
+func (r *Registry) Publish(name string) {
+	expvar.Publish(name, expvar.Func(func() any {
+		return r.Snapshot()
+	}))
+}
+
+// ServeHTTP writes the registry in the Prometheus text exposition format. The
+// publish rate is rate(ring_published_total[...]).
+func (r *Registry) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_ = r.WriteText(w)
+}
+
+type sample struct {
+	labels string
+	value  float64
+}
+
+type family struct {
+	name, kind, help string
+	samples          []sample
+}
+
+// WriteText writes the registry in the Prometheus text exposition format.
+func (r *Registry) WriteText(w io.Writer) error {
+	s := r.Snapshot()
+	families := []*family{
+		{name: "ring_published_total", kind: "counter", help: "Events published to the disruptor."},
+		{name: "ring_enqueue_failures_total", kind: "counter", help: "Rejected or abandoned enqueues."},
+		{name: "ring_backoff_sleeps_total", kind: "counter", help: "Producer sleeps waiting for readers."},
+		{name: "ring_utilization", kind: "gauge", help: "Fraction of the ring held by the slowest reader."},
+		{name: "ring_reclaimed_total", kind: "counter", help: "Retainer entries released after readers passed their events."},
+		{name: "ring_reader_lag", kind: "gauge", help: "Published events the reader has not passed."},
+		{name: "ring_reader_processed_total", kind: "counter", help: "Events consumed by the reader."},
+		{name: "ring_reader_busy_seconds_total", kind: "counter", help: "Time spent in the reader's callback."},
+		{name: "ring_queue_depth", kind: "gauge", help: "Events waiting in the queue."},
+		{name: "ring_queue_enqueued_total", kind: "counter", help: "Events enqueued."},
+		{name: "ring_queue_dequeued_total", kind: "counter", help: "Events dequeued."},
+		{name: "ring_queue_enqueue_failures_total", kind: "counter", help: "Rejected or abandoned enqueues."},
+		{name: "ring_queue_utilization", kind: "gauge", help: "Fraction of the queue capacity in use."},
+	}
+	add := func(i int, labels string, v float64) {
+		families[i].samples = append(families[i].samples, sample{labels: labels, value: v})
+	}
+
+	for _, name := range sortedKeys(s.Disruptors) {
+		st := s.Disruptors[name]
+		l := labels("ring", name)
+		add(0, l, float64(st.Writer))
+		add(1, l, float64(st.FailedEnqueues))
+		add(2, l, float64(st.BackoffSleeps))
+		add(3, l, st.Utilization)
+		add(4, l, float64(st.Reclaimed))
+		for _, rd := range st.Readers {
+			rl := labels("ring", name, "reader", strconv.FormatUint(rd.ID, 10), "group", rd.Group)
+			add(5, rl, float64(rd.Lag))
+			add(6, rl, float64(rd.Processed))
+			add(7, rl, rd.Busy.Seconds())
+		}
+	}
+	for _, name := range sortedKeys(s.Queues) {
+		st := s.Queues[name]
+		l := labels("queue", name)
+		add(8, l, float64(st.Len))
+		add(9, l, float64(st.Enqueued))
+		add(10, l, float64(st.Dequeued))
+		add(11, l, float64(st.FailedEnqueues))
+		add(12, l, st.Utilization)
+	}
+
+	var b strings.Builder
+	for _, f := range families {
+		if len(f.samples) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s %s\n", f.name, f.help, f.name, f.kind)
+		for _, s := range f.samples {
+			fmt.Fprintf(&b, "%s{%s} %g\n", f.name, s.labels, s.value)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func labels(kv ...string) string {
+	var b strings.Builder
+	for i := 0; i < len(kv); i += 2 {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(kv[i])
+		b.WriteString(`="`)
+		b.WriteString(escaper.Replace(kv[i+1]))
+		b.WriteByte('"')
+	}
+	return b.String()
+}
+
+var escaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,104 @@
+package ringmetrics
+
+import (
+	"context"
+	"encoding/json"
+	"expvar"
+	"github.com/dk-open/ring"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRegistry_WriteText(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	d, err := ring.Disruptor[int](ctx, 8)
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	d.HandleWithGroup(`jour"nal`, func(int) {})
+	q, err := ring.Queue[int](4)
+	if err != nil {
+		t.Fatalf("Failed to create queue: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		if err := d.MustEnqueue(i); err != nil {
+			t.Fatalf("MustEnqueue failed: %v", err)
+		}
+		q.Enqueue(i)
+	}
+
+	r := New()
+	r.Disruptor("orders", d)
+	r.Queue("fills", q)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body := rec.Body.String()
+	for _, want := range []string{
+		"# TYPE ring_published_total counter",
+		`ring_published_total{ring="orders"} 3`,
+		`ring_reader_lag{ring="orders",reader="0",group="jour\"nal"}`,
+		`ring_reclaimed_total{ring="orders"} 0`,
+		`ring_queue_depth{queue="fills"} 3`,
+		`ring_queue_utilization{queue="fills"} 0.75`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected %q in output:\n%s", want, body)
+		}
+	}
+
+	r.Unregister("fills")
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if strings.Contains(rec.Body.String(), "ring_queue_depth") {
+		t.Errorf("Expected unregistered queue to be dropped")
+	}
+}
+
+func TestRegistry_ReaderID(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	d, err := ring.Disruptor[int](ctx, 8)
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	first, second := d.NewReader(), d.NewReader()
+	for i := 0; i < 3; i++ {
+		d.Enqueue(i)
+	}
+	second.Dequeue()
+	// The second reader keeps its label once the first one is gone
+	first.Close()
+
+	r := New()
+	r.Disruptor("orders", d)
+	var b strings.Builder
+	r.WriteText(&b)
+	if want := `ring_reader_lag{ring="orders",reader="1",group=""} 2`; !strings.Contains(b.String(), want) {
+		t.Errorf("Expected %q in output:\n%s", want, b.String())
+	}
+}
+
+func TestRegistry_Publish(t *testing.T) {
+	q, err := ring.Queue[int](4)
+	if err != nil {
+		t.Fatalf("Failed to create queue: %v", err)
+	}
+	q.Enqueue(1)
+
+	r := New()
+	r.Queue("q", q)
+	r.Publish("ringmetrics_test")
+
+	var s Snapshot
+	if err := json.Unmarshal([]byte(expvar.Get("ringmetrics_test").String()), &s); err != nil {
+		t.Fatalf("Failed to decode expvar: %v", err)
+	}
+	if s.Queues["q"].Len != 1 {
+		t.Errorf("Expected queue depth 1, got %+v", s.Queues["q"])
+	}
+}
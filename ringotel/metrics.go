@@ -0,0 +1,146 @@
+// Package ringotel instruments disruptors and queues with OpenTelemetry. It
+// reports their Stats as asynchronous metrics and traces events through a ring
+// by carrying the context they were published in along in an Envelope. It is
+// a module of its own, so the ring module keeps no dependencies.
+package ringotel
+
+import (
+	"context"
+	"errors"
+	"github.com/dk-open/ring"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const scope = "github.com/dk-open/ring/ringotel"
+
+// Option configures the instrumentation.
+type Option func(*config)
+
+type config struct {
+	meters  metric.MeterProvider
+	tracers trace.TracerProvider
+	links   bool
+}
+
+// WithMeterProvider reports the metrics to mp instead of the global provider.
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return func(c *config) {
+		c.meters = mp
+	}
+}
+
+// WithTracerProvider traces every event published and consumed through the
+// wrappers with tp. Without it events carry their context but no spans are
+// started.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracers = tp
+	}
+}
+
+// WithLinks starts the consumer spans of an event as new traces linked to its
+// publish span instead of as its children, for stages that should not extend
+// the publisher's trace.
+func WithLinks() Option {
+	return func(c *config) {
+		c.links = true
+	}
+}
+
+func newConfig(opts []Option) config {
+	c := config{meters: otel.GetMeterProvider()}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+// DisruptorSource is implemented by ring.IDisruptor.
+type DisruptorSource interface {
+	Stats() ring.Stats
+}
+
+// QueueSource is implemented by ring.IQueue.
+type QueueSource interface {
+	Stats() ring.QueueStats
+}
+
+// instruments creates observable instruments and collects their errors.
+type instruments struct {
+	meter metric.Meter
+	err   error
+}
+
+func (in *instruments) counter(name, unit, desc string) metric.Int64ObservableCounter {
+	res, err := in.meter.Int64ObservableCounter(name, metric.WithUnit(unit), metric.WithDescription(desc))
+	in.err = errors.Join(in.err, err)
+	return res
+}
+
+func (in *instruments) gauge(name, unit, desc string) metric.Int64ObservableGauge {
+	res, err := in.meter.Int64ObservableGauge(name, metric.WithUnit(unit), metric.WithDescription(desc))
+	in.err = errors.Join(in.err, err)
+	return res
+}
+
+func (in *instruments) ratio(name, desc string) metric.Float64ObservableGauge {
+	res, err := in.meter.Float64ObservableGauge(name, metric.WithUnit("1"), metric.WithDescription(desc))
+	in.err = errors.Join(in.err, err)
+	return res
+}
+
+// RegisterDisruptor reports the stats of d under name until the registration
+// is unregistered: the published events, whose rate is the publish rate,
+// failed enqueues, drops, utilization and every reader's lag.
+func RegisterDisruptor(name string, d DisruptorSource, opts ...Option) (metric.Registration, error) {
+	c := newConfig(opts)
+	in := instruments{meter: c.meters.Meter(scope)}
+	published := in.counter("ring.published", "{event}", "Events published to the disruptor.")
+	failures := in.counter("ring.enqueue.failures", "{enqueue}", "Rejected or abandoned enqueues.")
+	dropped := in.counter("ring.dropped", "{event}", "Events dropped by the slow-consumer policy.")
+	utilization := in.ratio("ring.utilization", "Fraction of the ring held by the slowest reader.")
+	lag := in.gauge("ring.reader.lag", "{event}", "Published events a reader has not passed yet.")
+	if in.err != nil {
+		return nil, in.err
+	}
+	ringName := attribute.String("ring.name", name)
+	attrs := metric.WithAttributes(ringName)
+	return in.meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		s := d.Stats()
+		o.ObserveInt64(published, int64(s.Writer), attrs)
+		o.ObserveInt64(failures, int64(s.FailedEnqueues), attrs)
+		o.ObserveInt64(dropped, int64(s.Dropped), attrs)
+		o.ObserveFloat64(utilization, s.Utilization, attrs)
+		for _, r := range s.Readers {
+			o.ObserveInt64(lag, int64(r.Lag), metric.WithAttributes(ringName,
+				attribute.Int64("ring.reader", int64(r.ID)), attribute.String("ring.group", r.Group)))
+		}
+		return nil
+	}, published, failures, dropped, utilization, lag)
+}
+
+// RegisterQueue reports the stats of q under name until the registration is
+// unregistered: its depth, enqueued and dequeued items and failed enqueues.
+func RegisterQueue(name string, q QueueSource, opts ...Option) (metric.Registration, error) {
+	c := newConfig(opts)
+	in := instruments{meter: c.meters.Meter(scope)}
+	depth := in.gauge("ring.queue.depth", "{item}", "Items in the queue.")
+	enqueued := in.counter("ring.queue.enqueued", "{item}", "Items enqueued.")
+	dequeued := in.counter("ring.queue.dequeued", "{item}", "Items dequeued.")
+	failures := in.counter("ring.enqueue.failures", "{enqueue}", "Rejected or abandoned enqueues.")
+	if in.err != nil {
+		return nil, in.err
+	}
+	attrs := metric.WithAttributes(attribute.String("ring.name", name))
+	return in.meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		s := q.Stats()
+		o.ObserveInt64(depth, int64(s.Len), attrs)
+		o.ObserveInt64(enqueued, int64(s.Enqueued), attrs)
+		o.ObserveInt64(dequeued, int64(s.Dequeued), attrs)
+		o.ObserveInt64(failures, int64(s.FailedEnqueues), attrs)
+		return nil
+	}, depth, enqueued, dequeued, failures)
+}
@@ -0,0 +1,218 @@
+package ringotel
+
+import (
+	"context"
+	"github.com/dk-open/ring"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"testing"
+)
+
+func collect(t *testing.T, reader *sdkmetric.ManualReader) map[string]metricdata.Aggregation {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	res := make(map[string]metricdata.Aggregation)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			res[m.Name] = m.Data
+		}
+	}
+	return res
+}
+
+func int64Value(t *testing.T, data metricdata.Aggregation, attr attribute.KeyValue) int64 {
+	t.Helper()
+	var points []metricdata.DataPoint[int64]
+	switch data := data.(type) {
+	case metricdata.Sum[int64]:
+		points = data.DataPoints
+	case metricdata.Gauge[int64]:
+		points = data.DataPoints
+	default:
+		t.Fatalf("Unexpected aggregation %T", data)
+	}
+	for _, p := range points {
+		if v, ok := p.Attributes.Value(attr.Key); ok && v == attr.Value {
+			return p.Value
+		}
+	}
+	t.Fatalf("No data point with %v", attr)
+	return 0
+}
+
+func TestWrapDisruptor(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	reader := sdkmetric.NewManualReader()
+	spans := tracetest.NewSpanRecorder()
+	opts := []Option{
+		WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+		WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))),
+	}
+	inner, err := ring.Disruptor[Envelope[int]](ctx, 8)
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	d, err := WrapDisruptor("orders", inner, opts...)
+	if err != nil {
+		t.Fatalf("WrapDisruptor failed: %v", err)
+	}
+	traces := make(chan trace.SpanContext, 3)
+	d.HandleWithGroup("journal", d.Handler(func(ctx context.Context, v int) {
+		traces <- trace.SpanContextFromContext(ctx)
+	}))
+
+	parent, span := sdktrace.NewTracerProvider().Tracer("test").Start(ctx, "request")
+	for i := 0; i < 3; i++ {
+		if err := d.Publish(parent, i); err != nil {
+			t.Fatalf("Publish failed: %v", err)
+		}
+	}
+	span.End()
+	for i := 0; i < 3; i++ {
+		if got := <-traces; got.TraceID() != span.SpanContext().TraceID() {
+			t.Errorf("Expected the handler to continue trace %v, got %v", span.SpanContext().TraceID(), got.TraceID())
+		}
+	}
+
+	metrics := collect(t, reader)
+	if got := int64Value(t, metrics["ring.published"], attribute.String("ring.name", "orders")); got != 3 {
+		t.Errorf("Expected 3 published events, got %d", got)
+	}
+	if _, ok := metrics["ring.reader.lag"]; !ok {
+		t.Error("Expected the reader lag to be reported")
+	}
+
+	if err := d.Close(ctx); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	var publish, process int
+	for _, s := range spans.Ended() {
+		switch s.Name() {
+		case "publish orders":
+			publish++
+		case "process orders":
+			process++
+			if s.Parent().TraceID() != span.SpanContext().TraceID() {
+				t.Errorf("Expected the process span to be part of the request trace")
+			}
+		}
+	}
+	if publish != 3 || process != 3 {
+		t.Errorf("Expected 3 publish and 3 process spans, got %d and %d", publish, process)
+	}
+	if metrics := collect(t, reader); len(metrics) != 0 {
+		t.Errorf("Expected no metrics after Close, got %v", metrics)
+	}
+}
+
+func TestRegisterDisruptor_ReaderID(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	reader := sdkmetric.NewManualReader()
+	d, err := ring.Disruptor[int](ctx, 8)
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	first, second := d.NewReader(), d.NewReader()
+	reg, err := RegisterDisruptor("orders", d, WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))))
+	if err != nil {
+		t.Fatalf("RegisterDisruptor failed: %v", err)
+	}
+	defer reg.Unregister()
+	for i := 0; i < 3; i++ {
+		d.Enqueue(i)
+	}
+	second.Dequeue()
+
+	// The second reader keeps its series once the first one is gone
+	first.Close()
+	lag := collect(t, reader)["ring.reader.lag"]
+	if got := int64Value(t, lag, attribute.Int64("ring.reader", 1)); got != 2 {
+		t.Errorf("Expected a lag of 2 for reader 1, got %d", got)
+	}
+}
+
+func TestWrapQueue_Links(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	spans := tracetest.NewSpanRecorder()
+	inner, err := ring.Queue[Envelope[string]](4)
+	if err != nil {
+		t.Fatalf("Failed to create queue: %v", err)
+	}
+	q, err := WrapQueue("fills", inner, WithLinks(),
+		WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+		WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))))
+	if err != nil {
+		t.Fatalf("WrapQueue failed: %v", err)
+	}
+	defer q.Unregister()
+
+	if err := q.Publish(context.Background(), "a"); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+	if !q.Enqueue(Envelope[string]{Value: "b"}) {
+		t.Fatal("Enqueue failed")
+	}
+	metrics := collect(t, reader)
+	if got := int64Value(t, metrics["ring.queue.depth"], attribute.String("ring.name", "fills")); got != 2 {
+		t.Errorf("Expected a depth of 2, got %d", got)
+	}
+
+	ctx, v, ok := q.Receive()
+	if !ok || v != "a" {
+		t.Fatalf("Expected a, got %q %v", v, ok)
+	}
+	var published trace.SpanContext
+	for _, s := range spans.Ended() {
+		if s.Name() == "publish fills" {
+			published = s.SpanContext()
+		}
+	}
+	receive := trace.SpanContextFromContext(ctx)
+	if !receive.IsValid() || receive.TraceID() == published.TraceID() {
+		t.Fatalf("Expected the receive span to start a new trace")
+	}
+	for _, s := range spans.Ended() {
+		if s.Name() == "receive fills" {
+			if links := s.Links(); len(links) != 1 || links[0].SpanContext.SpanID() != published.SpanID() {
+				t.Errorf("Expected the receive span to link the publish span, got %v", links)
+			}
+		}
+	}
+
+	// Items enqueued without a context are received with the background one
+	if ctx, v, ok := q.Receive(); !ok || v != "b" || ctx == nil {
+		t.Fatalf("Expected b with a context, got %q %v %v", v, ok, ctx)
+	}
+}
+
+func TestWrap_WithoutTracing(t *testing.T) {
+	inner, err := ring.Queue[Envelope[int]](4)
+	if err != nil {
+		t.Fatalf("Failed to create queue: %v", err)
+	}
+	q, err := WrapQueue("plain", inner, WithMeterProvider(sdkmetric.NewMeterProvider()))
+	if err != nil {
+		t.Fatalf("WrapQueue failed: %v", err)
+	}
+	defer q.Unregister()
+
+	type key struct{}
+	if err := q.Publish(context.WithValue(context.Background(), key{}, "v"), 1); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+	ctx, _, ok := q.Receive()
+	if !ok || ctx.Value(key{}) != "v" {
+		t.Errorf("Expected the publisher's context to be carried without tracing")
+	}
+}
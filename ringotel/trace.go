@@ -0,0 +1,164 @@
+package ringotel
+
+import (
+	"context"
+	"errors"
+	"github.com/dk-open/ring"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Envelope carries an event through a ring together with the context it was
+// published in, so a stage consuming it continues the publisher's trace.
+type Envelope[T any] struct {
+	Ctx   context.Context
+	Value T
+}
+
+// context returns the envelope's context, events published without one get
+// the background context.
+func (e Envelope[T]) context() context.Context {
+	if e.Ctx == nil {
+		return context.Background()
+	}
+	return e.Ctx
+}
+
+// tracing starts the spans of a wrapped ring, a nil tracer starts none.
+type tracing struct {
+	tracer trace.Tracer
+	name   string
+	links  bool
+}
+
+func newTracing(name string, c config) tracing {
+	res := tracing{name: name, links: c.links}
+	if c.tracers != nil {
+		res.tracer = c.tracers.Tracer(scope)
+	}
+	return res
+}
+
+func (t tracing) start(ctx context.Context, op string, kind trace.SpanKind, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	opts = append(opts, trace.WithSpanKind(kind), trace.WithAttributes(
+		attribute.String("messaging.system", "ring"),
+		attribute.String("messaging.destination.name", t.name),
+		attribute.String("messaging.operation.type", op),
+	))
+	return t.tracer.Start(ctx, op+" "+t.name, opts...)
+}
+
+// publish wraps an enqueue of v in a producer span.
+func (t tracing) publish(ctx context.Context, enqueue func(context.Context) error) error {
+	if t.tracer == nil {
+		return enqueue(ctx)
+	}
+	ctx, span := t.start(ctx, "publish", trace.SpanKindProducer)
+	defer span.End()
+	err := enqueue(ctx)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return err
+}
+
+// consume starts the consumer span of an event published in ctx, as a child
+// or, with WithLinks, as a new trace linked to it.
+func (t tracing) consume(ctx context.Context, op string) (context.Context, trace.Span) {
+	if t.tracer == nil {
+		return ctx, nil
+	}
+	if t.links {
+		return t.start(ctx, op, trace.SpanKindConsumer, trace.WithNewRoot(),
+			trace.WithLinks(trace.LinkFromContext(ctx)))
+	}
+	return t.start(ctx, op, trace.SpanKindConsumer)
+}
+
+// Disruptor wraps a disruptor of envelopes. It reports the disruptor's metrics
+// until it is closed and traces the events published with Publish through
+// the readers created with Handler.
+type Disruptor[T any] struct {
+	ring.IDisruptor[Envelope[T]]
+	tracing
+	reg metric.Registration
+}
+
+// WrapDisruptor instruments d under name.
+func WrapDisruptor[T any](name string, d ring.IDisruptor[Envelope[T]], opts ...Option) (*Disruptor[T], error) {
+	reg, err := RegisterDisruptor(name, d, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &Disruptor[T]{IDisruptor: d, tracing: newTracing(name, newConfig(opts)), reg: reg}, nil
+}
+
+// Publish publishes v with ctx like MustEnqueue, in a producer span.
+func (d *Disruptor[T]) Publish(ctx context.Context, v T) error {
+	return d.publish(ctx, func(ctx context.Context) error {
+		return d.MustEnqueue(Envelope[T]{Ctx: ctx, Value: v})
+	})
+}
+
+// Handler adapts f to a reader callback that runs it in a consumer span. f
+// receives the publisher's context carrying that span.
+func (d *Disruptor[T]) Handler(f func(ctx context.Context, v T)) ring.ReaderCallback[Envelope[T]] {
+	return func(e Envelope[T]) {
+		ctx, span := d.consume(e.context(), "process")
+		f(ctx, e.Value)
+		if span != nil {
+			span.End()
+		}
+	}
+}
+
+// Close closes the disruptor and stops reporting its metrics.
+func (d *Disruptor[T]) Close(ctx context.Context) error {
+	return errors.Join(d.IDisruptor.Close(ctx), d.reg.Unregister())
+}
+
+// Queue wraps a queue of envelopes. It reports the queue's metrics until it is
+// unregistered and traces the items published with Publish to Receive.
+type Queue[T any] struct {
+	ring.IQueue[Envelope[T]]
+	tracing
+	reg metric.Registration
+}
+
+// WrapQueue instruments q under name.
+func WrapQueue[T any](name string, q ring.IQueue[Envelope[T]], opts ...Option) (*Queue[T], error) {
+	reg, err := RegisterQueue(name, q, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &Queue[T]{IQueue: q, tracing: newTracing(name, newConfig(opts)), reg: reg}, nil
+}
+
+// Publish enqueues v with ctx like MustEnqueue, in a producer span.
+func (q *Queue[T]) Publish(ctx context.Context, v T) error {
+	return q.publish(ctx, func(ctx context.Context) error {
+		return q.MustEnqueue(Envelope[T]{Ctx: ctx, Value: v})
+	})
+}
+
+// Receive dequeues the next item like Dequeue. The returned context carries
+// the publisher's trace and the ended receive span.
+func (q *Queue[T]) Receive() (ctx context.Context, v T, ok bool) {
+	e, ok := q.Dequeue()
+	if !ok {
+		return nil, v, false
+	}
+	ctx, span := q.consume(e.context(), "receive")
+	if span != nil {
+		span.End()
+	}
+	return ctx, e.Value, true
+}
+
+// Unregister stops reporting the queue's metrics.
+func (q *Queue[T]) Unregister() error {
+	return q.reg.Unregister()
+}
@@ -0,0 +1,125 @@
+// Package sink provides ready-made disruptor readers writing events to
+// external systems.
+package sink
+
+import (
+	"context"
+	"database/sql"
+	"github.com/dk-open/ring"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Execer is implemented by *sql.DB, *sql.Conn and *sql.Tx.
+type Execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// StmtBuilder builds a single statement writing the whole batch.
+type StmtBuilder[T any] func(batch []T) (query string, args []any)
+
+// SQL is a reader batching events into multi-row statements. A batch is
+// written once it holds maxBatch events or maxDelay after its first event.
+// Failed batches are retried and dead-lettered according to the policy, the
+// reader blocks while a batch is written so a slow database holds back the
+// producers instead of buffering without bound.
+//
+// Events are released from the ring when they are added to a batch. Call
+// Close before closing the process to write the pending batch.
+type SQL[T any] struct {
+	ctx      context.Context
+	db       Execer
+	build    StmtBuilder[T]
+	maxBatch int
+	maxDelay time.Duration
+	write    ring.ReaderCallback[[]T]
+
+	mu    sync.Mutex
+	batch []T
+	timer *time.Timer
+}
+
+// SQLBatch creates a batching SQL sink. Use its Handle method as the reader:
+//
+//	s := sink.SQLBatch(ctx, db, sink.Insert("events", cols, values), 500, 10*time.Millisecond, policy)
+//	d.HandleWith(s.Handle)
+func SQLBatch[T any](ctx context.Context, db Execer, build StmtBuilder[T], maxBatch int, maxDelay time.Duration, policy ring.ErrorPolicy[[]T]) *SQL[T] {
+	if maxBatch < 1 {
+		maxBatch = 1
+	}
+	s := &SQL[T]{
+		ctx:      ctx,
+		db:       db,
+		build:    build,
+		maxBatch: maxBatch,
+		maxDelay: maxDelay,
+		batch:    make([]T, 0, maxBatch),
+	}
+	s.write = ring.WithErrorHandler(s.exec, policy)
+	return s
+}
+
+// Handle adds an event to the current batch.
+func (s *SQL[T]) Handle(value T) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.batch = append(s.batch, value)
+	switch {
+	case len(s.batch) >= s.maxBatch:
+		s.flush()
+	case len(s.batch) == 1 && s.maxDelay > 0:
+		s.timer = time.AfterFunc(s.maxDelay, s.Flush)
+	}
+}
+
+// Flush writes the pending batch.
+func (s *SQL[T]) Flush() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.flush()
+}
+
+// Close writes the pending batch. The sink must not be used afterwards.
+func (s *SQL[T]) Close() {
+	s.Flush()
+}
+
+func (s *SQL[T]) flush() {
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	if len(s.batch) == 0 {
+		return
+	}
+	batch := s.batch
+	s.batch = make([]T, 0, s.maxBatch)
+	s.write(batch)
+}
+
+func (s *SQL[T]) exec(batch []T) error {
+	query, args := s.build(batch)
+	_, err := s.db.ExecContext(s.ctx, query, args...)
+	return err
+}
+
+// Insert builds multi-row INSERT statements with ? placeholders, e.g. for
+// MySQL and SQLite. values returns the column values of an event.
+func Insert[T any](table string, columns []string, values func(T) []any) StmtBuilder[T] {
+	row := "(" + strings.TrimSuffix(strings.Repeat("?,", len(columns)), ",") + ")"
+	prefix := "INSERT INTO " + table + " (" + strings.Join(columns, ", ") + ") VALUES "
+	return func(batch []T) (string, []any) {
+		var b strings.Builder
+		b.WriteString(prefix)
+		args := make([]any, 0, len(batch)*len(columns))
+		for i, v := range batch {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(row)
+			args = append(args, values(v)...)
+		}
+		return b.String(), args
+	}
+}
@@ -0,0 +1,118 @@
+package sink
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/dk-open/ring"
+	"sync"
+	"testing"
+	"time"
+)
+
+type execCall struct {
+	query string
+	args  []any
+}
+
+type fakeDB struct {
+	mu    sync.Mutex
+	calls []execCall
+	fail  int
+}
+
+func (f *fakeDB) ExecContext(_ context.Context, query string, args ...any) (sql.Result, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, execCall{query: query, args: args})
+	if f.fail > 0 {
+		f.fail--
+		return nil, errors.New("deadlock detected")
+	}
+	return nil, nil
+}
+
+func (f *fakeDB) Calls() []execCall {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]execCall(nil), f.calls...)
+}
+
+var insertPair = Insert("events", []string{"id", "double"}, func(v int) []any { return []any{v, v * 2} })
+
+func TestSQLBatch_MaxBatch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	db := &fakeDB{}
+	s := SQLBatch(ctx, db, insertPair, 3, time.Hour, ring.ErrorPolicy[[]int]{})
+	d, err := ring.Disruptor[int](ctx, 8, s.Handle)
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	for i := 0; i < 7; i++ {
+		if err := d.MustEnqueue(i); err != nil {
+			t.Fatalf("MustEnqueue failed: %v", err)
+		}
+	}
+	if err := d.Close(context.Background()); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	s.Close()
+
+	calls := db.Calls()
+	if len(calls) != 3 {
+		t.Fatalf("Expected 3 statements, got %d", len(calls))
+	}
+	if want := "INSERT INTO events (id, double) VALUES (?,?),(?,?),(?,?)"; calls[0].query != want {
+		t.Errorf("Expected %q, got %q", want, calls[0].query)
+	}
+	if len(calls[0].args) != 6 || calls[0].args[5] != 4 {
+		t.Errorf("Unexpected args %v", calls[0].args)
+	}
+	if len(calls[2].args) != 2 || calls[2].args[0] != 6 {
+		t.Errorf("Expected the remaining event on Close, got %v", calls[2].args)
+	}
+}
+
+func TestSQLBatch_MaxDelay(t *testing.T) {
+	db := &fakeDB{}
+	s := SQLBatch(context.Background(), db, insertPair, 100, 5*time.Millisecond, ring.ErrorPolicy[[]int]{})
+	s.Handle(1)
+	s.Handle(2)
+
+	deadline := time.Now().Add(time.Second)
+	for len(db.Calls()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	calls := db.Calls()
+	if len(calls) != 1 || len(calls[0].args) != 4 {
+		t.Fatalf("Expected one delayed statement with 2 rows, got %v", calls)
+	}
+}
+
+func TestSQLBatch_RetryAndDeadLetter(t *testing.T) {
+	db := &fakeDB{fail: 4}
+	var dead [][]int
+	var errs int
+	s := SQLBatch(context.Background(), db, insertPair, 2, 0, ring.ErrorPolicy[[]int]{
+		Action:     ring.RetryEvent,
+		Retries:    2,
+		OnError:    func([]int, error) { errs++ },
+		DeadLetter: func(batch []int) { dead = append(dead, batch) },
+	})
+	s.Handle(1)
+	s.Handle(2) // fails three times and is dead-lettered
+	s.Handle(3)
+	s.Handle(4) // fails once and succeeds on retry
+
+	if len(dead) != 1 || dead[0][0] != 1 || dead[0][1] != 2 {
+		t.Errorf("Expected the first batch to be dead-lettered, got %v", dead)
+	}
+	if errs != 4 {
+		t.Errorf("Expected 4 errors, got %d", errs)
+	}
+	if n := len(db.Calls()); n != 5 {
+		t.Errorf("Expected 5 statements, got %d", n)
+	}
+}
@@ -0,0 +1,112 @@
+package snapshot
+
+import (
+	"context"
+	"github.com/dk-open/ring"
+	"github.com/dk-open/ring/pad"
+	"runtime"
+	"sync/atomic"
+)
+
+// Snapshot is a coalesced state covering the first Seq deltas.
+type Snapshot[S any] struct {
+	Seq   uint64
+	State S
+}
+
+type entry[D any] struct {
+	seq   uint64
+	delta D
+}
+
+// Publisher distributes deltas through a disruptor while a coalescing reader
+// keeps a snapshot for late joiners. Apply must not mutate the state it is
+// given, published snapshots are shared with subscribers.
+type Publisher[S, D any] struct {
+	d         ring.IDisruptor[entry[D]]
+	apply     func(state S, delta D) S
+	every     uint64
+	next      uint64
+	published pad.AtomicUint64
+	latest    atomic.Pointer[Snapshot[S]]
+}
+
+// New creates a publisher that publishes a snapshot every n deltas and
+// whenever the coalescer catches up with the producer.
+func New[S, D any](ctx context.Context, capacity uint64, initial S, apply func(state S, delta D) S, every uint64) (*Publisher[S, D], error) {
+	d, err := ring.Disruptor[entry[D]](ctx, capacity)
+	if err != nil {
+		return nil, err
+	}
+	p := &Publisher[S, D]{d: d, apply: apply, every: every}
+	p.latest.Store(&Snapshot[S]{State: initial})
+
+	state := initial
+	d.HandleWith(func(e entry[D]) {
+		state = apply(state, e.delta)
+		applied := e.seq + 1
+		if (p.every > 0 && applied%p.every == 0) || applied == p.published.Load() {
+			p.latest.Store(&Snapshot[S]{Seq: applied, State: state})
+		}
+	})
+	return p, nil
+}
+
+// Publish sends a delta to all subscribers. It must be called from a single
+// producer goroutine.
+func (p *Publisher[S, D]) Publish(delta D) error {
+	seq := p.next
+	p.next++
+	// Counted before the enqueue so a subscriber never misses a delta that is
+	// already in the ring but not covered by its snapshot.
+	p.published.Store(p.next)
+	return p.d.MustEnqueue(entry[D]{seq: seq, delta: delta})
+}
+
+// Snapshot returns the latest coalesced snapshot.
+func (p *Publisher[S, D]) Snapshot() Snapshot[S] {
+	return *p.latest.Load()
+}
+
+// Subscribe delivers a consistent snapshot to onSnapshot followed by every
+// later delta to onDelta. onSnapshot returns before the first onDelta call.
+// ctx bounds the wait for the snapshot. The returned func unsubscribes: it
+// stops the subscriber's reader, which then no longer gates the publisher.
+func (p *Publisher[S, D]) Subscribe(ctx context.Context, onSnapshot func(state S), onDelta func(delta D)) (unsubscribe func(), err error) {
+	var from atomic.Pointer[uint64]
+	var cancelled atomic.Bool
+	g := p.d.HandleWith(func(e entry[D]) {
+		for from.Load() == nil && !cancelled.Load() {
+			runtime.Gosched()
+		}
+		if cancelled.Load() || e.seq < *from.Load() {
+			return
+		}
+		onDelta(e.delta)
+	})
+	h := g.Handles()[0]
+
+	// Deltas below n are either behind the reader or counted by the snapshot
+	n := p.published.Load()
+	for {
+		snap := p.latest.Load()
+		if snap.Seq >= n {
+			onSnapshot(snap.State)
+			from.Store(&snap.Seq)
+			return h.Close, nil
+		}
+		select {
+		case <-ctx.Done():
+			cancelled.Store(true)
+			h.Close()
+			return nil, ctx.Err()
+		default:
+			runtime.Gosched()
+		}
+	}
+}
+
+// Close drains the pending deltas and stops the subscribers.
+func (p *Publisher[S, D]) Close(ctx context.Context) error {
+	return p.d.Close(ctx)
+}
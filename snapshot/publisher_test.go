@@ -0,0 +1,131 @@
+package snapshot
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func add(state, delta int) int {
+	return state + delta
+}
+
+func TestPublisher_LateJoiner(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	p, err := New[int, int](ctx, 16, 0, add, 4)
+	if err != nil {
+		t.Fatalf("Failed to create publisher: %v", err)
+	}
+	for i := 1; i <= 10; i++ {
+		if err := p.Publish(i); err != nil {
+			t.Fatalf("Publish failed: %v", err)
+		}
+	}
+
+	var mu sync.Mutex
+	var state int
+	var deltas int
+	_, err = p.Subscribe(ctx, func(s int) {
+		mu.Lock()
+		state = s
+		mu.Unlock()
+	}, func(delta int) {
+		mu.Lock()
+		state = add(state, delta)
+		deltas++
+		mu.Unlock()
+	})
+	if err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+	for i := 11; i <= 20; i++ {
+		if err := p.Publish(i); err != nil {
+			t.Fatalf("Publish failed: %v", err)
+		}
+	}
+	if err := p.Close(context.Background()); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if state != 210 {
+		t.Errorf("Expected subscriber state 210, got %d", state)
+	}
+	if deltas < 10 {
+		t.Errorf("Expected at least 10 deltas after subscribing, got %d", deltas)
+	}
+	if snap := p.Snapshot(); snap.Seq != 20 || snap.State != 210 {
+		t.Errorf("Expected snapshot of 20 deltas with state 210, got %+v", snap)
+	}
+}
+
+func TestPublisher_SubscribeCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	block := make(chan struct{})
+	p, err := New[int, int](ctx, 8, 0, func(state, delta int) int {
+		<-block
+		return state + delta
+	}, 1)
+	if err != nil {
+		t.Fatalf("Failed to create publisher: %v", err)
+	}
+	if err := p.Publish(1); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+
+	subCtx, subCancel := context.WithCancel(ctx)
+	subCancel()
+	if _, err := p.Subscribe(subCtx, func(int) {}, func(int) {}); err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	close(block)
+	// The cancelled subscriber's reader exits, only the coalescer remains
+	waitReaders(t, p, 1)
+}
+
+func TestPublisher_Unsubscribe(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	p, err := New[int, int](ctx, 8, 0, add, 4)
+	if err != nil {
+		t.Fatalf("Failed to create publisher: %v", err)
+	}
+	var deltas atomic.Int64
+	unsubscribe, err := p.Subscribe(ctx, func(int) {}, func(int) { deltas.Add(1) })
+	if err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+	unsubscribe()
+	waitReaders(t, p, 1)
+	// The subscriber no longer gates the publisher
+	for i := 0; i < 20; i++ {
+		if err := p.Publish(i); err != nil {
+			t.Fatalf("Publish failed: %v", err)
+		}
+	}
+	if err := p.Close(context.Background()); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if n := deltas.Load(); n != 0 {
+		t.Errorf("Expected no deltas after unsubscribing, got %d", n)
+	}
+}
+
+func waitReaders(t *testing.T, p *Publisher[int, int], want int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for len(p.d.Stats().Readers) != want {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected %d readers, got %d", want, len(p.d.Stats().Readers))
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
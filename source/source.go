@@ -0,0 +1,82 @@
+// Package source feeds events from external ordered logs into a disruptor and
+// acknowledges them upstream once the ring is done with them.
+package source
+
+import (
+	"context"
+	"github.com/dk-open/ring"
+)
+
+// Message is a record of an external log with the offset that acknowledges it.
+type Message[T any] struct {
+	Offset int64
+	Value  T
+}
+
+// Consumer adapts an external consumer, e.g. a Kafka partition consumer.
+type Consumer[T any] interface {
+	// Poll returns the next messages in offset order. It should return, even
+	// empty-handed, at a regular interval so pending offsets get committed.
+	Poll(ctx context.Context) ([]Message[T], error)
+	// Commit acknowledges every message up to and including offset.
+	Commit(ctx context.Context, offset int64) error
+}
+
+type mark struct {
+	published uint64
+	offset    int64
+}
+
+// Run feeds messages from c into d until ctx is done or c fails. An offset is
+// committed only after every gating reader of d has passed the events up to
+// it, so a restart resumes no later than the first unprocessed message.
+//
+// Dropped events would be acknowledged as well, d should use the default
+// Block slow-consumer policy.
+func Run[T any](ctx context.Context, c Consumer[T], d ring.IDisruptor[T]) error {
+	var pending []mark
+	committed := false
+	var last int64
+	commit := func(ctx context.Context) error {
+		released := d.Released()
+		n := 0
+		for n < len(pending) && pending[n].published <= released {
+			n++
+		}
+		if n == 0 {
+			return nil
+		}
+		offset := pending[n-1].offset
+		pending = pending[n:]
+		if committed && offset <= last {
+			return nil
+		}
+		if err := c.Commit(ctx, offset); err != nil {
+			return err
+		}
+		committed, last = true, offset
+		return nil
+	}
+
+	for {
+		msgs, err := c.Poll(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return commit(context.WithoutCancel(ctx))
+			}
+			return err
+		}
+		for _, m := range msgs {
+			if err := d.MustEnqueue(m.Value); err != nil {
+				return err
+			}
+		}
+		if len(msgs) > 0 {
+			// Counted after the enqueue, events of other producers only delay the commit
+			pending = append(pending, mark{published: d.Published(), offset: msgs[len(msgs)-1].Offset})
+		}
+		if err := commit(ctx); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package source
+
+import (
+	"context"
+	"github.com/dk-open/ring"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeConsumer struct {
+	mu        sync.Mutex
+	batches   [][]Message[int]
+	committed []int64
+}
+
+func (f *fakeConsumer) Poll(ctx context.Context) ([]Message[int], error) {
+	f.mu.Lock()
+	if len(f.batches) > 0 {
+		b := f.batches[0]
+		f.batches = f.batches[1:]
+		f.mu.Unlock()
+		return b, nil
+	}
+	f.mu.Unlock()
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(time.Millisecond):
+		return nil, nil
+	}
+}
+
+func (f *fakeConsumer) Commit(_ context.Context, offset int64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.committed = append(f.committed, offset)
+	return nil
+}
+
+func (f *fakeConsumer) Committed() []int64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]int64(nil), f.committed...)
+}
+
+func TestRun_CommitsAfterReaders(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	c := &fakeConsumer{}
+	for b := 0; b < 2; b++ {
+		var batch []Message[int]
+		for i := 0; i < 5; i++ {
+			batch = append(batch, Message[int]{Offset: int64(100 + b*5 + i), Value: b*5 + i})
+		}
+		c.batches = append(c.batches, batch)
+	}
+
+	release := make(chan struct{})
+	d, err := ring.Disruptor[int](ctx, 16, func(int) { <-release })
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- Run[int](ctx, c, d) }()
+
+	time.Sleep(20 * time.Millisecond)
+	if got := c.Committed(); len(got) != 0 {
+		t.Fatalf("Expected no commits while the reader is blocked, got %v", got)
+	}
+
+	close(release)
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if got := c.Committed(); len(got) > 0 && got[len(got)-1] == 109 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	got := c.Committed()
+	if len(got) == 0 || got[len(got)-1] != 109 {
+		t.Fatalf("Expected offset 109 to be committed, got %v", got)
+	}
+	for i := 1; i < len(got); i++ {
+		if got[i] <= got[i-1] {
+			t.Errorf("Expected increasing commits, got %v", got)
+		}
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Expected Run to stop cleanly, got %v", err)
+	}
+}
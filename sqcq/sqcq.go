@@ -0,0 +1,135 @@
+// Package sqcq pairs a submission ring with a completion ring, in the style of
+// io_uring: callers submit requests tagged with a token, a worker pool handles
+// them and publishes completions carrying the same token into the paired
+// ring, where callers reap them.
+package sqcq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/dk-open/ring"
+)
+
+var ErrPanicked = errors.New("sqcq: handler panicked")
+
+// Request is a submitted request with the caller's token.
+type Request[Q any] struct {
+	Token uint64
+	Value Q
+}
+
+// Completion is the result of the request with the same token.
+type Completion[R any] struct {
+	Token uint64
+	Value R
+	Err   error
+}
+
+// Option configures a pair created by New.
+type Option[R any] func(*options[R])
+
+type options[R any] struct {
+	onComplete func(c Completion[R])
+}
+
+// WithCompletionHandler delivers completions to f on a reader goroutine of the
+// completion ring instead of keeping them for Reap.
+func WithCompletionHandler[R any](f func(c Completion[R])) Option[R] {
+	return func(o *options[R]) {
+		o.onComplete = f
+	}
+}
+
+// Pair is a running submission and completion ring pair.
+type Pair[Q, R any] struct {
+	sq     ring.IDisruptor[Request[Q]]
+	cq     ring.IDisruptor[Completion[R]]
+	reaper ring.IDisruptorRing[Completion[R]]
+}
+
+// New starts workers goroutines handling the submitted requests. Both rings
+// have capacity slots, a power of two. Workers wait while the completion ring
+// is full, so a caller that stops reaping eventually blocks its own Submit.
+// A panic of the handler completes the request with ErrPanicked.
+func New[Q, R any](capacity uint64, workers int, handler func(req Q) (R, error), opts ...Option[R]) (*Pair[Q, R], error) {
+	var o options[R]
+	for _, opt := range opts {
+		opt(&o)
+	}
+	cq, err := ring.NewDisruptor[Completion[R]](context.Background(), ring.WithCapacity(capacity), ring.WithBlockingWait())
+	if err != nil {
+		return nil, err
+	}
+	sq, err := ring.NewDisruptor[Request[Q]](context.Background(), ring.WithCapacity(capacity), ring.WithBlockingWait())
+	if err != nil {
+		return nil, err
+	}
+	p := &Pair[Q, R]{sq: sq, cq: cq}
+	if o.onComplete != nil {
+		cq.HandleWith(o.onComplete)
+	} else {
+		p.reaper = cq.NewReader()
+	}
+	worker := func(req Request[Q]) {
+		v, err := call(handler, req.Value)
+		_ = cq.MustEnqueue(Completion[R]{Token: req.Token, Value: v, Err: err})
+	}
+	pool := make([]ring.ReaderCallback[Request[Q]], max(workers, 1))
+	for i := range pool {
+		pool[i] = worker
+	}
+	sq.HandleWithWorkerPool(pool...)
+	return p, nil
+}
+
+func call[Q, R any](handler func(req Q) (R, error), req Q) (v R, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %v", ErrPanicked, r)
+		}
+	}()
+	return handler(req)
+}
+
+// Submit queues req under token, waiting while the submission ring is full.
+// It fails with ring.ErrClosed once the pair is closed.
+func (p *Pair[Q, R]) Submit(token uint64, req Q) error {
+	return p.sq.MustEnqueue(Request[Q]{Token: token, Value: req})
+}
+
+// TrySubmit queues req under token unless the submission ring is full or the
+// pair is closed.
+func (p *Pair[Q, R]) TrySubmit(token uint64, req Q) bool {
+	return p.sq.Enqueue(Request[Q]{Token: token, Value: req})
+}
+
+// Reap returns the next completion. Completions arrive in the order workers
+// finish, callers correlate them by token. Reap must be called from a single
+// goroutine and never returns completions of a pair with a completion
+// handler.
+func (p *Pair[Q, R]) Reap() (Completion[R], bool) {
+	if p.reaper == nil {
+		return Completion[R]{}, false
+	}
+	return p.reaper.Dequeue()
+}
+
+// Pending returns the number of submitted requests not yet completed.
+func (p *Pair[Q, R]) Pending() uint64 {
+	return p.sq.Published() - p.sq.Released()
+}
+
+// Close stops accepting requests and waits until the submitted ones are
+// completed, and with a completion handler delivered. Completions left for
+// Reap stay available. If ctx is done first the pending requests are
+// abandoned.
+func (p *Pair[Q, R]) Close(ctx context.Context) error {
+	if err := p.sq.Close(ctx); err != nil {
+		return err
+	}
+	if p.reaper != nil {
+		return nil
+	}
+	return p.cq.Close(ctx)
+}
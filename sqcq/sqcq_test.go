@@ -0,0 +1,91 @@
+package sqcq
+
+import (
+	"context"
+	"errors"
+	"github.com/dk-open/ring"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func parse(s string) (int, error) {
+	if s == "panic" {
+		panic("bad request")
+	}
+	return strconv.Atoi(s)
+}
+
+func TestPair_Reap(t *testing.T) {
+	p, err := New(16, 4, parse)
+	if err != nil {
+		t.Fatalf("Failed to create pair: %v", err)
+	}
+	requests := map[uint64]string{1: "10", 2: "x", 3: "panic", 4: "40"}
+	for token, req := range requests {
+		if err := p.Submit(token, req); err != nil {
+			t.Fatalf("Submit failed: %v", err)
+		}
+	}
+	got := map[uint64]Completion[int]{}
+	deadline := time.Now().Add(time.Second)
+	for len(got) < len(requests) {
+		c, ok := p.Reap()
+		if !ok {
+			if time.Now().After(deadline) {
+				t.Fatalf("Timed out with %v", got)
+			}
+			time.Sleep(time.Millisecond)
+			continue
+		}
+		got[c.Token] = c
+	}
+	if got[1].Value != 10 || got[1].Err != nil || got[4].Value != 40 {
+		t.Errorf("Unexpected completions %v", got)
+	}
+	if got[2].Err == nil {
+		t.Error("Expected the handler error for token 2")
+	}
+	if !errors.Is(got[3].Err, ErrPanicked) {
+		t.Errorf("Expected ErrPanicked for token 3, got %v", got[3].Err)
+	}
+	if err := p.Close(context.Background()); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if err := p.Submit(5, "50"); !errors.Is(err, ring.ErrClosed) {
+		t.Errorf("Expected ErrClosed, got %v", err)
+	}
+}
+
+func TestPair_CompletionHandler(t *testing.T) {
+	var mu sync.Mutex
+	got := map[uint64]int{}
+	p, err := New(8, 2, parse, WithCompletionHandler(func(c Completion[int]) {
+		mu.Lock()
+		defer mu.Unlock()
+		got[c.Token] = c.Value
+	}))
+	if err != nil {
+		t.Fatalf("Failed to create pair: %v", err)
+	}
+	// More requests than both rings hold
+	for i := uint64(0); i < 100; i++ {
+		if err := p.Submit(i, strconv.Itoa(int(i)*2)); err != nil {
+			t.Fatalf("Submit failed: %v", err)
+		}
+	}
+	if err := p.Close(context.Background()); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if _, ok := p.Reap(); ok {
+		t.Error("Expected no completions to reap with a completion handler")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	for i := uint64(0); i < 100; i++ {
+		if got[i] != int(i)*2 {
+			t.Fatalf("Expected %d for token %d, got %d", i*2, i, got[i])
+		}
+	}
+}
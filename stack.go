@@ -0,0 +1,143 @@
+package ring
+
+import (
+	"context"
+	"fmt"
+	"github.com/dk-open/ring/pad"
+	"sync/atomic"
+)
+
+// Stack is a bounded lock-free LIFO for freelist-style reuse, where the item
+// released last is the one most likely still in cache. Any number of
+// goroutines may push and pop concurrently.
+type Stack[T any] struct {
+	// top and free head the list of stacked nodes and the list of free nodes.
+	// A head holds the node's index plus one in the low 32 bits, 0 for an
+	// empty list, and a version in the high 32 bits against ABA.
+	top   pad.AtomicUint64
+	free  pad.AtomicUint64
+	len   pad.AtomicInt64
+	nodes []stackNode[T]
+	retry retryPolicy
+	ctx   context.Context
+}
+
+// stackNode is owned by the goroutine that took it off a list until it is
+// linked into the other one, only next is read concurrently.
+type stackNode[T any] struct {
+	next  atomic.Uint32
+	value T
+}
+
+// NewStack creates a stack of the given capacity, a power of two no larger
+// than 1<<30. Of the queue options only the retry options and
+// WithQueueContext apply, they bound MustPush.
+func NewStack[T any](capacity uint64, opts ...QueueOption) (*Stack[T], error) {
+	if err := validCapacity(capacity); err != nil {
+		return nil, err
+	}
+	if capacity > maxPackedCapacity {
+		return nil, fmt.Errorf("%w: stack of %d items", ErrCapacity, capacity)
+	}
+	var o queueOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	res := &Stack[T]{
+		nodes: make([]stackNode[T], capacity),
+		retry: o.retry,
+		ctx:   o.ctx,
+	}
+	// Every node starts on the free list, the last one ends it
+	for i := range len(res.nodes) - 1 {
+		res.nodes[i].next.Store(uint32(i + 2))
+	}
+	res.free.Store(1)
+	return res, nil
+}
+
+// Push adds item on top. It reports false if the stack is full, which includes
+// nodes still being copied out by concurrent pops.
+func (s *Stack[T]) Push(item T) bool {
+	ref := s.unlink(&s.free)
+	if ref == 0 {
+		return false
+	}
+	s.nodes[ref-1].value = item
+	s.link(&s.top, ref)
+	s.len.Add(1)
+	return true
+}
+
+// MustPush pushes item, backing off while the stack is full. It gives up as
+// the retry options of NewStack say, with an error matching ErrFull.
+func (s *Stack[T]) MustPush(item T) error {
+	r := retry{policy: s.retry, ctx: s.ctx}
+	for !s.Push(item) {
+		if err := r.backoff(true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Pop takes the item pushed last.
+func (s *Stack[T]) Pop() (res T, ok bool) {
+	ref := s.unlink(&s.top)
+	if ref == 0 {
+		return res, false
+	}
+	node := &s.nodes[ref-1]
+	res = node.value
+	var zero T
+	node.value = zero
+	s.len.Add(-1)
+	s.link(&s.free, ref)
+	return res, true
+}
+
+// Len returns the number of items, which may change at any time.
+func (s *Stack[T]) Len() int {
+	return int(max(s.len.Load(), 0))
+}
+
+// Cap returns the capacity of the stack.
+func (s *Stack[T]) Cap() int {
+	return len(s.nodes)
+}
+
+// unlink takes the first node off list and returns its reference, 0 if the
+// list is empty.
+func (s *Stack[T]) unlink(list *pad.AtomicUint64) uint32 {
+	for attempt := uint64(0); ; attempt++ {
+		head := list.Load()
+		ref := uint32(head)
+		if ref == 0 {
+			return 0
+		}
+		// A stale next only matters if head is unchanged, the version rules
+		// that out
+		next := s.nodes[ref-1].next.Load()
+		if list.CompareAndSwap(head, versioned(head, next)) {
+			return ref
+		}
+		readerYield(attempt)
+	}
+}
+
+// link makes the node ref the first node of list.
+func (s *Stack[T]) link(list *pad.AtomicUint64, ref uint32) {
+	for attempt := uint64(0); ; attempt++ {
+		head := list.Load()
+		s.nodes[ref-1].next.Store(uint32(head))
+		if list.CompareAndSwap(head, versioned(head, ref)) {
+			return
+		}
+		readerYield(attempt)
+	}
+}
+
+// versioned replaces the reference of head and bumps its version.
+func versioned(head uint64, ref uint32) uint64 {
+	return (head>>32+1)<<32 | uint64(ref)
+}
@@ -0,0 +1,104 @@
+package ring
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestStack_LIFO(t *testing.T) {
+	s, err := NewStack[int](4)
+	if err != nil {
+		t.Fatalf("Failed to create stack: %v", err)
+	}
+	for i := 0; i < 4; i++ {
+		if !s.Push(i) {
+			t.Fatalf("Failed to push %d", i)
+		}
+	}
+	if s.Push(4) {
+		t.Error("Expected a push to a full stack to fail")
+	}
+	if got := s.Len(); got != s.Cap() {
+		t.Errorf("Expected a full stack, got %d items", got)
+	}
+	for want := 3; want >= 0; want-- {
+		if v, ok := s.Pop(); !ok || v != want {
+			t.Fatalf("Expected %d, got %d (ok=%v)", want, v, ok)
+		}
+	}
+	if _, ok := s.Pop(); ok {
+		t.Error("Expected a pop from an empty stack to fail")
+	}
+}
+
+func TestStack_MustPush(t *testing.T) {
+	s, err := NewStack[int](2, WithQueueMaxAttempts(3))
+	if err != nil {
+		t.Fatalf("Failed to create stack: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := s.MustPush(i); err != nil {
+			t.Fatalf("Failed to push %d: %v", i, err)
+		}
+	}
+	if err := s.MustPush(2); !errors.Is(err, ErrFull) || !errors.Is(err, ErrTooManyAttempts) {
+		t.Errorf("Expected ErrFull, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	blocked, _ := NewStack[int](1, WithQueueBlockForever(), WithQueueContext(ctx))
+	blocked.Push(0)
+	if err := blocked.MustPush(1); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestNewStack_Capacity(t *testing.T) {
+	for _, capacity := range []uint64{0, 3, maxPackedCapacity << 1} {
+		if _, err := NewStack[int](capacity); !errors.Is(err, ErrCapacity) {
+			t.Errorf("Expected ErrCapacity for %d, got %v", capacity, err)
+		}
+	}
+}
+
+func TestStack_Concurrent(t *testing.T) {
+	const workers, rounds = 4, 20000
+	s, err := NewStack[int](8)
+	if err != nil {
+		t.Fatalf("Failed to create stack: %v", err)
+	}
+	var pushed, popped atomic.Int64
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 1; i <= rounds; i++ {
+				if s.Push(i) {
+					pushed.Add(int64(i))
+				}
+				if v, ok := s.Pop(); ok {
+					popped.Add(int64(v))
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	for {
+		v, ok := s.Pop()
+		if !ok {
+			break
+		}
+		popped.Add(int64(v))
+	}
+	if pushed.Load() != popped.Load() {
+		t.Errorf("Expected every pushed item to be popped once, pushed %d popped %d", pushed.Load(), popped.Load())
+	}
+	if got := s.Len(); got != 0 {
+		t.Errorf("Expected an empty stack, got %d items", got)
+	}
+}
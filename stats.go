@@ -0,0 +1,161 @@
+package ring
+
+import (
+	"github.com/dk-open/ring/latency"
+	"sync"
+	"time"
+)
+
+// Stats is a point-in-time view of a disruptor. Sequences are counted in
+// events, the values are read without stopping producers or readers.
+type Stats struct {
+	// Name is the name given with WithName.
+	Name string
+	// Writer is the number of events published so far.
+	Writer uint64
+	// Readers holds the running readers in registration order.
+	Readers []ReaderStats
+	// FailedEnqueues counts Enqueue calls rejected because the ring was full
+	// or contended or by the rate limit and MustEnqueue calls that gave up.
+	FailedEnqueues uint64
+	// BackoffSleeps counts the times a producer slept waiting for readers.
+	BackoffSleeps uint64
+	// Dropped counts the events dropped by the slow-consumer policy.
+	Dropped uint64
+	// Reclaimed counts the retainer entries released after readers passed
+	// their events.
+	Reclaimed uint64
+	// Utilization is the fraction of the ring not yet released by the slowest
+	// gating reader.
+	Utilization float64
+}
+
+// ReaderStats describes a single reader.
+type ReaderStats struct {
+	// ID numbers the disruptor's readers in registration order, it is the
+	// reader label of the reader's goroutine in profiles.
+	ID uint64
+	// Group is the name of the reader's consumer group, if any.
+	Group string
+	// Sequence is the number of events the reader has passed.
+	Sequence uint64
+	// Lag is the number of published events the reader has not passed yet.
+	Lag uint64
+	// Gating reports whether the reader currently holds back producers.
+	Gating    bool
+	Processed uint64
+	Busy      time.Duration
+	// Latency is the live histogram of the time from publish until the
+	// reader's callback returned, nil unless WithLatencyTracking.
+	Latency *latency.Histogram
+}
+
+// QueueStats is a point-in-time view of a queue.
+type QueueStats struct {
+	Enqueued       uint64
+	Dequeued       uint64
+	Len            uint64
+	FailedEnqueues uint64
+	BackoffSleeps  uint64
+	// Contention counts enqueue attempts that lost the queue's head to
+	// another producer.
+	Contention uint64
+	// Utilization is the fraction of the queue's capacity in use.
+	Utilization float64
+	// Latency is the live histogram of the time from enqueue to dequeue, nil
+	// unless WithQueueLatencyTracking.
+	Latency *latency.Histogram
+}
+
+type statsSource interface {
+	stats(head uint64) ReaderStats
+}
+
+// readerRegistry lists the readers reported by Stats.
+type readerRegistry struct {
+	mu      sync.Mutex
+	readers []statsSource
+}
+
+func (r *readerRegistry) add(s statsSource) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.readers = append(r.readers, s)
+}
+
+func (r *readerRegistry) remove(s statsSource) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, v := range r.readers {
+		if v == s {
+			r.readers = append(r.readers[:i], r.readers[i+1:]...)
+			return
+		}
+	}
+}
+
+func (d *disruptor[T]) Stats() Stats {
+	head := d.writerCursor.Load() &^ 1
+	res := Stats{
+		Name:           d.name,
+		Writer:         head >> 1,
+		FailedEnqueues: d.failedEnqueues.Load(),
+		BackoffSleeps:  d.backoffSleeps.Load(),
+		Dropped:        d.drops.Load(),
+		Reclaimed:      d.reclaimed.Load(),
+	}
+	if barrier := d.readerBarrier.Load() &^ 1; barrier < head {
+		res.Utilization = float64((head-barrier)>>1) / float64(d.cap)
+	}
+	d.registry.mu.Lock()
+	defer d.registry.mu.Unlock()
+	res.Readers = make([]ReaderStats, len(d.registry.readers))
+	for i, r := range d.registry.readers {
+		res.Readers[i] = r.stats(head)
+	}
+	return res
+}
+
+func (r *disruptorReader[T]) stats(head uint64) ReaderStats {
+	tail := r.tail.Load()
+	res := ReaderStats{
+		ID:        r.id,
+		Group:     r.group,
+		Sequence:  tail >> 1,
+		Gating:    r.gating.Load(),
+		Processed: r.processed.Load(),
+		Busy:      r.Busy(),
+		Latency:   r.latency,
+	}
+	if tail < head {
+		res.Lag = (head - tail) >> 1
+	}
+	return res
+}
+
+func (r *pullReader[T]) stats(head uint64) ReaderStats {
+	tail := r.tail.Load()
+	res := ReaderStats{ID: r.id, Sequence: tail >> 1, Gating: true}
+	if tail < head {
+		res.Lag = (head - tail) >> 1
+	}
+	return res
+}
+
+func (q *queue[T]) Stats() QueueStats {
+	tail := q.tail.Load() &^ 1
+	head := q.head.Load() &^ 1
+	res := QueueStats{
+		Enqueued:       head >> 1,
+		Dequeued:       tail >> 1,
+		FailedEnqueues: q.failedEnqueues.Load(),
+		BackoffSleeps:  q.backoffSleeps.Load(),
+		Contention:     q.contention.Load(),
+		Latency:        q.latency,
+	}
+	if tail < head {
+		res.Len = (head - tail) >> 1
+	}
+	res.Utilization = float64(res.Len) / float64(q.cap)
+	return res
+}
@@ -0,0 +1,86 @@
+package ring
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestDisruptor_Stats(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	d, err := Disruptor[int](ctx, 8)
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	d.HandleWithGroup("audit", func(int) {})
+	pull := d.NewReader()
+
+	for i := 0; i < 8; i++ {
+		if !d.Enqueue(i) {
+			t.Fatalf("Enqueue %d failed", i)
+		}
+	}
+	// The pull reader holds the ring full
+	if d.Enqueue(8) {
+		t.Fatalf("Expected enqueue into a full ring to fail")
+	}
+	if _, ok := pull.Dequeue(); !ok {
+		t.Fatalf("Expected an event from the pull reader")
+	}
+
+	s := d.Stats()
+	for s.Readers[0].Lag > 0 {
+		time.Sleep(time.Millisecond)
+		s = d.Stats()
+	}
+	if s.Writer != 8 {
+		t.Errorf("Expected writer at 8, got %d", s.Writer)
+	}
+	if s.FailedEnqueues != 1 {
+		t.Errorf("Expected 1 failed enqueue, got %d", s.FailedEnqueues)
+	}
+	if len(s.Readers) != 2 {
+		t.Fatalf("Expected 2 readers, got %d", len(s.Readers))
+	}
+	if s.Readers[0].Group != "audit" || !s.Readers[0].Gating {
+		t.Errorf("Unexpected group reader stats: %+v", s.Readers[0])
+	}
+	if r := s.Readers[1]; r.Sequence != 1 || r.Lag != 7 {
+		t.Errorf("Expected pull reader at 1 with lag 7, got %+v", r)
+	}
+	if s.Utilization != 7.0/8 {
+		t.Errorf("Expected utilization 7/8, got %v", s.Utilization)
+	}
+
+	pull.Close()
+	if n := len(d.Stats().Readers); n != 1 {
+		t.Errorf("Expected closed reader to be dropped from stats, got %d readers", n)
+	}
+}
+
+func TestQueue_Stats(t *testing.T) {
+	q, err := Queue[int](4)
+	if err != nil {
+		t.Fatalf("Failed to create queue: %v", err)
+	}
+	for i := 0; i < 4; i++ {
+		q.Enqueue(i)
+	}
+	if q.Enqueue(4) {
+		t.Fatalf("Expected enqueue into a full queue to fail")
+	}
+	q.Dequeue()
+
+	s := q.Stats()
+	if s.Enqueued != 4 || s.Dequeued != 1 || s.Len != 3 {
+		t.Errorf("Unexpected counters: %+v", s)
+	}
+	if s.FailedEnqueues != 1 {
+		t.Errorf("Expected 1 failed enqueue, got %d", s.FailedEnqueues)
+	}
+	if s.Utilization != 0.75 {
+		t.Errorf("Expected utilization 0.75, got %v", s.Utilization)
+	}
+}
@@ -0,0 +1,91 @@
+package timerwheel
+
+import (
+	"cmp"
+	"context"
+	"github.com/dk-open/ring"
+	"slices"
+	"time"
+)
+
+// DelayQueue holds items until their fire time has arrived and then delivers
+// them ordered by fire time. Items are kept by value on the wheel, so a
+// queue in steady state does not allocate.
+type DelayQueue[T any] struct {
+	clock
+	ctx   context.Context
+	wheel wheel[delayed[T]]
+	ready ring.IQueue[T]
+}
+
+type delayed[T any] struct {
+	item T
+	at   time.Duration
+	tick uint64
+}
+
+func (d delayed[T]) due() uint64 { return d.tick }
+func (d delayed[T]) live() bool  { return true }
+
+// NewDelayQueue starts a delay queue whose wheel advances every tick until
+// ctx is done. Slots is the number of buckets per level and the capacity of
+// the rings items pass through, a power of two. Items are released with the
+// tick granularity; a consumer that falls a full ring behind holds the wheel
+// back until it catches up.
+func NewDelayQueue[T any](ctx context.Context, tick time.Duration, slots uint64) (*DelayQueue[T], error) {
+	c, err := newClock(tick)
+	if err != nil {
+		return nil, err
+	}
+	ready, err := ring.Queue[T](slots)
+	if err != nil {
+		return nil, err
+	}
+	q := &DelayQueue[T]{clock: c, ctx: ctx, ready: ready}
+	if err = q.wheel.init(slots, q.expire, func(delayed[T]) {}); err != nil {
+		return nil, err
+	}
+	go q.run(ctx, q.wheel.advance)
+	return q, nil
+}
+
+// Enqueue schedules item for fireAt and reports false if the ring of new
+// items is full.
+func (q *DelayQueue[T]) Enqueue(item T, fireAt time.Time) bool {
+	return q.wheel.pending.Enqueue(q.delayed(item, fireAt))
+}
+
+// MustEnqueue schedules item for fireAt, waiting for room in the ring of new
+// items.
+func (q *DelayQueue[T]) MustEnqueue(item T, fireAt time.Time) error {
+	return q.wheel.pending.MustEnqueue(q.delayed(item, fireAt))
+}
+
+// Dequeue returns the next item whose fire time has arrived.
+func (q *DelayQueue[T]) Dequeue() (T, bool) {
+	return q.ready.Dequeue()
+}
+
+// AsChan delivers the items as their fire time arrives until ctx is done.
+func (q *DelayQueue[T]) AsChan(ctx context.Context) <-chan T {
+	return ring.AsChan(ctx, q.ready)
+}
+
+func (q *DelayQueue[T]) delayed(item T, fireAt time.Time) delayed[T] {
+	at := fireAt.Sub(q.start)
+	return delayed[T]{item: item, at: at, tick: q.ticks(at)}
+}
+
+// expire releases a bucket in fire time order.
+func (q *DelayQueue[T]) expire(bucket []delayed[T]) {
+	slices.SortStableFunc(bucket, func(a, b delayed[T]) int {
+		return cmp.Compare(a.at, b.at)
+	})
+	for _, d := range bucket {
+		for q.ready.MustEnqueue(d.item) != nil {
+			if q.ctx.Err() != nil {
+				return
+			}
+		}
+	}
+}
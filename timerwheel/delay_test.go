@@ -0,0 +1,69 @@
+package timerwheel
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestDelayQueue_Order(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	q, err := NewDelayQueue[int](ctx, time.Millisecond, 8)
+	if err != nil {
+		t.Fatalf("Failed to create delay queue: %v", err)
+	}
+	start := time.Now()
+	// Delays beyond the first level and out of enqueue order
+	delays := []int{30, 2, 12, 0, 5}
+	for _, ms := range delays {
+		if !q.Enqueue(ms, start.Add(time.Duration(ms)*time.Millisecond)) {
+			t.Fatalf("Failed to enqueue %d", ms)
+		}
+	}
+	if v, ok := q.Dequeue(); ok {
+		t.Fatalf("Expected nothing before the first tick, got %d", v)
+	}
+
+	var got []int
+	deadline := time.After(time.Second)
+	for len(got) < len(delays) {
+		select {
+		case <-deadline:
+			t.Fatalf("Timed out with %v", got)
+		default:
+		}
+		v, ok := q.Dequeue()
+		if !ok {
+			time.Sleep(100 * time.Microsecond)
+			continue
+		}
+		if elapsed := time.Since(start); elapsed < time.Duration(v)*time.Millisecond {
+			t.Errorf("Item %d delivered early after %v", v, elapsed)
+		}
+		got = append(got, v)
+	}
+	for i, want := range []int{0, 2, 5, 12, 30} {
+		if got[i] != want {
+			t.Fatalf("Expected deadline order, got %v", got)
+		}
+	}
+}
+
+func TestDelayQueue_EnqueueAllocs(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	q, err := NewDelayQueue[int](ctx, time.Hour, 1024)
+	if err != nil {
+		t.Fatalf("Failed to create delay queue: %v", err)
+	}
+	fireAt := time.Now().Add(time.Minute)
+	allocs := testing.AllocsPerRun(100, func() {
+		q.Enqueue(1, fireAt)
+	})
+	if allocs != 0 {
+		t.Errorf("Expected Enqueue not to allocate, got %v allocs", allocs)
+	}
+}
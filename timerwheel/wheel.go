@@ -0,0 +1,241 @@
+// Package timerwheel schedules timeouts on a hierarchical timing wheel. A
+// single goroutine advances the wheel and publishes expired callbacks into a
+// disruptor, whose readers run them.
+package timerwheel
+
+import (
+	"context"
+	"fmt"
+	"github.com/dk-open/ring"
+	"github.com/dk-open/ring/pad"
+	"math/bits"
+	"sync/atomic"
+	"time"
+)
+
+// Levels is the number of wheels. Level i has slots buckets of slots^i ticks,
+// timers further out than the top level covers are re-placed as it turns.
+const Levels = 4
+
+const (
+	pending uint32 = iota
+	cancelled
+	fired
+)
+
+// Timer is a scheduled callback.
+type Timer struct {
+	deadline uint64
+	fn       func()
+	state    atomic.Uint32
+}
+
+// Cancel stops the timer and reports false if it already fired or was
+// cancelled before.
+func (t *Timer) Cancel() bool {
+	return t.state.CompareAndSwap(pending, cancelled)
+}
+
+func (t *Timer) due() uint64 { return t.deadline }
+func (t *Timer) live() bool  { return t.state.Load() != cancelled }
+
+// Wheel is a running timing wheel.
+type Wheel struct {
+	clock
+	wheel wheel[*Timer]
+	exec  ring.IDisruptor[func()]
+
+	fired     pad.AtomicUint64
+	cancelled pad.AtomicUint64
+	dropped   pad.AtomicUint64
+}
+
+// Stats counts the timers that fired and the cancelled timers dropped.
+// Dropped counts expired timers whose callback the execution disruptor
+// refused, e.g. because it was closed or its slow-consumer policy discarded
+// it; they are not counted as fired.
+type Stats struct {
+	Fired     uint64
+	Cancelled uint64
+	Dropped   uint64
+}
+
+// New starts a wheel advancing every tick until ctx is done. Slots is the
+// number of buckets per level, a power of two. Scheduled timers pass through
+// a ring queue of the same size, expired callbacks are published to exec.
+func New(ctx context.Context, tick time.Duration, slots uint64, exec ring.IDisruptor[func()]) (*Wheel, error) {
+	c, err := newClock(tick)
+	if err != nil {
+		return nil, err
+	}
+	w := &Wheel{clock: c, exec: exec}
+	if err = w.wheel.init(slots, w.expire, w.drop); err != nil {
+		return nil, err
+	}
+	go w.run(ctx, w.wheel.advance)
+	return w, nil
+}
+
+// Schedule calls fn on a reader of the execution disruptor once d has passed,
+// rounded up to the tick.
+func (w *Wheel) Schedule(d time.Duration, fn func()) (*Timer, error) {
+	t := &Timer{deadline: w.ticks(time.Since(w.start) + d), fn: fn}
+	if err := w.wheel.pending.MustEnqueue(t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (w *Wheel) Stats() Stats {
+	return Stats{Fired: w.fired.Load(), Cancelled: w.cancelled.Load(), Dropped: w.dropped.Load()}
+}
+
+func (w *Wheel) expire(timers []*Timer) {
+	for _, t := range timers {
+		if !t.state.CompareAndSwap(pending, fired) {
+			w.drop(t)
+			continue
+		}
+		if w.exec.MustEnqueue(t.fn) != nil {
+			w.dropped.Add(1)
+			continue
+		}
+		w.fired.Add(1)
+	}
+}
+
+func (w *Wheel) drop(*Timer) {
+	w.cancelled.Add(1)
+}
+
+// clock converts between wall time and wheel ticks.
+type clock struct {
+	tick  time.Duration
+	start time.Time
+}
+
+func newClock(tick time.Duration) (clock, error) {
+	if tick <= 0 {
+		return clock{}, fmt.Errorf("tick must be positive, got %v", tick)
+	}
+	return clock{tick: tick, start: time.Now()}, nil
+}
+
+// ticks rounds an offset from the start up to the tick.
+func (c clock) ticks(elapsed time.Duration) uint64 {
+	if elapsed <= 0 {
+		return 0
+	}
+	return uint64((elapsed + c.tick - 1) / c.tick)
+}
+
+// run calls advance once per elapsed tick until ctx is done.
+func (c clock) run(ctx context.Context, advance func(now uint64)) {
+	ticker := time.NewTicker(c.tick)
+	defer ticker.Stop()
+	var now uint64
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for target := uint64(time.Since(c.start) / c.tick); now < target; {
+				now++
+				advance(now)
+			}
+		}
+	}
+}
+
+// entry is an element of the wheel, due at a tick. Entries that are no longer
+// live are dropped instead of placed.
+type entry interface {
+	due() uint64
+	live() bool
+}
+
+// wheel is the bucket hierarchy, owned by the goroutine advancing it. New
+// entries arrive through the pending queue.
+type wheel[E entry] struct {
+	bits    int
+	mask    uint64
+	now     uint64
+	pending ring.IQueue[E]
+	buckets [Levels][][]E
+	// spare is an emptied bucket handed to the next bucket that is drained, so
+	// a wheel in steady state does not allocate
+	spare []E
+
+	expire func(bucket []E)
+	drop   func(e E)
+}
+
+func (w *wheel[E]) init(slots uint64, expire func([]E), drop func(E)) error {
+	q, err := ring.Queue[E](slots)
+	if err != nil {
+		return err
+	}
+	w.bits = bits.TrailingZeros64(slots)
+	w.mask = slots - 1
+	w.pending = q
+	w.expire = expire
+	w.drop = drop
+	for i := range w.buckets {
+		w.buckets[i] = make([][]E, slots)
+	}
+	return nil
+}
+
+// advance moves the wheel on to tick now: new entries are placed, the buckets
+// of the upper levels whose turn came are cascaded down and the current
+// bucket of level 0 expires.
+func (w *wheel[E]) advance(now uint64) {
+	w.now = now
+	for e, ok := w.pending.Dequeue(); ok; e, ok = w.pending.Dequeue() {
+		w.place(e)
+	}
+	for level := Levels - 1; level > 0; level-- {
+		if now&(1<<(w.bits*level)-1) != 0 {
+			continue
+		}
+		entries := w.take(level, now>>(w.bits*level)&w.mask)
+		for _, e := range entries {
+			w.place(e)
+		}
+		w.release(entries)
+	}
+	entries := w.take(0, now&w.mask)
+	if len(entries) > 0 {
+		w.expire(entries)
+	}
+	w.release(entries)
+}
+
+// place puts e into the lowest level whose range covers its deadline. Entries
+// already due join the current bucket of level 0.
+func (w *wheel[E]) place(e E) {
+	if !e.live() {
+		w.drop(e)
+		return
+	}
+	deadline := max(e.due(), w.now)
+	delta, level := deadline-w.now, 0
+	for level < Levels-1 && delta >= 1<<(w.bits*(level+1)) {
+		level++
+	}
+	slot := deadline >> (w.bits * level) & w.mask
+	w.buckets[level][slot] = append(w.buckets[level][slot], e)
+}
+
+func (w *wheel[E]) take(level int, slot uint64) []E {
+	res := w.buckets[level][slot]
+	w.buckets[level][slot], w.spare = w.spare, nil
+	return res
+}
+
+func (w *wheel[E]) release(entries []E) {
+	clear(entries)
+	if w.spare == nil {
+		w.spare = entries[:0]
+	}
+}
@@ -0,0 +1,92 @@
+package timerwheel
+
+import (
+	"context"
+	"github.com/dk-open/ring"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWheel_Schedule(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	exec, err := ring.Disruptor[func()](ctx, 64, func(fn func()) { fn() })
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	// Four slots per level make timers cascade through the levels
+	w, err := New(ctx, time.Millisecond, 4, exec)
+	if err != nil {
+		t.Fatalf("Failed to create wheel: %v", err)
+	}
+
+	var mu sync.Mutex
+	fired := map[int]time.Duration{}
+	start := time.Now()
+	delays := []int{1, 3, 7, 20, 45, 90}
+	for _, ms := range delays {
+		if _, err := w.Schedule(time.Duration(ms)*time.Millisecond, func() {
+			mu.Lock()
+			fired[ms] = time.Since(start)
+			mu.Unlock()
+		}); err != nil {
+			t.Fatalf("Schedule failed: %v", err)
+		}
+	}
+	cancelled, err := w.Schedule(30*time.Millisecond, func() { t.Error("Expected a cancelled timer not to fire") })
+	if err != nil {
+		t.Fatalf("Schedule failed: %v", err)
+	}
+	if !cancelled.Cancel() {
+		t.Fatal("Expected Cancel to stop a pending timer")
+	}
+
+	time.Sleep(150 * time.Millisecond)
+	mu.Lock()
+	defer mu.Unlock()
+	for _, ms := range delays {
+		got, ok := fired[ms]
+		if !ok {
+			t.Errorf("Timer of %dms did not fire", ms)
+			continue
+		}
+		if got < time.Duration(ms)*time.Millisecond {
+			t.Errorf("Timer of %dms fired early after %v", ms, got)
+		}
+	}
+	if st := w.Stats(); st.Fired != uint64(len(delays)) || st.Cancelled != 1 {
+		t.Errorf("Unexpected stats %+v", st)
+	}
+	if cancelled.Cancel() {
+		t.Error("Expected a second Cancel to fail")
+	}
+}
+
+func TestWheel_ClosedExecutor(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	exec, err := ring.Disruptor[func()](ctx, 8, func(fn func()) { fn() })
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	w, err := New(ctx, time.Millisecond, 4, exec)
+	if err != nil {
+		t.Fatalf("Failed to create wheel: %v", err)
+	}
+	if err := exec.Close(context.Background()); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if _, err := w.Schedule(time.Millisecond, func() { t.Error("Expected the timer not to run") }); err != nil {
+		t.Fatalf("Schedule failed: %v", err)
+	}
+	deadline := time.Now().Add(time.Second)
+	for w.Stats().Dropped == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if st := w.Stats(); st.Dropped != 1 || st.Fired != 0 {
+		t.Errorf("Expected one dropped timer, got %+v", st)
+	}
+}
@@ -0,0 +1,230 @@
+// Package topic is a publish/subscribe layer over disruptors. Every topic is
+// a disruptor of its own, created when it is first published or subscribed
+// to, and every subscription is a reader on the topics it matches.
+package topic
+
+import (
+	"context"
+	"github.com/dk-open/ring"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Option configures the topics created by New.
+type Option func(*options)
+
+type options struct {
+	defaults []ring.Option
+	configs  []topicOptions
+}
+
+type topicOptions struct {
+	pattern string
+	opts    []ring.Option
+}
+
+// WithDefaults configures the disruptor of every topic, e.g. its capacity or
+// wait strategy.
+func WithDefaults(opts ...ring.Option) Option {
+	return func(o *options) {
+		o.defaults = append(o.defaults, opts...)
+	}
+}
+
+// WithTopic configures the disruptors of the topics matching pattern on top
+// of the defaults. The first matching pattern applies.
+func WithTopic(pattern string, opts ...ring.Option) Option {
+	return func(o *options) {
+		o.configs = append(o.configs, topicOptions{pattern: pattern, opts: opts})
+	}
+}
+
+// Match reports whether the topic name matches pattern. A pattern ending in
+// "*" matches every topic with the prefix before it, so "*" matches all
+// topics; any other pattern matches only the topic of that name.
+func Match(pattern, name string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return strings.HasPrefix(name, prefix)
+	}
+	return pattern == name
+}
+
+// Callback receives the events of a subscription with the topic they were
+// published to.
+type Callback[T any] func(topic string, v T)
+
+// Topics is a set of topics carrying events of type T.
+type Topics[T any] struct {
+	ctx context.Context
+	options
+	mu sync.Mutex
+	// topics is copy-on-write so Publish finds existing topics without the lock
+	topics atomic.Pointer[map[string]ring.IDisruptor[T]]
+	subs   []*Subscription[T]
+	closed bool
+}
+
+// Subscription is a subscriber attached to every topic matching its pattern,
+// including topics created after it subscribed.
+type Subscription[T any] struct {
+	t       *Topics[T]
+	pattern string
+	cb      Callback[T]
+	readers map[string]ring.ReaderHandle[T]
+}
+
+// New creates an empty set of topics whose disruptors run until ctx is done.
+func New[T any](ctx context.Context, opts ...Option) *Topics[T] {
+	t := &Topics[T]{ctx: ctx}
+	for _, opt := range opts {
+		opt(&t.options)
+	}
+	t.topics.Store(&map[string]ring.IDisruptor[T]{})
+	return t
+}
+
+// Publish sends v to the subscribers of topic, waiting while the topic's ring
+// is full. Events of a topic without subscribers are not retained.
+func (t *Topics[T]) Publish(topic string, v T) error {
+	d, err := t.topic(topic)
+	if err != nil {
+		return err
+	}
+	return d.MustEnqueue(v)
+}
+
+// TryPublish sends v to the subscribers of topic unless its ring is full.
+func (t *Topics[T]) TryPublish(topic string, v T) bool {
+	d, err := t.topic(topic)
+	return err == nil && d.Enqueue(v)
+}
+
+// Subscribe calls cb on the reader goroutine of every topic matching pattern,
+// see Match, for the events published from now on. A topic named by a plain
+// pattern is created right away.
+func (t *Topics[T]) Subscribe(pattern string, cb Callback[T]) (*Subscription[T], error) {
+	if !strings.HasSuffix(pattern, "*") {
+		if _, err := t.topic(pattern); err != nil {
+			return nil, err
+		}
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		return nil, ring.ErrClosed
+	}
+	s := &Subscription[T]{t: t, pattern: pattern, cb: cb, readers: map[string]ring.ReaderHandle[T]{}}
+	for name, d := range *t.topics.Load() {
+		if Match(pattern, name) {
+			if err := s.attach(name, d); err != nil {
+				s.detach()
+				return nil, err
+			}
+		}
+	}
+	t.subs = append(t.subs, s)
+	return s, nil
+}
+
+// Topics returns the names of the topics created so far.
+func (t *Topics[T]) Topics() []string {
+	topics := *t.topics.Load()
+	res := make([]string, 0, len(topics))
+	for name := range topics {
+		res = append(res, name)
+	}
+	return res
+}
+
+// Close closes every topic, waiting until the subscribers consumed the
+// published events or ctx is done.
+func (t *Topics[T]) Close(ctx context.Context) error {
+	t.mu.Lock()
+	t.closed = true
+	t.mu.Unlock()
+	var res error
+	for _, d := range *t.topics.Load() {
+		if err := d.Close(ctx); err != nil && res == nil {
+			res = err
+		}
+	}
+	return res
+}
+
+// topic returns the disruptor of the named topic, creating it with the
+// readers of the matching subscriptions on first use.
+func (t *Topics[T]) topic(name string) (ring.IDisruptor[T], error) {
+	if d, ok := (*t.topics.Load())[name]; ok {
+		return d, nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	topics := *t.topics.Load()
+	if d, ok := topics[name]; ok {
+		return d, nil
+	}
+	if t.closed {
+		return nil, ring.ErrClosed
+	}
+	d, err := ring.NewDisruptor[T](t.ctx, append(append([]ring.Option{ring.WithName(name)}, t.defaults...), t.configure(name)...)...)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range t.subs {
+		if Match(s.pattern, name) {
+			if err = s.attach(name, d); err != nil {
+				_ = d.Close(t.ctx)
+				return nil, err
+			}
+		}
+	}
+	next := make(map[string]ring.IDisruptor[T], len(topics)+1)
+	for k, v := range topics {
+		next[k] = v
+	}
+	next[name] = d
+	t.topics.Store(&next)
+	return d, nil
+}
+
+func (t *Topics[T]) configure(name string) []ring.Option {
+	for _, c := range t.configs {
+		if Match(c.pattern, name) {
+			return c.opts
+		}
+	}
+	return nil
+}
+
+// Unsubscribe detaches the subscription from its topics. The callback is not
+// called for events published afterwards, a call in progress completes.
+func (s *Subscription[T]) Unsubscribe() {
+	s.t.mu.Lock()
+	defer s.t.mu.Unlock()
+	for i, sub := range s.t.subs {
+		if sub == s {
+			s.t.subs = append(s.t.subs[:i], s.t.subs[i+1:]...)
+			break
+		}
+	}
+	s.detach()
+}
+
+// attach adds the subscriber as a reader of the topic, called with the lock
+// of the topics held.
+func (s *Subscription[T]) attach(name string, d ring.IDisruptor[T]) error {
+	h, err := d.AddReader(func(v T) { s.cb(name, v) })
+	if err != nil {
+		return err
+	}
+	s.readers[name] = h
+	return nil
+}
+
+func (s *Subscription[T]) detach() {
+	for name, h := range s.readers {
+		h.Close()
+		delete(s.readers, name)
+	}
+}
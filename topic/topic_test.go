@@ -0,0 +1,162 @@
+package topic
+
+import (
+	"context"
+	"errors"
+	"github.com/dk-open/ring"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+type received struct {
+	mu  sync.Mutex
+	got []string
+}
+
+func (r *received) add(topic string, v int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.got = append(r.got, topic)
+}
+
+func (r *received) wait(t *testing.T, n int) []string {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for {
+		r.mu.Lock()
+		got := slices.Clone(r.got)
+		r.mu.Unlock()
+		if len(got) >= n {
+			slices.Sort(got)
+			return got
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Timed out with %v", got)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestTopics_Subscribe(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	topics := New[int](ctx, WithDefaults(ring.WithCapacity(16)))
+	var exact, prefix, all received
+	if _, err := topics.Subscribe("orders.eu", exact.add); err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+	if _, err := topics.Subscribe("orders.*", prefix.add); err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+	if _, err := topics.Subscribe("*", all.add); err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+	// orders.us and fills are created on demand after the subscriptions
+	for _, name := range []string{"orders.eu", "orders.us", "fills"} {
+		if err := topics.Publish(name, 1); err != nil {
+			t.Fatalf("Publish failed: %v", err)
+		}
+	}
+	if got := exact.wait(t, 1); !slices.Equal(got, []string{"orders.eu"}) {
+		t.Errorf("Unexpected exact subscription events %v", got)
+	}
+	if got := prefix.wait(t, 2); !slices.Equal(got, []string{"orders.eu", "orders.us"}) {
+		t.Errorf("Unexpected prefix subscription events %v", got)
+	}
+	if got := all.wait(t, 3); !slices.Equal(got, []string{"fills", "orders.eu", "orders.us"}) {
+		t.Errorf("Unexpected wildcard subscription events %v", got)
+	}
+}
+
+func TestTopics_Unsubscribe(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	topics := New[int](ctx)
+	var r received
+	s, err := topics.Subscribe("a*", r.add)
+	if err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+	if err := topics.Publish("a1", 1); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+	r.wait(t, 1)
+	s.Unsubscribe()
+	// Neither existing nor new topics deliver to a cancelled subscription
+	for _, name := range []string{"a1", "a2"} {
+		if err := topics.Publish(name, 2); err != nil {
+			t.Fatalf("Publish failed: %v", err)
+		}
+	}
+	time.Sleep(20 * time.Millisecond)
+	if got := r.wait(t, 1); len(got) != 1 {
+		t.Errorf("Expected no events after Unsubscribe, got %v", got)
+	}
+}
+
+func TestTopics_Configure(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	topics := New[int](ctx,
+		WithDefaults(ring.WithCapacity(16)),
+		WithTopic("bulk.*", ring.WithCapacity(1024)),
+		WithTopic("bad", ring.WithCapacity(3)),
+	)
+	if err := topics.Publish("bulk.1", 1); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+	if err := topics.Publish("bad", 1); !errors.Is(err, ring.ErrCapacity) {
+		t.Errorf("Expected the topic configuration to apply, got %v", err)
+	}
+	if got := topics.Topics(); !slices.Equal(got, []string{"bulk.1"}) {
+		t.Errorf("Expected only the valid topic to exist, got %v", got)
+	}
+}
+
+func TestTopics_Close(t *testing.T) {
+	topics := New[int](context.Background())
+	var r received
+	if _, err := topics.Subscribe("t", r.add); err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+	for i := 0; i < 100; i++ {
+		if err := topics.Publish("t", i); err != nil {
+			t.Fatalf("Publish failed: %v", err)
+		}
+	}
+	if err := topics.Close(context.Background()); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if got := r.wait(t, 100); len(got) != 100 {
+		t.Errorf("Expected every event before Close returned, got %d", len(got))
+	}
+	if err := topics.Publish("t", 1); !errors.Is(err, ring.ErrClosed) {
+		t.Errorf("Expected ErrClosed, got %v", err)
+	}
+	if _, err := topics.Subscribe("u", r.add); !errors.Is(err, ring.ErrClosed) {
+		t.Errorf("Expected ErrClosed, got %v", err)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	for _, tc := range []struct {
+		pattern, name string
+		want          bool
+	}{
+		{"a", "a", true},
+		{"a", "ab", false},
+		{"a*", "ab", true},
+		{"a*", "a", true},
+		{"*", "anything", true},
+		{"b*", "ab", false},
+	} {
+		if got := Match(tc.pattern, tc.name); got != tc.want {
+			t.Errorf("Match(%q, %q) = %v, want %v", tc.pattern, tc.name, got, tc.want)
+		}
+	}
+}
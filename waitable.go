@@ -0,0 +1,66 @@
+package ring
+
+import (
+	"context"
+	"github.com/dk-open/ring/pad"
+)
+
+// WaitableBarrier waits for a barrier to reach a sequence with the spin, yield
+// and sleep backoff of the disruptor's readers, e.g. for a stage that must not
+// run ahead of another or a shutdown draining a ring.
+type WaitableBarrier struct {
+	barrier pad.Barrier
+	idle    idleBackoff
+	// wake cuts a sleep short, nil if nothing signals progress
+	wake func() <-chan struct{}
+}
+
+// NewWaitableBarrier waits on barrier with the default reader backoff.
+func NewWaitableBarrier(barrier pad.Barrier) *WaitableBarrier {
+	return &WaitableBarrier{barrier: barrier, idle: defaultIdle}
+}
+
+func (w *WaitableBarrier) Load() uint64 {
+	return w.barrier.Load()
+}
+
+// WaitFor blocks until the barrier reaches seq and returns the value it
+// reached, which may be past seq. It fails with the context's error once ctx
+// is done, returning the last value seen.
+func (w *WaitableBarrier) WaitFor(ctx context.Context, seq uint64) (uint64, error) {
+	for attempt := uint64(0); ; attempt++ {
+		if v := w.barrier.Load(); v >= seq {
+			return v, nil
+		} else if err := ctx.Err(); err != nil {
+			return v, err
+		}
+		var wake <-chan struct{}
+		if w.wake != nil {
+			wake = w.wake()
+		}
+		if wake == nil {
+			wake = ctx.Done()
+		}
+		w.idle.park(attempt, wake)
+	}
+}
+
+// waitable waits on barrier with the disruptor's backoff, woken by its
+// producers if priority inheritance or blocking wait is enabled.
+func (d *disruptor[T]) waitable(barrier pad.Barrier) *WaitableBarrier {
+	return &WaitableBarrier{barrier: barrier, idle: d.idle, wake: d.wakeChan}
+}
+
+func (d *disruptor[T]) wakeChan() <-chan struct{} {
+	if ch := d.wake.Load(); ch != nil {
+		return *ch
+	}
+	return nil
+}
+
+// WaitFor blocks until every reader of the group has processed the first seq
+// events and returns the number processed, or fails once ctx is done.
+func (g *ReaderGroup[T]) WaitFor(ctx context.Context, seq uint64) (uint64, error) {
+	v, err := g.d.waitable(g.Barrier()).WaitFor(ctx, 2*seq)
+	return v >> 1, err
+}
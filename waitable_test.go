@@ -0,0 +1,108 @@
+package ring
+
+import (
+	"context"
+	"errors"
+	"github.com/dk-open/ring/pad"
+	"testing"
+	"time"
+)
+
+func TestWaitableBarrier_WaitFor(t *testing.T) {
+	var seq pad.AtomicUint64
+	w := NewWaitableBarrier(&seq)
+	go func() {
+		for i := 0; i < 10; i++ {
+			time.Sleep(time.Millisecond)
+			seq.Add(2)
+		}
+	}()
+	v, err := w.WaitFor(context.Background(), 14)
+	if err != nil {
+		t.Fatalf("WaitFor failed: %v", err)
+	}
+	if v < 14 {
+		t.Errorf("Expected at least 14, got %d", v)
+	}
+}
+
+func TestWaitableBarrier_Cancelled(t *testing.T) {
+	w := NewWaitableBarrier(pad.ConstBarrier(4))
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	v, err := w.WaitFor(ctx, 6)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected DeadlineExceeded, got %v", err)
+	}
+	if v != 4 {
+		t.Errorf("Expected the last value 4, got %d", v)
+	}
+}
+
+func TestReaderGroup_WaitFor(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	d, err := NewDisruptor[int](ctx, WithCapacity(64))
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	release := make(chan struct{})
+	g := d.HandleWith(func(value int) {
+		if value == 0 {
+			<-release
+		}
+	})
+	for i := 0; i < 20; i++ {
+		if err := d.MustEnqueue(i); err != nil {
+			t.Fatalf("MustEnqueue failed: %v", err)
+		}
+	}
+	short, cancelShort := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancelShort()
+	if _, err := g.WaitFor(short, 20); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected the held reader to time out, got %v", err)
+	}
+	close(release)
+	n, err := g.WaitFor(ctx, 20)
+	if err != nil {
+		t.Fatalf("WaitFor failed: %v", err)
+	}
+	if n != 20 {
+		t.Errorf("Expected 20 processed events, got %d", n)
+	}
+}
+
+func TestDisruptor_WaitDrained(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	d, err := NewDisruptor[int](ctx, WithCapacity(64))
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	release := make(chan struct{})
+	d.HandleWith(func(value int) {
+		if value == 0 {
+			<-release
+		}
+	})
+	d.HandleWithWorkerPool(func(int) {}, func(int) {})
+	for i := 0; i < 20; i++ {
+		if err := d.MustEnqueue(i); err != nil {
+			t.Fatalf("MustEnqueue failed: %v", err)
+		}
+	}
+	short, cancelShort := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancelShort()
+	if err := d.WaitDrained(short); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected the held reader to time out, got %v", err)
+	}
+	close(release)
+	if err := d.WaitDrained(ctx); err != nil {
+		t.Fatalf("WaitDrained failed: %v", err)
+	}
+	if released, published := d.Released(), d.Published(); released != published {
+		t.Errorf("Expected every event to be released, %d of %d", released, published)
+	}
+}
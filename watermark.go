@@ -0,0 +1,110 @@
+package ring
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync/atomic"
+)
+
+var ErrWatermarks = fmt.Errorf("watermarks must satisfy 0 <= low < high <= 1")
+
+// Pressure is the occupancy state reported to a watermark callback.
+type Pressure int
+
+const (
+	// Drained is reported once occupancy falls below the low watermark, or
+	// once the ring is empty for a low watermark of 0.
+	Drained Pressure = iota
+	// Saturated is reported once occupancy reaches the high watermark.
+	Saturated
+)
+
+// watermarks tracks occupancy with hysteresis. The goroutine observing a
+// crossing reports it, each crossing is reported exactly once. An empty ring
+// is always drained, so a low watermark of 0 reports Drained at 0.
+type watermarks struct {
+	high, low uint64
+	fn        func(state Pressure)
+	state     atomic.Int32
+}
+
+func newWatermarks(high, low float64, fn func(Pressure), capacity uint64) (*watermarks, error) {
+	if !(0 <= low && low < high && high <= 1) || fn == nil {
+		return nil, ErrWatermarks
+	}
+	return &watermarks{
+		high: uint64(math.Ceil(high * float64(capacity))),
+		low:  uint64(math.Ceil(low * float64(capacity))),
+		fn:   fn,
+	}, nil
+}
+
+// check reports a crossing for used occupied slots.
+func (w *watermarks) check(used uint64) {
+	switch {
+	case used >= w.high:
+		if w.state.CompareAndSwap(int32(Drained), int32(Saturated)) {
+			w.fn(Saturated)
+		}
+	case used < w.low || used == 0:
+		if w.state.CompareAndSwap(int32(Saturated), int32(Drained)) {
+			w.fn(Drained)
+		}
+	}
+}
+
+type watermarkOptions struct {
+	high, low float64
+	fn        func(Pressure)
+}
+
+// WithWatermarks calls fn with Saturated when the ring's occupancy reaches
+// high and with Drained once it falls below low again, both given as
+// fractions of the capacity; a low of 0 reports Drained once the ring is
+// empty. It lets producers shed load upstream before the ring is full. fn runs
+// on the producer or reader goroutine observing the crossing and must not
+// block. Crossings observed by different goroutines may invoke fn
+// concurrently, so a Drained can arrive before the Saturated it follows;
+// callers tracking the state should serialize fn themselves.
+func WithWatermarks(high, low float64, fn func(state Pressure)) Option {
+	return func(o *options) {
+		o.watermarks = &watermarkOptions{high: high, low: low, fn: fn}
+	}
+}
+
+// QueueOption configures a queue created by Queue.
+type QueueOption func(*queueOptions)
+
+type queueOptions struct {
+	watermarks    *watermarkOptions
+	buffer        any
+	slotPadding   bool
+	slotSequences bool
+	retry         retryPolicy
+	ctx           context.Context
+	latency       bool
+	dropHandler   any
+	shardScaling  *shardScaling
+}
+
+// WithQueueWatermarks is WithWatermarks for queues.
+func WithQueueWatermarks(high, low float64, fn func(state Pressure)) QueueOption {
+	return func(o *queueOptions) {
+		o.watermarks = &watermarkOptions{high: high, low: low, fn: fn}
+	}
+}
+
+// occupancy checks the watermarks of a disruptor against the slots not yet
+// released by its slowest gating reader.
+func (d *disruptor[T]) occupancy() {
+	if d.watermarks == nil {
+		return
+	}
+	head := d.writerCursor.Load() &^ 1
+	if barrier := d.readerBarrier.Load() &^ 1; barrier < head {
+		d.watermarks.check((head - barrier) >> 1)
+		return
+	}
+	d.watermarks.check(0)
+}
@@ -0,0 +1,85 @@
+package ring
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestWithWatermarks(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var states []Pressure
+	d, err := NewDisruptor[int](ctx,
+		WithCapacity(8),
+		WithWatermarks(0.75, 0.25, func(state Pressure) { states = append(states, state) }),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create disruptor: %v", err)
+	}
+	r := d.NewReader()
+	for i := 0; i < 8; i++ {
+		if !d.Enqueue(i) {
+			t.Fatalf("Enqueue %d failed", i)
+		}
+		if i == 4 && len(states) != 0 {
+			t.Fatalf("Expected no crossing below the high watermark, got %v", states)
+		}
+	}
+	if len(states) != 1 || states[0] != Saturated {
+		t.Fatalf("Expected a single Saturated, got %v", states)
+	}
+	for i := 0; i < 6; i++ {
+		r.Dequeue()
+	}
+	if len(states) != 1 {
+		t.Fatalf("Expected no crossing at the low watermark, got %v", states)
+	}
+	r.Dequeue()
+	if len(states) != 2 || states[1] != Drained {
+		t.Fatalf("Expected Drained below the low watermark, got %v", states)
+	}
+
+	if _, err := NewDisruptor[int](ctx, WithWatermarks(0.5, 0.5, func(Pressure) {})); !errors.Is(err, ErrWatermarks) {
+		t.Errorf("Expected ErrWatermarks, got %v", err)
+	}
+}
+
+func TestQueue_Watermarks(t *testing.T) {
+	var states []Pressure
+	q, err := Queue[int](4, WithQueueWatermarks(1, 0.5, func(state Pressure) { states = append(states, state) }))
+	if err != nil {
+		t.Fatalf("Failed to create queue: %v", err)
+	}
+	for i := 0; i < 4; i++ {
+		q.Enqueue(i)
+	}
+	q.Dequeue()
+	q.Dequeue()
+	q.Dequeue()
+	if len(states) != 2 || states[0] != Saturated || states[1] != Drained {
+		t.Errorf("Expected Saturated then Drained, got %v", states)
+	}
+	if _, err := Queue[int](4, WithQueueWatermarks(1.5, 0, func(Pressure) {})); !errors.Is(err, ErrWatermarks) {
+		t.Errorf("Expected ErrWatermarks, got %v", err)
+	}
+}
+
+func TestQueue_WatermarksZeroLow(t *testing.T) {
+	var states []Pressure
+	q, err := Queue[int](4, WithQueueWatermarks(0.5, 0, func(state Pressure) { states = append(states, state) }))
+	if err != nil {
+		t.Fatalf("Failed to create queue: %v", err)
+	}
+	q.Enqueue(1)
+	q.Enqueue(2)
+	q.Dequeue()
+	if len(states) != 1 || states[0] != Saturated {
+		t.Fatalf("Expected a single Saturated, got %v", states)
+	}
+	q.Dequeue()
+	if len(states) != 2 || states[1] != Drained {
+		t.Errorf("Expected Drained once empty, got %v", states)
+	}
+}
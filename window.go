@@ -0,0 +1,140 @@
+package ring
+
+import (
+	"github.com/dk-open/ring/pad"
+	"sync/atomic"
+	"time"
+)
+
+// Window keeps the most recent samples in a ring, e.g. for rolling latency
+// quantiles or rates. Any number of goroutines may Add concurrently, writers
+// only contend when one laps another on the same slot.
+type Window[T any] struct {
+	slots []windowSlot[T]
+	mask  uint64
+	head  pad.AtomicUint64
+
+	start  time.Time
+	maxAge time.Duration
+}
+
+// windowSlot holds sample i once seq is 2*(i+1). An odd seq marks the slot as
+// being written or copied out.
+type windowSlot[T any] struct {
+	seq   atomic.Uint64
+	at    time.Duration
+	value T
+}
+
+// WindowOption configures a window created by NewWindow.
+type WindowOption func(*windowOptions)
+
+type windowOptions struct {
+	maxAge time.Duration
+}
+
+// WithWindowDuration additionally drops samples older than d from the window.
+func WithWindowDuration(d time.Duration) WindowOption {
+	return func(o *windowOptions) {
+		o.maxAge = d
+	}
+}
+
+// NewWindow creates a window of the last size samples, a power of two.
+func NewWindow[T any](size uint64, opts ...WindowOption) (*Window[T], error) {
+	if err := validCapacity(size); err != nil {
+		return nil, err
+	}
+	var o windowOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &Window[T]{
+		slots:  make([]windowSlot[T], size),
+		mask:   size - 1,
+		start:  time.Now(),
+		maxAge: o.maxAge,
+	}, nil
+}
+
+// Add records v as the newest sample.
+func (w *Window[T]) Add(v T) {
+	i := w.head.Add(1) - 1
+	slot := &w.slots[i&w.mask]
+	committed := 2 * (i + 1)
+	for attempt := uint64(0); ; attempt++ {
+		seq := slot.seq.Load()
+		if seq >= committed {
+			// A writer a full ring ahead already replaced the sample
+			return
+		}
+		if seq&1 == 0 && slot.seq.CompareAndSwap(seq, committed-1) {
+			break
+		}
+		readerYield(attempt)
+	}
+	if w.maxAge > 0 {
+		slot.at = time.Since(w.start)
+	}
+	slot.value = v
+	slot.seq.Store(committed)
+}
+
+// Snapshot returns the samples in the window from oldest to newest.
+func (w *Window[T]) Snapshot() []T {
+	var res []T
+	w.each(func(v T) {
+		res = append(res, v)
+	})
+	return res
+}
+
+// Reduce folds the samples in the window from oldest to newest into init.
+func (w *Window[T]) Reduce(init T, fn func(acc, v T) T) T {
+	w.each(func(v T) {
+		init = fn(init, v)
+	})
+	return init
+}
+
+// each copies every sample still in the window out of its slot and passes it
+// to fn. Samples replaced while being visited are skipped.
+func (w *Window[T]) each(fn func(v T)) {
+	head := w.head.Load()
+	first := uint64(0)
+	if size := uint64(len(w.slots)); head > size {
+		first = head - size
+	}
+	var oldest time.Duration
+	if w.maxAge > 0 {
+		oldest = time.Since(w.start) - w.maxAge
+	}
+	for i := first; i < head; i++ {
+		if v, ok := w.load(i, oldest); ok {
+			fn(v)
+		}
+	}
+}
+
+// load copies sample i out of its slot unless it is older than oldest, not
+// written yet or already replaced.
+func (w *Window[T]) load(i uint64, oldest time.Duration) (v T, ok bool) {
+	slot := &w.slots[i&w.mask]
+	committed := 2 * (i + 1)
+	for attempt := uint64(0); ; attempt++ {
+		seq := slot.seq.Load()
+		if seq == committed && slot.seq.CompareAndSwap(seq, seq+1) {
+			break
+		}
+		if seq != committed && seq != committed+1 && seq != committed-1 {
+			return v, false
+		}
+		// Being written or copied by another reader
+		readerYield(attempt)
+	}
+	if slot.at >= oldest {
+		v, ok = slot.value, true
+	}
+	slot.seq.Store(committed)
+	return v, ok
+}
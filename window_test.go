@@ -0,0 +1,93 @@
+package ring
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWindow_KeepsLastSamples(t *testing.T) {
+	w, err := NewWindow[int](4)
+	if err != nil {
+		t.Fatalf("Failed to create window: %v", err)
+	}
+	if got := w.Snapshot(); len(got) != 0 {
+		t.Fatalf("Expected an empty window, got %v", got)
+	}
+	for i := 1; i <= 10; i++ {
+		w.Add(i)
+	}
+	got := w.Snapshot()
+	want := []int{7, 8, 9, 10}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Expected %v, got %v", want, got)
+		}
+	}
+	if sum := w.Reduce(0, func(acc, v int) int { return acc + v }); sum != 34 {
+		t.Errorf("Expected sum 34, got %d", sum)
+	}
+}
+
+func TestWindow_Duration(t *testing.T) {
+	w, err := NewWindow[int](8, WithWindowDuration(20*time.Millisecond))
+	if err != nil {
+		t.Fatalf("Failed to create window: %v", err)
+	}
+	w.Add(1)
+	time.Sleep(40 * time.Millisecond)
+	w.Add(2)
+	if got := w.Snapshot(); len(got) != 1 || got[0] != 2 {
+		t.Errorf("Expected only the recent sample, got %v", got)
+	}
+}
+
+func TestWindow_InvalidSize(t *testing.T) {
+	if _, err := NewWindow[int](3); err == nil {
+		t.Error("Expected an error for a size that is not a power of two")
+	}
+}
+
+func TestWindow_Concurrent(t *testing.T) {
+	w, err := NewWindow[int](64)
+	if err != nil {
+		t.Fatalf("Failed to create window: %v", err)
+	}
+	var wg sync.WaitGroup
+	for p := 0; p < 4; p++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 10000; i++ {
+				w.Add(i)
+			}
+		}()
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 1000; i++ {
+			if n := len(w.Snapshot()); n > 64 {
+				t.Errorf("Expected at most 64 samples, got %d", n)
+				return
+			}
+		}
+	}()
+	wg.Wait()
+	<-done
+	if n := len(w.Snapshot()); n != 64 {
+		t.Errorf("Expected a full window after the writers finished, got %d", n)
+	}
+}
+
+func BenchmarkWindow_Add(b *testing.B) {
+	w, _ := NewWindow[time.Duration](1024)
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			w.Add(time.Microsecond)
+		}
+	})
+}